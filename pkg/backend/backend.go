@@ -257,8 +257,8 @@ type UpdateOptions struct {
 	AutoApprove bool
 	// SkipPreview, when true, causes the preview step to be skipped.
 	SkipPreview bool
-	// Experimental plan support, when true cause plans to be generated.
-	ExperimentalPlans bool
+	// PreviewOnly, when true, causes only the preview step to be run, without a prompt.
+	PreviewOnly bool
 }
 
 // QueryOptions configures a query to operate against a backend and the engine.
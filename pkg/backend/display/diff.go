@@ -213,6 +213,10 @@ func renderSummaryEvent(action apitype.UpdateKind, event engine.SummaryEventPayl
 		summaryPieces = append(summaryPieces, fmt.Sprintf("%d unchanged", sameCount))
 	}
 
+	if event.Failures != 0 {
+		summaryPieces = append(summaryPieces, fmt.Sprintf("%s%d failed%s", colors.SpecError, event.Failures, colors.Reset))
+	}
+
 	if len(summaryPieces) > 0 {
 		fprintfIgnoreError(out, "    ")
 
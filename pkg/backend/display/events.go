@@ -95,6 +95,7 @@ func ConvertEngineEvent(e engine.Event) (apitype.EngineEvent, error) {
 			MaybeCorrupt:    p.MaybeCorrupt,
 			DurationSeconds: int(p.Duration.Seconds()),
 			ResourceChanges: changes,
+			Failures:        p.Failures,
 			PolicyPacks:     p.PolicyPacks,
 		}
 
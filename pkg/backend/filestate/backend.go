@@ -24,6 +24,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -54,6 +55,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/fsutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -61,7 +63,47 @@ import (
 // Backend extends the base backend interface with specific information about local backends.
 type Backend interface {
 	backend.Backend
-	local() // at the moment, no local specific info, so just use a marker function.
+	local() // a marker function to identify local backends.
+
+	// BreakStaleLocks deletes the locks on the given stack whose leases have expired, leaving locks that are still
+	// held by running processes in place. It returns the number of locks that were deleted.
+	BreakStaleLocks(ctx context.Context, stackRef backend.StackReference) (int, error)
+
+	// Upgrade moves the backend's stacks into the project-scoped layout, where stacks of different projects may share
+	// a name, and returns the stacks that it moved. The projects of stacks without resources must be given, keyed by
+	// stack name. Buckets are only upgraded automatically when they are opened if PULUMI_FILESTATE_UPGRADE_LAYOUT is
+	// set.
+	Upgrade(ctx context.Context, projects map[tokens.Name]tokens.Name) ([]StackMove, error)
+	// Downgrade moves the backend's stacks back into the legacy layout, where stacks are identified by name alone.
+	// It fails if stacks of different projects share a name.
+	Downgrade(ctx context.Context) error
+
+	// AddStackToPolicyGroup adds a stack to a policy group, creating the group if it does not exist. The policy packs
+	// enabled for the group are enforced on every update of the stack. Every stack belongs to the default group.
+	AddStackToPolicyGroup(ctx context.Context, policyGroup string, stackRef backend.StackReference) error
+	// RemoveStackFromPolicyGroup removes a stack from a policy group.
+	RemoveStackFromPolicyGroup(ctx context.Context, policyGroup string, stackRef backend.StackReference) error
+
+	// PruneHistory removes the history entries and checkpoint backups of a stack that the retention policy does not
+	// keep. It returns the number of history entries and backups that were removed.
+	PruneHistory(ctx context.Context, stackRef backend.StackReference, retention HistoryRetention) (int, int, error)
+	// CompressHistory gzips the history checkpoints and backups of a stack that were saved uncompressed. It returns
+	// the number of files that were compressed.
+	CompressHistory(ctx context.Context, stackRef backend.StackReference) (int, error)
+
+	// ImportHistoryEntry appends an update, along with the deployment it resulted in, to the history of a stack. It is
+	// used to record changes that are not made by the engine, such as copying a stack from another backend along with
+	// its history or rotating a stack's secrets.
+	ImportHistoryEntry(ctx context.Context, stackRef backend.StackReference, update backend.UpdateInfo,
+		deployment *apitype.UntypedDeployment) error
+	// HistoryCheckpoints returns the deployments saved in the history checkpoints and checkpoint backups of a stack,
+	// keyed by the files that hold them.
+	HistoryCheckpoints(ctx context.Context, stackRef backend.StackReference) (map[string]*apitype.UntypedDeployment,
+		error)
+	// ReplaceHistoryCheckpoints replaces the deployments saved in history checkpoints and checkpoint backups of a
+	// stack, keyed as HistoryCheckpoints returns them. It is used to re-encrypt them when a stack's key is rotated.
+	ReplaceHistoryCheckpoints(ctx context.Context, stackRef backend.StackReference,
+		deployments map[string]*apitype.UntypedDeployment) error
 }
 
 type localBackend struct {
@@ -76,21 +118,63 @@ type localBackend struct {
 	bucket Bucket
 	mutex  sync.Mutex
 
-	lockID string
+	// stackPaths remembers the paths of the stacks' checkpoints, which may be compressed or not, keyed by the path of
+	// the uncompressed checkpoint. Only saving, moving or removing a stack changes its path.
+	stackPaths sync.Map
+
+	// meta describes the layout of the stacks in the bucket.
+	meta pulumiMeta
+	// currentProject is the project in the current working directory, if any. Stack references that do not name a
+	// project refer to stacks of this project.
+	currentProject *workspace.Project
+
+	lockID      string
+	leases      map[string]*lockLease // the leases of the locks held by this backend, keyed by stack path.
+	leasesMutex sync.Mutex
 }
 
+// Assert we implement the backend.SpecificDeploymentExporter interface.
+var _ backend.SpecificDeploymentExporter = &localBackend{}
+
+// localOrganization is the organization of every stack in a local backend, which has no organizations of its own.
+// It may appear in fully qualified stack references of the form organization/project/stack.
+const localOrganization = "organization"
+
 type localBackendReference struct {
 	name tokens.Name
+	// project is the project that the stack belongs to, or empty if the backend uses the legacy layout.
+	project tokens.Name
+	// currentProject is the backend's current project, if any, used to elide the project when printing references.
+	currentProject *workspace.Project
 }
 
 func (r localBackendReference) String() string {
-	return string(r.name)
+	if r.project == "" || r.currentProject != nil && tokens.Name(r.currentProject.Name) == r.project {
+		return string(r.name)
+	}
+	return fmt.Sprintf("%s/%s/%s", localOrganization, r.project, r.name)
 }
 
 func (r localBackendReference) Name() tokens.Name {
 	return r.name
 }
 
+// pathName returns the path of the stack's files relative to the directories that hold them, such as the stacks or
+// history directory.
+func (r localBackendReference) pathName() string {
+	if r.project == "" {
+		return fsutil.NamePath(r.name)
+	}
+	return path.Join(fsutil.NamePath(r.project), fsutil.NamePath(r.name))
+}
+
+// localReference returns the local reference underlying a stack reference created by this backend.
+func localReference(ref backend.StackReference) localBackendReference {
+	localRef, ok := ref.(localBackendReference)
+	contract.Assertf(ok, "expected a local stack reference, got %T", ref)
+	return localRef
+}
+
 func IsFileStateBackendURL(urlstr string) bool {
 	u, err := url.Parse(urlstr)
 	if err != nil {
@@ -151,13 +235,24 @@ func New(d diag.Sink, originalURL string) (Backend, error) {
 		return nil, err
 	}
 
-	return &localBackend{
-		d:           d,
-		originalURL: originalURL,
-		url:         u,
-		bucket:      &wrappedBucket{bucket: bucket},
-		lockID:      lockID.String(),
-	}, nil
+	// When stringifying and parsing stack references, we take the current project (if present) into account.
+	currentProject, err := workspace.DetectProject()
+	if err != nil {
+		currentProject = nil
+	}
+
+	b := &localBackend{
+		d:              d,
+		originalURL:    originalURL,
+		url:            u,
+		bucket:         &wrappedBucket{bucket: bucket},
+		currentProject: currentProject,
+		lockID:         lockID.String(),
+	}
+	if err = b.initLayout(context.TODO()); err != nil {
+		return nil, err
+	}
+	return b, nil
 }
 
 // massageBlobPath takes the path the user provided and converts it to an appropriate form go-cloud
@@ -232,37 +327,64 @@ func (b *localBackend) StateDir() string {
 	return workspace.BookkeepingDir
 }
 
-func (b *localBackend) GetPolicyPack(ctx context.Context, policyPack string,
-	d diag.Sink) (backend.PolicyPack, error) {
-
-	return nil, fmt.Errorf("File state backend does not support resource policy")
-}
-
-func (b *localBackend) ListPolicyGroups(ctx context.Context, orgName string, _ backend.ContinuationToken) (
-	apitype.ListPolicyGroupsResponse, backend.ContinuationToken, error) {
-	return apitype.ListPolicyGroupsResponse{}, nil, fmt.Errorf("File state backend does not support resource policy")
-}
-
-func (b *localBackend) ListPolicyPacks(ctx context.Context, orgName string, _ backend.ContinuationToken) (
-	apitype.ListPolicyPacksResponse, backend.ContinuationToken, error) {
-	return apitype.ListPolicyPacksResponse{}, nil, fmt.Errorf("File state backend does not support resource policy")
-}
-
 func (b *localBackend) SupportsTags() bool {
-	return false
+	return true
 }
 
 func (b *localBackend) SupportsOrganizations() bool {
 	return false
 }
 
+// ParseStackReference parses a stack reference. If the backend uses the project-scoped layout, the reference may be
+// fully qualified as organization/project/stack; otherwise it refers to a stack of the current project.
 func (b *localBackend) ParseStackReference(stackRefName string) (backend.StackReference, error) {
-	return localBackendReference{name: tokens.Name(stackRefName)}, nil
+	if !b.meta.projectScoped() {
+		if strings.Contains(stackRefName, "/") {
+			return nil, errors.New("stack references may not contain slashes unless the backend uses the " +
+				"project-scoped layout; run `pulumi state upgrade` to upgrade it")
+		}
+		return localBackendReference{name: tokens.Name(stackRefName)}, nil
+	}
+
+	ref := localBackendReference{currentProject: b.currentProject}
+	switch parts := strings.Split(stackRefName, "/"); len(parts) {
+	case 1:
+		// The project may have been created since the backend was, for instance by `pulumi new`.
+		currentProject := b.currentProject
+		if currentProject == nil {
+			project, err := workspace.DetectProject()
+			if err != nil {
+				return nil, fmt.Errorf("no current project found; pass the fully qualified stack name "+
+					"(%s/project/stack): %w", localOrganization, err)
+			}
+			currentProject = project
+		}
+		ref.project, ref.name = tokens.Name(currentProject.Name), tokens.Name(parts[0])
+	case 3:
+		if parts[0] != localOrganization {
+			return nil, fmt.Errorf("the local backend has no organizations; "+
+				"fully qualified stack names must start with '%s/'", localOrganization)
+		}
+		ref.project, ref.name = tokens.Name(parts[1]), tokens.Name(parts[2])
+	default:
+		return nil, fmt.Errorf("could not parse stack reference '%s'; expected a stack name or %s/project/stack",
+			stackRefName, localOrganization)
+	}
+	return ref, nil
 }
 
 // ValidateStackName verifies the stack name is valid for the local backend. We use the same rules as the
 // httpstate backend.
 func (b *localBackend) ValidateStackName(stackName string) error {
+	if b.meta.projectScoped() {
+		if parts := strings.Split(stackName, "/"); len(parts) == 3 && parts[0] == localOrganization {
+			if !tokens.IsName(parts[1]) {
+				return errors.New("project names may only contain alphanumeric, hyphens, underscores, or periods")
+			}
+			stackName = parts[2]
+		}
+	}
+
 	if strings.Contains(stackName, "/") {
 		return errors.New("stack names may not contain slashes")
 	}
@@ -276,7 +398,19 @@ func (b *localBackend) ValidateStackName(stackName string) error {
 }
 
 func (b *localBackend) DoesProjectExist(ctx context.Context, projectName string) (bool, error) {
-	// Local backends don't really have multiple projects, so just return false here.
+	// Backends that use the legacy layout don't really have multiple projects, so just return false here.
+	if !b.meta.projectScoped() {
+		return false, nil
+	}
+	projects, err := b.getLocalProjects()
+	if err != nil {
+		return false, err
+	}
+	for _, project := range projects {
+		if string(project) == projectName {
+			return true, nil
+		}
+	}
 	return false, nil
 }
 
@@ -291,13 +425,14 @@ func (b *localBackend) CreateStack(ctx context.Context, stackRef backend.StackRe
 
 	contract.Requiref(opts == nil, "opts", "local stacks do not support any options")
 
-	stackName := stackRef.Name()
+	ref := localReference(stackRef)
+	stackName := ref.name
 	if stackName == "" {
 		return nil, errors.New("invalid empty stack name")
 	}
 
-	if _, _, err := b.getStack(stackName); err == nil {
-		return nil, &backend.StackAlreadyExistsError{StackName: string(stackName)}
+	if _, _, err := b.getStack(ref); err == nil {
+		return nil, &backend.StackAlreadyExistsError{StackName: ref.String()}
 	}
 
 	tags, err := backend.GetEnvironmentTagsForCurrentStack()
@@ -308,48 +443,67 @@ func (b *localBackend) CreateStack(ctx context.Context, stackRef backend.StackRe
 		return nil, fmt.Errorf("validating stack properties: %w", err)
 	}
 
-	file, err := b.saveStack(stackName, nil, nil)
+	file, err := b.saveStack(ref, nil, nil)
 	if err != nil {
 		return nil, err
 	}
+	if err = b.saveStackTags(ctx, ref, tags); err != nil {
+		return nil, fmt.Errorf("saving stack tags: %w", err)
+	}
 
-	stack := newStack(stackRef, file, nil, b)
+	stack := newStack(stackRef, file, nil, tags, b)
 	fmt.Printf("Created stack '%s'\n", stack.Ref())
 
 	return stack, nil
 }
 
 func (b *localBackend) GetStack(ctx context.Context, stackRef backend.StackReference) (backend.Stack, error) {
-	stackName := stackRef.Name()
-	snapshot, path, err := b.getStack(stackName)
+	ref := localReference(stackRef)
+	snapshot, path, err := b.getStack(ref)
 
 	switch {
 	case gcerrors.Code(err) == gcerrors.NotFound:
 		return nil, nil
 	case err != nil:
 		return nil, err
-	default:
-		return newStack(stackRef, path, snapshot, b), nil
 	}
+
+	tags, err := b.getStackTags(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return newStack(stackRef, path, snapshot, tags, b), nil
 }
 
 func (b *localBackend) ListStacks(
-	ctx context.Context, _ backend.ListStacksFilter, _ backend.ContinuationToken) (
+	ctx context.Context, filter backend.ListStacksFilter, _ backend.ContinuationToken) (
 	[]backend.StackSummary, backend.ContinuationToken, error) {
-	stacks, err := b.getLocalStacks()
+	var project *tokens.Name
+	if filter.Project != nil && b.meta.projectScoped() {
+		name := tokens.Name(*filter.Project)
+		project = &name
+	}
+	stacks, err := b.getLocalStacks(project)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Note that the provided stack filter is not honored, since fields like
-	// organizations and tags aren't persisted in the local backend.
+	// Note that the organization filter is not honored, since the local backend has no organizations. Backends that
+	// use the legacy layout do not record the projects of their stacks either.
 	var results []backend.StackSummary
-	for _, stackName := range stacks {
-		chk, err := b.getCheckpoint(stackName)
-		if err != nil {
-			return nil, nil, err
+	for _, stackRef := range stacks {
+		if filter.TagName != nil {
+			tags, err := b.getStackTags(ctx, stackRef)
+			if err != nil {
+				return nil, nil, err
+			}
+			value, has := tags[*filter.TagName]
+			if !has || filter.TagValue != nil && value != *filter.TagValue {
+				continue
+			}
 		}
-		stackRef, err := b.ParseStackReference(string(stackName))
+
+		chk, err := b.getCheckpoint(stackRef)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -367,8 +521,8 @@ func (b *localBackend) RemoveStack(ctx context.Context, stack backend.Stack, for
 	}
 	defer b.Unlock(ctx, stack.Ref())
 
-	stackName := stack.Ref().Name()
-	snapshot, _, err := b.getStack(stackName)
+	ref := localReference(stack.Ref())
+	snapshot, _, err := b.getStack(ref)
 	if err != nil {
 		return false, err
 	}
@@ -378,7 +532,7 @@ func (b *localBackend) RemoveStack(ctx context.Context, stack backend.Stack, for
 		return true, errors.New("refusing to remove stack because it still contains resources")
 	}
 
-	return false, b.removeStack(stackName)
+	return false, b.removeStack(ref)
 }
 
 func (b *localBackend) RenameStack(ctx context.Context, stack backend.Stack,
@@ -391,22 +545,31 @@ func (b *localBackend) RenameStack(ctx context.Context, stack backend.Stack,
 	defer b.Unlock(ctx, stack.Ref())
 
 	// Get the current state from the stack to be renamed.
-	stackName := stack.Ref().Name()
-	snap, _, err := b.getStack(stackName)
+	ref := localReference(stack.Ref())
+	snap, _, err := b.getStack(ref)
 	if err != nil {
 		return nil, err
 	}
 
-	// Ensure the new stack name is valid.
-	newRef, err := b.ParseStackReference(string(newName))
-	if err != nil {
-		return nil, err
+	// Ensure the new stack name is valid. A bare name keeps the stack in its current project.
+	var newRef localBackendReference
+	if ref.project != "" && !strings.Contains(string(newName), "/") {
+		newRef = ref
+		newRef.name = tokens.Name(newName)
+	} else {
+		parsed, err := b.ParseStackReference(string(newName))
+		if err != nil {
+			return nil, err
+		}
+		newRef = localReference(parsed)
 	}
 
-	newStackName := newRef.Name()
-
 	// Ensure the destination stack does not already exist.
-	hasExisting, err := b.bucket.Exists(ctx, b.stackPath(newStackName))
+	newFile, err := b.stackPath(newRef)
+	if err != nil {
+		return nil, err
+	}
+	hasExisting, err := b.bucket.Exists(ctx, newFile)
 	if err != nil {
 		return nil, err
 	}
@@ -414,24 +577,44 @@ func (b *localBackend) RenameStack(ctx context.Context, stack backend.Stack,
 		return nil, fmt.Errorf("a stack named %s already exists", newName)
 	}
 
-	// If we have a snapshot, we need to rename the URNs inside it to use the new stack name.
+	// If we have a snapshot, we need to rename the URNs inside it to use the new stack and project names.
 	if snap != nil {
-		if err = edit.RenameStack(snap, newStackName, ""); err != nil {
+		var newProject tokens.PackageName
+		if newRef.project != ref.project {
+			newProject = tokens.PackageName(newRef.project)
+		}
+		if err = edit.RenameStack(snap, newRef.name, newProject); err != nil {
 			return nil, err
 		}
 	}
 
 	// Now save the snapshot with a new name (we pass nil to re-use the existing secrets manager from the snapshot).
-	if _, err = b.saveStack(newStackName, snap, nil); err != nil {
+	if _, err = b.saveStack(newRef, snap, nil); err != nil {
 		return nil, err
 	}
 
-	// To remove the old stack, just make a backup of the file and don't write out anything new.
-	file := b.stackPath(stackName)
+	// To remove the old stack, just make a backup of the file and don't write out anything new. Any journal has
+	// already been replayed into the snapshot we just saved, so it can go as well.
+	file, err := b.stackPath(ref)
+	if err != nil {
+		return nil, err
+	}
 	backupTarget(b.bucket, file, false)
+	b.stackPaths.Delete(b.plainStackPath(ref))
+	if err = b.removeJournal(ref); err != nil {
+		return nil, err
+	}
+
+	// Move the stack's tags and policy group memberships.
+	if err = moveObject(ctx, b.bucket, b.tagsPath(ref), b.tagsPath(newRef)); err != nil {
+		return nil, err
+	}
+	if err = b.renameStackInPolicyGroups(ctx, ref, &newRef, ""); err != nil {
+		return nil, err
+	}
 
 	// And rename the histoy folder as well.
-	if err = b.renameHistory(stackName, newStackName); err != nil {
+	if err = b.renameHistory(ref, newRef); err != nil {
 		return nil, err
 	}
 	return newRef, err
@@ -536,6 +719,7 @@ func (b *localBackend) apply(
 	events chan<- engine.Event) (*deploy.Plan, engine.ResourceChanges, result.Result) {
 
 	stackRef := stack.Ref()
+	ref := localReference(stackRef)
 	stackName := stackRef.Name()
 	actionLabel := backend.ActionLabel(kind, opts.DryRun)
 
@@ -546,11 +730,29 @@ func (b *localBackend) apply(
 	}
 
 	// Start the update.
-	update, err := b.newUpdate(stackName, op)
+	update, err := b.newUpdate(ref, op)
 	if err != nil {
 		return nil, nil, result.FromError(err)
 	}
 
+	// Enforce the policy packs enabled for the stack's policy groups.
+	requiredPolicies, err := b.getRequiredPolicies(ctx, ref)
+	if err != nil {
+		return nil, nil, result.FromError(fmt.Errorf("getting required policies: %w", err))
+	}
+	op.Opts.Engine.RequiredPolicies = append(op.Opts.Engine.RequiredPolicies, requiredPolicies...)
+
+	// As the service does when an update starts, refresh the stack's tags to pick up any metadata changes.
+	if !opts.DryRun {
+		tags, err := backend.GetMergedStackTags(ctx, stack)
+		if err != nil {
+			return nil, nil, result.FromError(fmt.Errorf("getting stack tags: %w", err))
+		}
+		if err = b.saveStackTags(ctx, ref, tags); err != nil {
+			return nil, nil, result.FromError(fmt.Errorf("saving stack tags: %w", err))
+		}
+	}
+
 	// Spawn a display loop to show events on the CLI.
 	displayEvents := make(chan engine.Event)
 	displayDone := make(chan bool)
@@ -578,8 +780,13 @@ func (b *localBackend) apply(
 	}()
 
 	// Create the management machinery.
-	persister := b.newSnapshotPersister(stackName, op.SecretsManager)
-	manager := backend.NewSnapshotManager(persister, update.GetTarget().Snapshot)
+	var manager engine.SnapshotManager
+	if cmdutil.IsTruthy(os.Getenv(DisableJournalingEnvVar)) {
+		persister := b.newSnapshotPersister(ref, op.SecretsManager)
+		manager = backend.NewSnapshotManager(persister, update.GetTarget().Snapshot)
+	} else {
+		manager = b.newJournalSnapshotManager(ref, op.SecretsManager, update.GetTarget().Snapshot)
+	}
 	engineCtx := &engine.Context{
 		Cancel:          scope.Context(),
 		Events:          engineEvents,
@@ -612,7 +819,7 @@ func (b *localBackend) apply(
 	<-displayDone
 	scope.Close() // Don't take any cancellations anymore, we're shutting down.
 	close(engineEvents)
-	contract.IgnoreClose(manager)
+	closeErr := manager.Close()
 
 	// Make sure the goroutine writing to displayEvents and events has exited before proceeding.
 	<-eventsDone
@@ -620,7 +827,7 @@ func (b *localBackend) apply(
 
 	// Save update results.
 	backendUpdateResult := backend.SucceededResult
-	if updateRes != nil {
+	if updateRes != nil || closeErr != nil {
 		backendUpdateResult = backend.FailedResult
 	}
 	info := backend.UpdateInfo{
@@ -639,9 +846,13 @@ func (b *localBackend) apply(
 
 	var saveErr error
 	var backupErr error
+	var pruneErr error
 	if !opts.DryRun {
-		saveErr = b.addToHistory(stackName, info)
-		backupErr = b.backupStack(stackName)
+		saveErr = b.addToHistory(ref, info)
+		backupErr = b.backupStack(ref)
+		if saveErr == nil && backupErr == nil {
+			pruneErr = b.pruneHistoryFromEnv(ctx, ref)
+		}
 	}
 
 	if updateRes != nil {
@@ -649,6 +860,11 @@ func (b *localBackend) apply(
 		return plan, changes, updateRes
 	}
 
+	if closeErr != nil {
+		// The journal of this update is left behind, so the stack's state will be recovered when it is next loaded.
+		return plan, changes, result.FromError(fmt.Errorf("saving snapshot: %w", closeErr))
+	}
+
 	if saveErr != nil {
 		// We swallow backupErr as it is less important than the saveErr.
 		return plan, changes, result.FromError(fmt.Errorf("saving update info: %w", saveErr))
@@ -658,17 +874,27 @@ func (b *localBackend) apply(
 		return plan, changes, result.FromError(fmt.Errorf("saving backup: %w", backupErr))
 	}
 
+	if pruneErr != nil {
+		// The update itself succeeded, so failing to prune its history is not worth failing the command over.
+		cmdutil.Diag().Warningf(diag.Message("", "could not prune the history of stack %s: %v"), stackRef, pruneErr)
+	}
+
 	// Make sure to print a link to the stack's checkpoint before exiting.
 	if !op.Opts.Display.SuppressPermalink && opts.ShowLink && !op.Opts.Display.JSONDisplay {
 		// Note we get a real signed link for aws/azure/gcp links.  But no such option exists for
 		// file:// links so we manually create the link ourselves.
 		var link string
-		if strings.HasPrefix(b.url, FilePathPrefix) {
+		stackPath, err := b.stackPath(ref)
+		switch {
+		case err != nil:
+			// As with pruning, the update itself succeeded, so this is not worth failing the command over.
+			cmdutil.Diag().Warningf(diag.Message("", "could not create a Permalink for stack %s: %v"), stackRef, err)
+		case strings.HasPrefix(b.url, FilePathPrefix):
 			u, _ := url.Parse(b.url)
-			u.Path = filepath.ToSlash(path.Join(u.Path, b.stackPath(stackName)))
+			u.Path = filepath.ToSlash(path.Join(u.Path, stackPath))
 			link = u.String()
-		} else {
-			link, err = b.bucket.SignedURL(context.TODO(), b.stackPath(stackName), nil)
+		default:
+			link, err = b.bucket.SignedURL(context.TODO(), stackPath, nil)
 			if err != nil {
 				// set link to be empty to when there is an error to hide use of Permalinks
 				link = ""
@@ -706,8 +932,7 @@ func (b *localBackend) GetHistory(
 	stackRef backend.StackReference,
 	pageSize int,
 	page int) ([]backend.UpdateInfo, error) {
-	stackName := stackRef.Name()
-	updates, err := b.getHistory(stackName, pageSize, page)
+	updates, err := b.getHistory(localReference(stackRef), pageSize, page)
 	if err != nil {
 		return nil, err
 	}
@@ -717,8 +942,7 @@ func (b *localBackend) GetHistory(
 func (b *localBackend) GetLogs(ctx context.Context, stack backend.Stack, cfg backend.StackConfiguration,
 	query operations.LogQuery) ([]operations.LogEntry, error) {
 
-	stackName := stack.Ref().Name()
-	target, err := b.getTarget(stackName, cfg.Config, cfg.Decrypter)
+	target, err := b.getTarget(localReference(stack.Ref()), cfg.Config, cfg.Decrypter)
 	if err != nil {
 		return nil, err
 	}
@@ -752,8 +976,7 @@ func GetLogsForTarget(target *deploy.Target, query operations.LogQuery) ([]opera
 func (b *localBackend) ExportDeployment(ctx context.Context,
 	stk backend.Stack) (*apitype.UntypedDeployment, error) {
 
-	stackName := stk.Ref().Name()
-	snap, _, err := b.getStack(stackName)
+	snap, _, err := b.getStack(localReference(stk.Ref()))
 	if err != nil {
 		return nil, err
 	}
@@ -778,6 +1001,36 @@ func (b *localBackend) ExportDeployment(ctx context.Context,
 	}, nil
 }
 
+func (b *localBackend) ExportDeploymentForVersion(ctx context.Context, stk backend.Stack,
+	version string) (*apitype.UntypedDeployment, error) {
+
+	ref := localReference(stk.Ref())
+	v, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: versions of local stacks are numeric", version)
+	}
+
+	chk, err := b.getHistoryCheckpoint(ref, v)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := chk.Latest
+	if deployment == nil {
+		deployment = &apitype.DeploymentV3{}
+	}
+
+	data, err := json.Marshal(deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    3,
+		Deployment: json.RawMessage(data),
+	}, nil
+}
+
 func (b *localBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	deployment *apitype.UntypedDeployment) error {
 
@@ -787,8 +1040,8 @@ func (b *localBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	}
 	defer b.Unlock(ctx, stk.Ref())
 
-	stackName := stk.Ref().Name()
-	_, _, err = b.getStack(stackName)
+	ref := localReference(stk.Ref())
+	_, _, err = b.getStack(ref)
 	if err != nil {
 		return err
 	}
@@ -798,7 +1051,7 @@ func (b *localBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 		return err
 	}
 
-	_, err = b.saveStack(stackName, snap, snap.SecretsManager)
+	_, err = b.saveStack(ref, snap, snap.SecretsManager)
 	return err
 }
 
@@ -818,50 +1071,102 @@ func (b *localBackend) CurrentUser() (string, error) {
 	return user.Username, nil
 }
 
-func (b *localBackend) getLocalStacks() ([]tokens.Name, error) {
-	var stacks []tokens.Name
+// getLocalStacks returns references to the stacks in the backend. If the backend uses the project-scoped layout and
+// a project is given, only the stacks of that project are returned.
+func (b *localBackend) getLocalStacks(project *tokens.Name) ([]localBackendReference, error) {
+	if !b.meta.projectScoped() {
+		return b.getLocalStacksIn(b.stacksDirectory(), "")
+	}
 
-	// Read the stack directory.
-	path := b.stackPath("")
+	var projects []tokens.Name
+	if project != nil {
+		projects = []tokens.Name{*project}
+	} else {
+		all, err := b.getLocalProjects()
+		if err != nil {
+			return nil, err
+		}
+		projects = all
+	}
 
-	files, err := listBucket(b.bucket, path)
+	var stacks []localBackendReference
+	for _, project := range projects {
+		projectStacks, err := b.getLocalStacksIn(path.Join(b.stacksDirectory(), fsutil.NamePath(project)), project)
+		if err != nil {
+			return nil, err
+		}
+		stacks = append(stacks, projectStacks...)
+	}
+	return stacks, nil
+}
+
+// getLocalStacksIn returns references to the stacks whose checkpoints are in the given directory.
+func (b *localBackend) getLocalStacksIn(dir string, project tokens.Name) ([]localBackendReference, error) {
+	var stacks []localBackendReference
+
+	files, err := listBucket(b.bucket, dir)
 	if err != nil {
 		return nil, fmt.Errorf("error listing stacks: %w", err)
 	}
 
+	seen := make(map[tokens.Name]bool)
 	for _, file := range files {
 		// Ignore directories.
 		if file.IsDir {
 			continue
 		}
 
-		// Skip files without valid extensions (e.g., *.bak files).
-		stackfn := objectName(file)
+		// Skip files without valid extensions (e.g., *.bak files). Compressed checkpoints end in .json.gz.
+		stackfn := strings.TrimSuffix(objectName(file), gzipExt)
 		ext := filepath.Ext(stackfn)
 		if _, has := encoding.Marshalers[ext]; !has {
 			continue
 		}
 
-		// Read in this stack's information.
+		// Read in this stack's information. A stack whose checkpoint was being converted between formats when the
+		// CLI was interrupted may have both.
 		name := tokens.Name(stackfn[:len(stackfn)-len(ext)])
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
 
-		stacks = append(stacks, name)
+		stacks = append(stacks, localBackendReference{
+			name:           name,
+			project:        project,
+			currentProject: b.currentProject,
+		})
 	}
 
 	return stacks, nil
 }
 
+// getLocalProjects returns the projects that have stacks in a backend that uses the project-scoped layout.
+func (b *localBackend) getLocalProjects() ([]tokens.Name, error) {
+	files, err := listBucket(b.bucket, b.stacksDirectory())
+	if err != nil {
+		return nil, fmt.Errorf("error listing projects: %w", err)
+	}
+
+	var projects []tokens.Name
+	for _, file := range files {
+		if file.IsDir {
+			projects = append(projects, tokens.Name(path.Base(strings.TrimSuffix(file.Key, "/"))))
+		}
+	}
+	return projects, nil
+}
+
 // UpdateStackTags updates the stacks's tags, replacing all existing tags.
 func (b *localBackend) UpdateStackTags(ctx context.Context,
 	stack backend.Stack, tags map[apitype.StackTagName]string) error {
 
-	// The local backend does not currently persist tags.
-	return errors.New("stack tags not supported in --local mode")
+	return b.saveStackTags(ctx, localReference(stack.Ref()), tags)
 }
 
 func (b *localBackend) CancelCurrentUpdate(ctx context.Context, stackRef backend.StackReference) error {
 	// Try to delete ALL the lock files
-	allFiles, err := listBucket(b.bucket, stackLockDir(stackRef.Name()))
+	allFiles, err := listBucket(b.bucket, stackLockDir(localReference(stackRef)))
 	if err != nil {
 		// Don't error if it just wasn't found
 		if gcerrors.Code(err) == gcerrors.NotFound {
@@ -3,19 +3,23 @@ package filestate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	user "github.com/tweekmonster/luser"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/operations"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -148,7 +152,7 @@ func TestListStacksWithMultiplePassphrases(t *testing.T) {
 	ctx := context.Background()
 
 	// Create stack "a" and import a checkpoint with a secret
-	aStackRef, err := b.ParseStackReference("a")
+	aStackRef, err := b.ParseStackReference("organization/project/a")
 	assert.NoError(t, err)
 	aStack, err := b.CreateStack(ctx, aStackRef, nil)
 	assert.NoError(t, err)
@@ -167,7 +171,7 @@ func TestListStacksWithMultiplePassphrases(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Create stack "b" and import a checkpoint with a secret
-	bStackRef, err := b.ParseStackReference("b")
+	bStackRef, err := b.ParseStackReference("organization/project/b")
 	assert.NoError(t, err)
 	bStack, err := b.CreateStack(ctx, bStackRef, nil)
 	assert.NoError(t, err)
@@ -212,7 +216,7 @@ func TestDrillError(t *testing.T) {
 	ctx := context.Background()
 
 	// Get a non-existent stack and expect a nil error because it won't be found.
-	stackRef, err := b.ParseStackReference("dev")
+	stackRef, err := b.ParseStackReference("organization/project/dev")
 	if err != nil {
 		t.Fatalf("unexpected error %v when parsing stack reference", err)
 	}
@@ -231,7 +235,7 @@ func TestCancel(t *testing.T) {
 	ctx := context.Background()
 
 	// Check that trying to cancel a stack that isn't created yet doesn't error
-	aStackRef, err := b.ParseStackReference("a")
+	aStackRef, err := b.ParseStackReference("organization/project/a")
 	assert.NoError(t, err)
 	err = b.CancelCurrentUpdate(ctx, aStackRef)
 	assert.NoError(t, err)
@@ -252,14 +256,14 @@ func TestCancel(t *testing.T) {
 	err = lb.Lock(ctx, aStackRef)
 	assert.NoError(t, err)
 	// check the lock file exists
-	lockExists, err := lb.bucket.Exists(ctx, lb.lockPath(aStackRef.Name()))
+	lockExists, err := lb.bucket.Exists(ctx, lb.lockPath(localReference(aStackRef)))
 	assert.NoError(t, err)
 	assert.True(t, lockExists)
 	// Call CancelCurrentUpdate
 	err = lb.CancelCurrentUpdate(ctx, aStackRef)
 	assert.NoError(t, err)
 	// Now check the lock file no longer exists
-	lockExists, err = lb.bucket.Exists(ctx, lb.lockPath(aStackRef.Name()))
+	lockExists, err = lb.bucket.Exists(ctx, lb.lockPath(localReference(aStackRef)))
 	assert.NoError(t, err)
 	assert.False(t, lockExists)
 
@@ -281,3 +285,396 @@ func TestCancel(t *testing.T) {
 	err = lb.checkForLock(ctx, aStackRef)
 	assert.NoError(t, err)
 }
+
+func TestExportDeploymentForVersion(t *testing.T) {
+	t.Parallel()
+
+	// Login to a temp dir filestate backend
+	tmpDir, err := ioutil.TempDir("", "filestatebackend")
+	assert.NoError(t, err)
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	stackRef, err := b.ParseStackReference("organization/project/a")
+	assert.NoError(t, err)
+	s, err := b.CreateStack(ctx, stackRef, nil)
+	assert.NoError(t, err)
+
+	lb, ok := b.(*localBackend)
+	assert.True(t, ok)
+
+	// Record two updates, each with a different set of resources.
+	sm := b64.NewBase64SecretsManager()
+	var resources []*resource.State
+	for _, name := range []tokens.QName{"first", "second"} {
+		resources = append(resources, &resource.State{
+			URN:  resource.NewURN("a", "proj", "", "a:b:c", name),
+			Type: "a:b:c",
+		})
+		_, err = lb.saveStack(localReference(stackRef), deploy.NewSnapshot(deploy.Manifest{}, sm, resources, nil), sm)
+		assert.NoError(t, err)
+		err = lb.addToHistory(localReference(stackRef), backend.UpdateInfo{Kind: apitype.UpdateUpdate})
+		assert.NoError(t, err)
+	}
+
+	// History is returned newest first, numbered from the oldest update.
+	updates, err := b.GetHistory(ctx, stackRef, 0 /*pageSize*/, 0 /*page*/)
+	assert.NoError(t, err)
+	if assert.Len(t, updates, 2) {
+		assert.Equal(t, 2, updates[0].Version)
+		assert.Equal(t, 1, updates[1].Version)
+	}
+
+	for version, count := range map[string]int{"1": 1, "2": 2} {
+		deployment, err := lb.ExportDeploymentForVersion(ctx, s, version)
+		assert.NoError(t, err)
+		snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+		assert.NoError(t, err)
+		assert.Len(t, snap.Resources, count)
+	}
+
+	_, err = lb.ExportDeploymentForVersion(ctx, s, "3")
+	assert.Error(t, err)
+	_, err = lb.ExportDeploymentForVersion(ctx, s, "latest")
+	assert.Error(t, err)
+}
+
+func TestGetResourceHistory(t *testing.T) {
+	t.Parallel()
+
+	// Login to a temp dir filestate backend
+	tmpDir, err := ioutil.TempDir("", "filestatebackend")
+	assert.NoError(t, err)
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	stackRef, err := b.ParseStackReference("organization/project/a")
+	assert.NoError(t, err)
+	s, err := b.CreateStack(ctx, stackRef, nil)
+	assert.NoError(t, err)
+	lb := b.(*localBackend)
+
+	urn := resource.NewURN("a", "proj", "", "a:b:c", "res")
+	other := &resource.State{URN: resource.NewURN("a", "proj", "", "a:b:c", "other"), Type: "a:b:c"}
+	newState := func(id resource.ID, size float64) *resource.State {
+		return &resource.State{
+			URN:     urn,
+			Type:    "a:b:c",
+			Custom:  true,
+			ID:      id,
+			Inputs:  resource.PropertyMap{"size": resource.NewNumberProperty(size)},
+			Outputs: resource.PropertyMap{},
+		}
+	}
+
+	// Create the resource, update it, leave it alone, replace it and finally delete it.
+	sm := b64.NewBase64SecretsManager()
+	for _, resources := range [][]*resource.State{
+		{newState("1", 1)},
+		{newState("1", 2)},
+		{newState("1", 2), other},
+		{newState("2", 2), other},
+		{other},
+	} {
+		_, err = lb.saveStack(localReference(stackRef), deploy.NewSnapshot(deploy.Manifest{}, sm, resources, nil), sm)
+		assert.NoError(t, err)
+		err = lb.addToHistory(localReference(stackRef), backend.UpdateInfo{Kind: apitype.UpdateUpdate})
+		assert.NoError(t, err)
+	}
+
+	entries, err := s.GetResourceHistory(ctx, urn)
+	assert.NoError(t, err)
+	var versions []int
+	var ops []deploy.StepOp
+	for _, entry := range entries {
+		versions = append(versions, entry.Update.Version)
+		ops = append(ops, entry.Diff.Op)
+	}
+	assert.Equal(t, []int{1, 2, 4, 5}, versions)
+	assert.Equal(t, []deploy.StepOp{deploy.OpCreate, deploy.OpUpdate, deploy.OpReplace, deploy.OpDelete}, ops)
+	if assert.Len(t, entries, 4) && assert.NotNil(t, entries[1].Diff.Inputs) {
+		update := entries[1].Diff.Inputs.Updates["size"]
+		assert.Equal(t, resource.NewNumberProperty(1), update.Old)
+		assert.Equal(t, resource.NewNumberProperty(2), update.New)
+	}
+
+	entries, err = s.GetResourceHistory(ctx, resource.NewURN("a", "proj", "", "a:b:c", "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestBreakStaleLocks(t *testing.T) {
+	t.Parallel()
+
+	// Login to a temp dir filestate backend
+	tmpDir, err := ioutil.TempDir("", "filestatebackend")
+	assert.NoError(t, err)
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	aStackRef, err := b.ParseStackReference("organization/project/a")
+	assert.NoError(t, err)
+	_, err = b.CreateStack(ctx, aStackRef, nil)
+	assert.NoError(t, err)
+	lb := b.(*localBackend)
+
+	ob, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	assert.NoError(t, err)
+	otherBackend := ob.(*localBackend)
+
+	// Simulate a process that took a lock and then crashed, so its lease was never renewed.
+	content, err := newLockContent()
+	assert.NoError(t, err)
+	content.Timestamp = time.Now().Add(-time.Hour)
+	content.Expires = content.Timestamp.Add(lockLeaseDuration)
+	bytes, err := json.Marshal(content)
+	assert.NoError(t, err)
+	err = otherBackend.bucket.WriteAll(ctx, otherBackend.lockPath(localReference(aStackRef)), bytes, nil)
+	assert.NoError(t, err)
+
+	err = lb.Lock(ctx, aStackRef)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "is no longer running (stale lock)")
+		assert.Contains(t, err.Error(), "--break-stale-lock")
+	}
+
+	broken, err := lb.BreakStaleLocks(ctx, aStackRef)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, broken)
+
+	// A lock held by a running process is reported as alive and is not broken.
+	err = otherBackend.Lock(ctx, aStackRef)
+	assert.NoError(t, err)
+	err = lb.checkForLock(ctx, aStackRef)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "its holder is still running")
+		assert.NotContains(t, err.Error(), "--break-stale-lock")
+	}
+	broken, err = lb.BreakStaleLocks(ctx, aStackRef)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, broken)
+
+	otherBackend.Unlock(ctx, aStackRef)
+	err = lb.Lock(ctx, aStackRef)
+	assert.NoError(t, err)
+	lb.Unlock(ctx, aStackRef)
+}
+
+func TestLockTwice(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+	lockPath := lb.lockPath(localReference(ref))
+
+	// A stack that this process has locked cannot be locked again until it is unlocked.
+	require.NoError(t, lb.Lock(ctx, ref))
+	err = lb.Lock(ctx, ref)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "already locked by this process")
+	}
+
+	// Unlocking once releases the lock, and leaves no lease behind that would write it again.
+	lb.Unlock(ctx, ref)
+	exists, err := lb.bucket.Exists(ctx, lockPath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	lb.leasesMutex.Lock()
+	assert.Empty(t, lb.leases)
+	lb.leasesMutex.Unlock()
+
+	require.NoError(t, lb.Lock(ctx, ref))
+	lb.Unlock(ctx, ref)
+}
+
+func TestStackTags(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	assert.NoError(t, err)
+	ctx := context.Background()
+	assert.True(t, b.SupportsTags())
+
+	aStackRef, err := b.ParseStackReference("organization/project/a")
+	assert.NoError(t, err)
+	aStack, err := b.CreateStack(ctx, aStackRef, nil)
+	assert.NoError(t, err)
+	bStackRef, err := b.ParseStackReference("organization/project/b")
+	assert.NoError(t, err)
+	_, err = b.CreateStack(ctx, bStackRef, nil)
+	assert.NoError(t, err)
+
+	tags := map[apitype.StackTagName]string{"owner": "platform", "cost-center": "42"}
+	err = b.UpdateStackTags(ctx, aStack, tags)
+	assert.NoError(t, err)
+
+	aStack, err = b.GetStack(ctx, aStackRef)
+	assert.NoError(t, err)
+	assert.Equal(t, tags, aStack.Tags())
+
+	// Stacks can be filtered by tag name, or by tag name and value.
+	filterNames := func(filter backend.ListStacksFilter) []string {
+		stacks, _, err := b.ListStacks(ctx, filter, nil)
+		assert.NoError(t, err)
+		var names []string
+		for _, s := range stacks {
+			names = append(names, string(s.Name().Name()))
+		}
+		return names
+	}
+	owner, platform, other := "owner", "platform", "other"
+	assert.Equal(t, []string{"a"}, filterNames(backend.ListStacksFilter{TagName: &owner}))
+	assert.Equal(t, []string{"a"}, filterNames(backend.ListStacksFilter{TagName: &owner, TagValue: &platform}))
+	assert.Empty(t, filterNames(backend.ListStacksFilter{TagName: &owner, TagValue: &other}))
+	assert.Len(t, filterNames(backend.ListStacksFilter{}), 2)
+
+	// Tags follow the stack when it is renamed, and are removed with it.
+	cRef, err := b.RenameStack(ctx, aStack, "c")
+	assert.NoError(t, err)
+	cStack, err := b.GetStack(ctx, cRef)
+	assert.NoError(t, err)
+	assert.Equal(t, tags, cStack.Tags())
+
+	_, err = b.RemoveStack(ctx, cStack, false)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(tmpDir, ".pulumi", "stacks", "project", "c.json.tags"))
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestGzipCheckpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+	ctx := context.Background()
+	stacksDir := filepath.Join(tmpDir, ".pulumi", "stacks", "project")
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+
+	sm := b64.NewBase64SecretsManager()
+	resources := []*resource.State{{URN: resource.NewURN("a", "proj", "", "a:b:c", "res"), Type: "a:b:c"}}
+	snap := deploy.NewSnapshot(deploy.Manifest{}, sm, resources, nil)
+
+	// Compressed checkpoints replace plain ones when they are saved, and are read transparently.
+	t.Setenv(GzipCheckpointsEnvVar, "true")
+	_, err = lb.saveStack(localReference(ref), snap, sm)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(stacksDir, "a.json.gz"))
+	assert.NoFileExists(t, filepath.Join(stacksDir, "a.json"))
+	require.NoError(t, lb.addToHistory(localReference(ref), backend.UpdateInfo{Kind: apitype.UpdateUpdate}))
+
+	t.Setenv(GzipCheckpointsEnvVar, "")
+	s, err := b.GetStack(ctx, ref)
+	require.NoError(t, err)
+	snap, err = s.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Len(t, snap.Resources, 1)
+	stacks, _, err := b.ListStacks(ctx, backend.ListStacksFilter{}, nil)
+	require.NoError(t, err)
+	assert.Len(t, stacks, 1)
+	_, err = lb.ExportDeploymentForVersion(ctx, s, "1")
+	require.NoError(t, err)
+
+	// Renaming a stack saves its checkpoint in the current format.
+	bRef, err := b.RenameStack(ctx, s, "b")
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(stacksDir, "b.json"))
+	assert.NoFileExists(t, filepath.Join(stacksDir, "a.json.gz"))
+	s, err = b.GetStack(ctx, bRef)
+	require.NoError(t, err)
+	snap, err = s.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Len(t, snap.Resources, 1)
+}
+
+// existsCountingBucket counts the lookups of the objects in a bucket, and fails them if err is set.
+type existsCountingBucket struct {
+	Bucket
+	lookups int
+	err     error
+}
+
+func (b *existsCountingBucket) Exists(ctx context.Context, key string) (bool, error) {
+	b.lookups++
+	if b.err != nil {
+		return false, b.err
+	}
+	return b.Bucket.Exists(ctx, key)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestStackPathLookups(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	sm := b64.NewBase64SecretsManager()
+	resources := []*resource.State{{URN: resource.NewURN("a", "proj", "", "a:b:c", "res"), Type: "a:b:c"}}
+	snap := deploy.NewSnapshot(deploy.Manifest{}, sm, resources, nil)
+
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+
+	// Once a checkpoint has been saved, its path is known.
+	bucket := &existsCountingBucket{Bucket: lb.bucket}
+	lb.bucket = bucket
+	t.Setenv(GzipCheckpointsEnvVar, "true")
+	for i := 0; i < 3; i++ {
+		_, err = lb.saveStack(localReference(ref), snap, sm)
+		require.NoError(t, err)
+		_, _, err = lb.getStack(localReference(ref))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 0, bucket.lookups)
+
+	// A checkpoint in the other format is looked up once.
+	t.Setenv(GzipCheckpointsEnvVar, "")
+	b, err = New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	require.NoError(t, err)
+	lb = b.(*localBackend)
+	bucket = &existsCountingBucket{Bucket: lb.bucket}
+	lb.bucket = bucket
+	for i := 0; i < 3; i++ {
+		_, _, err = lb.getStack(localReference(ref))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, bucket.lookups)
+
+	// A failed lookup is reported rather than guessing a path, and is not remembered.
+	b2, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	require.NoError(t, err)
+	lb2 := b2.(*localBackend)
+	failing := &existsCountingBucket{Bucket: lb2.bucket, err: errors.New("bucket unavailable")}
+	lb2.bucket = failing
+	_, err = lb2.stackPath(localReference(ref))
+	assert.True(t, errors.Is(err, failing.err), "unexpected error: %v", err)
+	_, _, err = lb2.getStack(localReference(ref))
+	assert.True(t, errors.Is(err, failing.err), "unexpected error: %v", err)
+	failing.err = nil
+	_, _, err = lb2.getStack(localReference(ref))
+	assert.NoError(t, err)
+
+	// Removing the stack forgets its path.
+	s, err := b.GetStack(ctx, ref)
+	require.NoError(t, err)
+	_, err = b.RemoveStack(ctx, s, true)
+	require.NoError(t, err)
+	_, _, err = lb.getStack(localReference(ref))
+	assert.Error(t, err)
+}
@@ -0,0 +1,446 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/version"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// DisableJournalingEnvVar can be set to a truthy value to make updates rewrite the stack's checkpoint after every
+// step, rather than recording each step in an append-only journal and writing the checkpoint once at the end.
+const DisableJournalingEnvVar = "PULUMI_DISABLE_JOURNALING"
+
+// journalEntryKind is the kind of a persisted journal entry.
+type journalEntryKind string
+
+const (
+	// journalEntryBegin records that the engine has started to execute a step.
+	journalEntryBegin journalEntryKind = "begin"
+	// journalEntrySuccess records that a step completed successfully.
+	journalEntrySuccess journalEntryKind = "success"
+	// journalEntryFailure records that a step failed.
+	journalEntryFailure journalEntryKind = "failure"
+	// journalEntryOutputs records the outputs registered for a previously completed step.
+	journalEntryOutputs journalEntryKind = "outputs"
+	// journalEntryRebase replaces the base deployment that the journal applies to. A rebase entry starts every
+	// journal, and another one is written whenever the engine rewrites its base snapshot.
+	journalEntryRebase journalEntryKind = "rebase"
+)
+
+// journalEntry is the persisted form of an engine.JournalEntry. Rather than referring to resource states by identity,
+// entries refer to old states by their index in the base deployment and to new states by an ID that is unique within
+// the journal.
+type journalEntry struct {
+	Kind journalEntryKind `json:"kind"`
+	Op   deploy.StepOp    `json:"op,omitempty"`
+	URN  resource.URN     `json:"urn,omitempty"`
+	// Old is the index of the step's old state in the base deployment, if the step has an old state.
+	Old *int `json:"old,omitempty"`
+	// NewID identifies the step's new state, if the step has a new state.
+	NewID int `json:"newID,omitempty"`
+	// New is the serialized new state of the step as of this entry.
+	New *apitype.ResourceV3 `json:"new,omitempty"`
+	// Deployment is the new base deployment for rebase entries.
+	Deployment *apitype.DeploymentV3 `json:"deployment,omitempty"`
+}
+
+// journalSnapshotManager is an engine.SnapshotManager that records each step of an update in an append-only journal
+// stored next to the stack's checkpoint, and only writes a full checkpoint once the update finishes. If the update is
+// interrupted, the journal is replayed on top of the checkpoint the next time the stack is loaded.
+type journalSnapshotManager struct {
+	ref     localBackendReference
+	backend *localBackend
+	sm      secrets.Manager
+	base    *deploy.Snapshot // the base snapshot; the engine rewrites its resources in place after a refresh.
+
+	mu         sync.Mutex
+	entries    engine.JournalEntries   // the in-memory journal, used to produce the final snapshot.
+	prepared   bool                    // true once any journal left by an interrupted update has been folded in.
+	baseStates []*resource.State       // the base snapshot's resources as of the last rebase entry.
+	baseIndex  map[*resource.State]int // the index of each base state as of the last rebase entry.
+	newIDs     map[*resource.State]int // the journal ID of each new state.
+	seq        int                     // the sequence number of the next persisted entry.
+	dirty      bool                    // true if the final snapshot needs to be written on close.
+}
+
+var _ engine.SnapshotManager = (*journalSnapshotManager)(nil)
+
+func (b *localBackend) newJournalSnapshotManager(ref localBackendReference, sm secrets.Manager,
+	base *deploy.Snapshot) *journalSnapshotManager {
+
+	return &journalSnapshotManager{
+		ref:     ref,
+		backend: b,
+		sm:      sm,
+		base:    base,
+		newIDs:  make(map[*resource.State]int),
+	}
+}
+
+type journalSnapshotMutation struct {
+	manager *journalSnapshotManager
+}
+
+func (m *journalSnapshotMutation) End(step deploy.Step, successful bool) error {
+	kind, persistedKind := engine.JournalEntryFailure, journalEntryFailure
+	if successful {
+		kind, persistedKind = engine.JournalEntrySuccess, journalEntrySuccess
+	}
+	return m.manager.record(engine.JournalEntry{Kind: kind, Step: step}, persistedKind)
+}
+
+func (j *journalSnapshotManager) BeginMutation(step deploy.Step) (engine.SnapshotMutation, error) {
+	contract.Require(step != nil, "step != nil")
+	if err := j.record(engine.JournalEntry{Kind: engine.JournalEntryBegin, Step: step}, journalEntryBegin); err != nil {
+		return nil, err
+	}
+	return &journalSnapshotMutation{manager: j}, nil
+}
+
+func (j *journalSnapshotManager) RegisterResourceOutputs(step deploy.Step) error {
+	contract.Require(step != nil, "step != nil")
+	return j.record(engine.JournalEntry{Kind: engine.JournalEntryOutputs, Step: step}, journalEntryOutputs)
+}
+
+// record appends an entry to the in-memory journal and persists it.
+func (j *journalSnapshotManager) record(entry engine.JournalEntry, kind journalEntryKind) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, entry)
+	j.dirty = true
+
+	// Refresh steps don't change the snapshot directly. Instead, the engine rewrites the base snapshot once all
+	// refreshes are done, which is picked up by the rebase entry written before the next step.
+	if entry.Step.Op() == deploy.OpRefresh {
+		return nil
+	}
+
+	if err := j.prepare(); err != nil {
+		return err
+	}
+	if j.baseChanged() {
+		if err := j.persistBase(); err != nil {
+			return err
+		}
+	}
+
+	persisted := journalEntry{Kind: kind, Op: entry.Step.Op(), URN: entry.Step.URN()}
+	if old := entry.Step.Old(); old != nil {
+		// Every step's old state comes from the base snapshot.
+		index, has := j.baseIndex[old]
+		if !has {
+			return fmt.Errorf("journaling %v of %v: old state is not part of the base snapshot", persisted.Op, persisted.URN)
+		}
+		persisted.Old = &index
+	}
+	if new := entry.Step.New(); new != nil {
+		id, has := j.newIDs[new]
+		if !has {
+			id = len(j.newIDs) + 1
+			j.newIDs[new] = id
+		}
+		enc, err := j.sm.Encrypter()
+		if err != nil {
+			return err
+		}
+		res, err := stack.SerializeResource(new, enc, false /* showSecrets */)
+		if err != nil {
+			return fmt.Errorf("serializing journal entry for %v: %w", persisted.URN, err)
+		}
+		persisted.NewID, persisted.New = id, &res
+	}
+	return j.persist(persisted)
+}
+
+// prepare makes sure that no journal left behind by an interrupted update is in the way before the first entry is
+// persisted. getStack has already replayed any such journal into the base snapshot, so writing the base snapshot
+// as the stack's checkpoint (which removes the journal) preserves its contents.
+func (j *journalSnapshotManager) prepare() error {
+	if j.prepared {
+		return nil
+	}
+
+	files, err := listBucket(j.backend.bucket, j.backend.journalDirectory(j.ref))
+	if err != nil {
+		return err
+	}
+	if len(files) != 0 {
+		if _, err = j.backend.saveStack(j.ref, j.base, j.sm); err != nil {
+			return err
+		}
+	}
+
+	j.prepared = true
+	return nil
+}
+
+// baseChanged returns true if the engine has rewritten the base snapshot since it was last persisted. The engine
+// does this when it adds default providers to old snapshots, after refreshing resources, and after targeted deletes.
+func (j *journalSnapshotManager) baseChanged() bool {
+	if j.baseIndex == nil {
+		return true
+	}
+	if j.base == nil {
+		return false
+	}
+	resources := j.base.Resources
+	return len(resources) != len(j.baseStates) || len(resources) != 0 && &resources[0] != &j.baseStates[0]
+}
+
+// persistBase writes a rebase entry containing the current base snapshot, and indexes its states so that later
+// entries can refer to them.
+func (j *journalSnapshotManager) persistBase() error {
+	base := j.base
+	if base == nil {
+		base = deploy.NewSnapshot(deploy.Manifest{Time: time.Now(), Version: version.Version}, j.sm, nil, nil)
+		base.Manifest.Magic = base.Manifest.NewMagic()
+	}
+	dep, err := stack.SerializeDeployment(base, j.sm, false /* showSecrets */)
+	if err != nil {
+		return fmt.Errorf("serializing journal base: %w", err)
+	}
+	if err = j.persist(journalEntry{Kind: journalEntryRebase, Deployment: dep}); err != nil {
+		return err
+	}
+
+	j.baseStates = base.Resources
+	j.baseIndex = make(map[*resource.State]int)
+	for i, res := range base.Resources {
+		j.baseIndex[res] = i
+	}
+	return nil
+}
+
+// persist writes a single entry to the journal.
+func (j *journalSnapshotManager) persist(entry journalEntry) error {
+	byts, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshalling journal entry: %w", err)
+	}
+
+	file := filepath.Join(j.backend.journalDirectory(j.ref), fmt.Sprintf("%010d.json", j.seq))
+	if err = j.backend.bucket.WriteAll(context.TODO(), file, byts, nil); err != nil {
+		return fmt.Errorf("An IO error occurred while writing the journal entry: %w", err)
+	}
+	j.seq++
+	return nil
+}
+
+// Close writes the final snapshot produced by the journal to the stack's checkpoint, which also removes the journal.
+func (j *journalSnapshotManager) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if !j.dirty {
+		return nil
+	}
+
+	snap := j.entries.Snap(j.base)
+	snap.Manifest = deploy.Manifest{Time: time.Now(), Version: version.Version}
+	snap.Manifest.Magic = snap.Manifest.NewMagic()
+	snap.SecretsManager = j.sm
+	if err := snap.NormalizeURNReferences(); err != nil {
+		return fmt.Errorf("failed to normalize URN references: %w", err)
+	}
+	if _, err := j.backend.saveStack(j.ref, snap, j.sm); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	j.dirty = false
+	return nil
+}
+
+func (b *localBackend) journalDirectory(ref localBackendReference) string {
+	contract.Require(ref.name != "", "ref.name")
+	return filepath.Join(b.StateDir(), workspace.JournalDir, ref.pathName())
+}
+
+// readJournal reads the entries left behind by an interrupted update of the given stack, if any.
+func (b *localBackend) readJournal(ref localBackendReference) ([]journalEntry, error) {
+	files, err := listBucket(b.bucket, b.journalDirectory(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]journalEntry, 0, len(files))
+	for _, file := range files {
+		byts, err := b.bucket.ReadAll(context.TODO(), file.Key)
+		if err != nil {
+			return nil, fmt.Errorf("reading journal entry %s: %w", file.Key, err)
+		}
+		var entry journalEntry
+		if err = json.Unmarshal(byts, &entry); err != nil {
+			// The last entry may have been cut short if the update was killed while writing it. Entries are
+			// written in order, so we can safely stop here.
+			logging.V(5).Infof("ignoring unreadable journal entry %s: %v", file.Key, err)
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// removeJournal deletes the journal for the given stack.
+func (b *localBackend) removeJournal(ref localBackendReference) error {
+	return removeAllByPrefix(b.bucket, b.journalDirectory(ref))
+}
+
+// journalStateRef identifies a state referred to by a journal: either a state in the base deployment or one recorded
+// by the journal itself.
+type journalStateRef struct {
+	base  int // the index of the state in the base deployment, or -1.
+	newID int // the journal ID of the state, or 0.
+}
+
+// replayJournal reconstructs the deployment produced by the given journal entries, the first of which must be a
+// rebase entry. This mirrors engine.JournalEntries.Snap.
+func replayJournal(entries []journalEntry) *apitype.DeploymentV3 {
+	contract.Require(len(entries) != 0 && entries[0].Kind == journalEntryRebase, "entries[0].Kind == rebase")
+
+	var base *apitype.DeploymentV3
+	news := make(map[int]*apitype.ResourceV3)
+	var resources []journalStateRef
+	dones := make(map[int]bool)
+	var ops []journalStateRef
+	opTypes := make(map[journalStateRef]apitype.OperationType)
+	doneOps := make(map[journalStateRef]bool)
+
+	for _, e := range entries {
+		if e.Kind == journalEntryRebase {
+			base, dones = e.Deployment, make(map[int]bool)
+			continue
+		}
+
+		old, new := journalStateRef{base: -1}, journalStateRef{base: -1}
+		if e.Old != nil {
+			old.base = *e.Old
+		}
+		if e.New != nil {
+			new.newID = e.NewID
+			if existing, has := news[e.NewID]; has {
+				*existing = *e.New
+			} else {
+				news[e.NewID] = e.New
+			}
+		}
+
+		switch e.Kind {
+		case journalEntryBegin:
+			var ref journalStateRef
+			var typ apitype.OperationType
+			switch e.Op {
+			case deploy.OpCreate, deploy.OpCreateReplacement:
+				ref, typ = new, apitype.OperationTypeCreating
+			case deploy.OpDelete, deploy.OpDeleteReplaced, deploy.OpReadDiscard, deploy.OpDiscardReplaced:
+				ref, typ = old, apitype.OperationTypeDeleting
+			case deploy.OpRead, deploy.OpReadReplacement:
+				ref, typ = new, apitype.OperationTypeReading
+			case deploy.OpUpdate:
+				ref, typ = new, apitype.OperationTypeUpdating
+			case deploy.OpImport, deploy.OpImportReplacement:
+				ref, typ = new, apitype.OperationTypeImporting
+			default:
+				continue
+			}
+			ops, opTypes[ref] = append(ops, ref), typ
+		case journalEntryFailure, journalEntrySuccess:
+			switch e.Op {
+			case deploy.OpCreate, deploy.OpCreateReplacement, deploy.OpRead, deploy.OpReadReplacement, deploy.OpUpdate,
+				deploy.OpImport, deploy.OpImportReplacement:
+				doneOps[new] = true
+			case deploy.OpDelete, deploy.OpDeleteReplaced, deploy.OpReadDiscard, deploy.OpDiscardReplaced:
+				doneOps[old] = true
+			}
+		}
+
+		if e.Kind != journalEntrySuccess {
+			continue
+		}
+		switch e.Op {
+		case deploy.OpSame, deploy.OpUpdate:
+			resources = append(resources, new)
+			dones[old.base] = true
+		case deploy.OpCreate, deploy.OpCreateReplacement:
+			resources = append(resources, new)
+			if old.base != -1 && base.Resources[old.base].PendingReplacement {
+				dones[old.base] = true
+			}
+		case deploy.OpDelete, deploy.OpDeleteReplaced, deploy.OpReadDiscard, deploy.OpDiscardReplaced:
+			if !base.Resources[old.base].PendingReplacement {
+				dones[old.base] = true
+			}
+		case deploy.OpRead, deploy.OpReadReplacement:
+			resources = append(resources, new)
+			if old.base != -1 {
+				dones[old.base] = true
+			}
+		case deploy.OpRemovePendingReplace:
+			dones[old.base] = true
+		case deploy.OpImport, deploy.OpImportReplacement:
+			resources = append(resources, new)
+		}
+	}
+
+	lookup := func(ref journalStateRef) apitype.ResourceV3 {
+		if ref.newID != 0 {
+			return *news[ref.newID]
+		}
+		return base.Resources[ref.base]
+	}
+
+	result := *base
+	result.Resources = nil
+	for _, ref := range resources {
+		result.Resources = append(result.Resources, lookup(ref))
+	}
+	for i, res := range base.Resources {
+		if !dones[i] {
+			result.Resources = append(result.Resources, res)
+		}
+	}
+
+	result.PendingOperations = nil
+	for _, ref := range ops {
+		if !doneOps[ref] {
+			result.PendingOperations = append(result.PendingOperations, apitype.OperationV2{
+				Resource: lookup(ref),
+				Type:     opTypes[ref],
+			})
+		}
+	}
+	for _, op := range base.PendingOperations {
+		if op.Type == apitype.OperationTypeCreating {
+			result.PendingOperations = append(result.PendingOperations, op)
+		}
+	}
+
+	return &result
+}
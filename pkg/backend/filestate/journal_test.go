@@ -0,0 +1,121 @@
+package filestate
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+// journalTestStep is a minimal deploy.Step that carries only what the journal records.
+type journalTestStep struct {
+	deploy.Step
+
+	op       deploy.StepOp
+	old, new *resource.State
+}
+
+func (s *journalTestStep) Op() deploy.StepOp    { return s.op }
+func (s *journalTestStep) Old() *resource.State { return s.old }
+func (s *journalTestStep) New() *resource.State { return s.new }
+
+func (s *journalTestStep) URN() resource.URN {
+	if s.new != nil {
+		return s.new.URN
+	}
+	return s.old.URN
+}
+
+func TestJournalReplay(t *testing.T) {
+	t.Parallel()
+
+	tmpDir, err := ioutil.TempDir("", "filestatebackend")
+	assert.NoError(t, err)
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	stackRef, err := b.ParseStackReference("organization/project/a")
+	assert.NoError(t, err)
+	_, err = b.CreateStack(ctx, stackRef, nil)
+	assert.NoError(t, err)
+	lb := b.(*localBackend)
+	ref := localReference(stackRef)
+
+	newState := func(name tokens.QName, id resource.ID) *resource.State {
+		return &resource.State{
+			Type:    "a:b:c",
+			URN:     resource.NewURN("a", "proj", "", "a:b:c", name),
+			Custom:  true,
+			ID:      id,
+			Inputs:  resource.PropertyMap{"secret": resource.MakeSecret(resource.NewStringProperty(string(name)))},
+			Outputs: resource.PropertyMap{},
+		}
+	}
+
+	sm := b64.NewBase64SecretsManager()
+	base := deploy.NewSnapshot(deploy.Manifest{}, sm, []*resource.State{
+		newState("kept", "1"),
+		newState("updated", "2"),
+		newState("deleted", "3"),
+	}, nil)
+	_, err = lb.saveStack(ref, base, sm)
+	assert.NoError(t, err)
+
+	journal := lb.newJournalSnapshotManager(ref, sm, base)
+	run := func(step *journalTestStep, successful bool) {
+		mutation, err := journal.BeginMutation(step)
+		assert.NoError(t, err)
+		assert.NoError(t, mutation.End(step, successful))
+	}
+
+	updated := newState("updated", "2")
+	updated.Outputs["out"] = resource.NewStringProperty("hello")
+	run(&journalTestStep{op: deploy.OpUpdate, old: base.Resources[1], new: updated}, true)
+	run(&journalTestStep{op: deploy.OpCreate, new: newState("created", "4")}, true)
+	run(&journalTestStep{op: deploy.OpCreate, new: newState("failed", "")}, false)
+	run(&journalTestStep{op: deploy.OpDelete, old: base.Resources[2]}, true)
+
+	// Leave a pending create behind, as if the CLI was killed mid-step.
+	_, err = journal.BeginMutation(&journalTestStep{op: deploy.OpCreate, new: newState("pending", "")})
+	assert.NoError(t, err)
+
+	// Loading the stack replays the journal on top of the checkpoint.
+	assertState := func(snap *deploy.Snapshot) {
+		var urns []resource.URN
+		for _, res := range snap.Resources {
+			urns = append(urns, res.URN)
+		}
+		assert.Equal(t, []resource.URN{
+			resource.NewURN("a", "proj", "", "a:b:c", "updated"),
+			resource.NewURN("a", "proj", "", "a:b:c", "created"),
+			resource.NewURN("a", "proj", "", "a:b:c", "kept"),
+		}, urns)
+		assert.Equal(t, resource.NewStringProperty("hello"), snap.Resources[0].Outputs["out"])
+		assert.True(t, snap.Resources[1].Inputs["secret"].IsSecret())
+		if assert.Len(t, snap.PendingOperations, 1) {
+			assert.Equal(t, resource.OperationTypeCreating, snap.PendingOperations[0].Type)
+			assert.Equal(t, resource.NewURN("a", "proj", "", "a:b:c", "pending"), snap.PendingOperations[0].Resource.URN)
+		}
+	}
+	snap, _, err := lb.getStack(ref)
+	assert.NoError(t, err)
+	assertState(snap)
+
+	// Closing the journal writes the same state to the checkpoint and removes the journal.
+	assert.NoError(t, journal.Close())
+	entries, err := lb.readJournal(ref)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	snap, _, err = lb.getStack(ref)
+	assert.NoError(t, err)
+	assertState(snap)
+}
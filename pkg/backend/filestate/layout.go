@@ -0,0 +1,302 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// UpgradeLayoutEnvVar can be set to a truthy value to upgrade buckets that use the legacy layout, where stacks are
+// identified by name alone, to the project-scoped layout when they are opened. Older versions of the CLI that share
+// an upgraded bucket no longer see its stacks, so buckets are otherwise only upgraded by `pulumi state upgrade`.
+const UpgradeLayoutEnvVar = "PULUMI_FILESTATE_UPGRADE_LAYOUT"
+
+// projectScopedVersion is the version of the layout that stores stacks by project, in .pulumi/stacks/<project>.
+const projectScopedVersion = 1
+
+// pulumiMeta is the contents of the bucket's .pulumi/meta.yaml file, which records how the bucket's stacks are laid
+// out. Buckets written by older versions of the CLI have no such file and use the legacy layout.
+type pulumiMeta struct {
+	// Version is 0 for the legacy layout and 1 for the project-scoped layout.
+	Version int `yaml:"version"`
+}
+
+// projectScoped returns true if stacks are stored by project, so that stacks of different projects may share a name.
+func (m pulumiMeta) projectScoped() bool {
+	return m.Version >= projectScopedVersion
+}
+
+// StackMove describes a stack that was moved into the directory of its project when a bucket was upgraded to the
+// project-scoped layout.
+type StackMove struct {
+	// Stack is the name of the stack.
+	Stack tokens.Name
+	// Project is the project that the stack belongs to.
+	Project tokens.Name
+}
+
+func (b *localBackend) metaPath() string {
+	return filepath.Join(b.StateDir(), "meta.yaml")
+}
+
+// readMeta reads the bucket's meta.yaml file. It returns false if the file does not exist.
+func (b *localBackend) readMeta(ctx context.Context) (pulumiMeta, bool, error) {
+	var meta pulumiMeta
+	exists, err := b.bucket.Exists(ctx, b.metaPath())
+	if err != nil || !exists {
+		return meta, false, err
+	}
+
+	bytes, err := b.bucket.ReadAll(ctx, b.metaPath())
+	if err != nil {
+		return meta, false, fmt.Errorf("could not read %s: %w", b.metaPath(), err)
+	}
+	if err = yaml.Unmarshal(bytes, &meta); err != nil {
+		return meta, false, fmt.Errorf("could not parse %s: %w", b.metaPath(), err)
+	}
+	if meta.Version < 0 || meta.Version > projectScopedVersion {
+		return meta, false, fmt.Errorf("the state in %s uses layout version %d, which this version of the Pulumi CLI "+
+			"does not support; please upgrade the CLI", b.originalURL, meta.Version)
+	}
+	return meta, true, nil
+}
+
+func (b *localBackend) writeMeta(ctx context.Context, meta pulumiMeta) error {
+	bytes, err := yaml.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return b.bucket.WriteAll(ctx, b.metaPath(), bytes, nil)
+}
+
+// initLayout determines the layout of the bucket's stacks. New buckets use the project-scoped layout. Buckets that
+// use the legacy layout keep it, as older versions of the CLI that share them would no longer see any stacks that are
+// moved, unless UpgradeLayoutEnvVar is set.
+func (b *localBackend) initLayout(ctx context.Context) error {
+	meta, exists, err := b.readMeta(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		// A bucket that was explicitly downgraded stays that way until it is explicitly upgraded again.
+		b.meta = meta
+		return nil
+	}
+
+	stacks, err := b.getLocalStacks(nil)
+	if err != nil {
+		return err
+	}
+	if len(stacks) == 0 {
+		b.meta = pulumiMeta{Version: projectScopedVersion}
+		return b.writeMeta(ctx, b.meta)
+	}
+
+	if !cmdutil.IsTruthy(os.Getenv(UpgradeLayoutEnvVar)) {
+		b.warnf("the state in %s identifies stacks by name alone; run `pulumi state upgrade` to scope them to "+
+			"their projects, so that stacks of different projects may share a name", b.originalURL)
+		return nil
+	}
+
+	moves, err := b.Upgrade(ctx, nil)
+	if err != nil {
+		logging.V(5).Infof("error upgrading %s to the project-scoped layout: %v", b.originalURL, err)
+		b.warnf("could not upgrade the state in %s to the project-scoped layout: %v; stacks will continue to be "+
+			"identified by name alone until `pulumi state upgrade` succeeds", b.originalURL, err)
+		return nil
+	}
+	if b.d != nil {
+		for _, move := range moves {
+			b.d.Infof(diag.Message("", "upgraded the state in %s: moved stack '%s' to '%s/%s/%s'"), b.originalURL,
+				move.Stack, localOrganization, move.Project, move.Stack)
+		}
+	}
+	return nil
+}
+
+func (b *localBackend) warnf(format string, args ...interface{}) {
+	if b.d != nil {
+		b.d.Warningf(diag.Message("", format), args...)
+	}
+}
+
+// Upgrade moves the stacks of a bucket that uses the legacy layout into the project-scoped layout, and returns the
+// stacks that it moved. The project of each stack is taken from the resources in its checkpoint. The projects of
+// stacks without resources cannot be determined, so they must be given by projects, which maps the names of stacks
+// to their projects. Nothing is moved unless the project of every stack is known.
+func (b *localBackend) Upgrade(ctx context.Context, projects map[tokens.Name]tokens.Name) ([]StackMove, error) {
+	if b.meta.projectScoped() {
+		return nil, nil
+	}
+
+	stacks, err := b.getLocalStacks(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	moves := make([]StackMove, 0, len(stacks))
+	for _, ref := range stacks {
+		project, err := b.stackProject(ref, projects)
+		if err != nil {
+			return nil, err
+		}
+		if project == "" {
+			unknown = append(unknown, string(ref.name))
+			continue
+		}
+		if err = b.checkForLock(ctx, ref); err != nil {
+			return nil, fmt.Errorf("stack '%s': %w", ref, err)
+		}
+		moves = append(moves, StackMove{Stack: ref.name, Project: project})
+	}
+	if len(unknown) != 0 {
+		return nil, fmt.Errorf("could not determine the projects of stacks without resources: %s; "+
+			"give their projects with `pulumi state upgrade --project <stack>=<project>`", strings.Join(unknown, ", "))
+	}
+
+	for _, move := range moves {
+		from := localBackendReference{name: move.Stack, currentProject: b.currentProject}
+		to := localBackendReference{name: move.Stack, project: move.Project, currentProject: b.currentProject}
+		if err = b.moveStackFiles(ctx, from, to); err != nil {
+			return nil, fmt.Errorf("moving stack '%s': %w", from, err)
+		}
+	}
+
+	b.meta = pulumiMeta{Version: projectScopedVersion}
+	return moves, b.writeMeta(ctx, b.meta)
+}
+
+// Downgrade moves the stacks of a bucket that uses the project-scoped layout back into the legacy layout. The bucket
+// is marked as downgraded so that it is not upgraded again the next time it is opened.
+func (b *localBackend) Downgrade(ctx context.Context) error {
+	if !b.meta.projectScoped() {
+		return nil
+	}
+
+	stacks, err := b.getLocalStacks(nil)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[tokens.Name]localBackendReference)
+	for _, ref := range stacks {
+		if other, has := seen[ref.name]; has {
+			return fmt.Errorf("stacks '%s' and '%s' have the same name; "+
+				"rename one of them before downgrading", other, ref)
+		}
+		seen[ref.name] = ref
+		if err = b.checkForLock(ctx, ref); err != nil {
+			return fmt.Errorf("stack '%s': %w", ref, err)
+		}
+	}
+
+	for _, ref := range stacks {
+		target := localBackendReference{name: ref.name, currentProject: b.currentProject}
+		if err = b.moveStackFiles(ctx, ref, target); err != nil {
+			return fmt.Errorf("moving stack '%s': %w", ref, err)
+		}
+	}
+
+	b.meta = pulumiMeta{Version: 0}
+	return b.writeMeta(ctx, b.meta)
+}
+
+// stackProject determines the project of a stack stored in the legacy layout from the resources in its checkpoint, or
+// from the given projects if it has no resources. It returns an empty name if the project cannot be determined.
+func (b *localBackend) stackProject(ref localBackendReference,
+	projects map[tokens.Name]tokens.Name) (tokens.Name, error) {
+
+	chk, err := b.getCheckpoint(ref)
+	if err != nil {
+		return "", fmt.Errorf("reading stack '%s': %w", ref, err)
+	}
+	given, hasGiven := projects[ref.name]
+	if chk.Latest != nil && len(chk.Latest.Resources) > 0 {
+		project := tokens.Name(chk.Latest.Resources[0].URN.Project())
+		if hasGiven && given != project {
+			return "", fmt.Errorf("stack '%s' belongs to project '%s', not '%s'", ref, project, given)
+		}
+		return project, nil
+	}
+	return given, nil
+}
+
+// moveStackFiles moves the checkpoint, tags, history, backups and journal of a stack from one location to another, and
+// updates the policy groups that refer to it.
+func (b *localBackend) moveStackFiles(ctx context.Context, from, to localBackendReference) error {
+	b.stackPaths.Delete(b.plainStackPath(from))
+	b.stackPaths.Delete(b.plainStackPath(to))
+	for _, suffix := range []string{"", ".bak", gzipExt, gzipExt + ".bak", ".tags"} {
+		if err := moveObject(ctx, b.bucket, b.plainStackPath(from)+suffix, b.plainStackPath(to)+suffix); err != nil {
+			return err
+		}
+	}
+
+	dirs := []func(localBackendReference) string{b.historyDirectory, b.backupDirectory, b.journalDirectory}
+	for _, dir := range dirs {
+		if err := moveDirectory(ctx, b.bucket, dir(from), dir(to)); err != nil {
+			return err
+		}
+	}
+
+	// Policy groups refer to stacks by their fully qualified names, which depend on the layout.
+	return b.renameStackInPolicyGroups(ctx, from, &to, "")
+}
+
+// moveObject moves the object src to dst, if it exists.
+func moveObject(ctx context.Context, bucket Bucket, src, dst string) error {
+	exists, err := bucket.Exists(ctx, src)
+	if err != nil || !exists {
+		return err
+	}
+	if err = bucket.Copy(ctx, dst, src, nil); err != nil {
+		return err
+	}
+	return bucket.Delete(ctx, src)
+}
+
+// moveDirectory moves the objects directly within the directory src into the directory dst.
+func moveDirectory(ctx context.Context, bucket Bucket, src, dst string) error {
+	files, err := listBucket(bucket, src)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.IsDir {
+			continue
+		}
+		if err = bucket.Copy(ctx, path.Join(filepath.ToSlash(dst), objectName(file)), file.Key, nil); err != nil {
+			return err
+		}
+		if err = bucket.Delete(ctx, file.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
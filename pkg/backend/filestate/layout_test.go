@@ -0,0 +1,155 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func TestNewBucketIsProjectScoped(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	require.NoError(t, err)
+	assert.True(t, b.(*localBackend).meta.projectScoped())
+	assert.FileExists(t, filepath.Join(tmpDir, ".pulumi", "meta.yaml"))
+
+	// Without a current project, stack names must be fully qualified.
+	_, err = b.ParseStackReference("dev")
+	assert.Error(t, err)
+	_, err = b.ParseStackReference("org/proj/dev")
+	assert.Error(t, err)
+	ref, err := b.ParseStackReference("organization/proj/dev")
+	require.NoError(t, err)
+	assert.Equal(t, "organization/proj/dev", ref.String())
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestLayoutUpgradeAndDowngrade(t *testing.T) {
+	tmpDir := t.TempDir()
+	url := "file://" + filepath.ToSlash(tmpDir)
+	ctx := context.Background()
+
+	// Write stacks using the legacy layout, as older versions of the CLI do.
+	b, err := New(cmdutil.Diag(), url)
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+	require.NoError(t, lb.Downgrade(ctx))
+	assert.False(t, lb.meta.projectScoped())
+
+	_, err = b.ParseStackReference("organization/proj/dev")
+	assert.Error(t, err)
+	ref, err := b.ParseStackReference("dev")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+	emptyRef, err := b.ParseStackReference("empty")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, emptyRef, nil)
+	require.NoError(t, err)
+
+	sm := b64.NewBase64SecretsManager()
+	resources := []*resource.State{{URN: resource.NewURN("dev", "proj", "", "a:b:c", "res"), Type: "a:b:c"}}
+	_, err = lb.saveStack(localReference(ref), deploy.NewSnapshot(deploy.Manifest{}, sm, resources, nil), sm)
+	require.NoError(t, err)
+	require.NoError(t, lb.addToHistory(localReference(ref), backend.UpdateInfo{Kind: apitype.UpdateUpdate}))
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, ".pulumi", "meta.yaml")))
+
+	// Opening the bucket again does not move its stacks, as older versions of the CLI would no longer see them.
+	b, err = New(cmdutil.Diag(), url)
+	require.NoError(t, err)
+	assert.False(t, b.(*localBackend).meta.projectScoped())
+	assert.FileExists(t, filepath.Join(tmpDir, ".pulumi", "stacks", "dev.json"))
+
+	// Nor does opening it with automatic upgrades enabled, as the project of the stack without resources is unknown.
+	t.Setenv(UpgradeLayoutEnvVar, "true")
+	b, err = New(cmdutil.Diag(), url)
+	require.NoError(t, err)
+	lb = b.(*localBackend)
+	assert.False(t, lb.meta.projectScoped())
+	assert.FileExists(t, filepath.Join(tmpDir, ".pulumi", "stacks", "dev.json"))
+	assert.FileExists(t, filepath.Join(tmpDir, ".pulumi", "stacks", "empty.json"))
+
+	_, err = lb.Upgrade(ctx, map[tokens.Name]tokens.Name{"dev": "other", "empty": "misc"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "belongs to project 'proj'")
+	}
+
+	// Stacks are moved into the directories of the projects their resources belong to, or that are given.
+	moves, err := lb.Upgrade(ctx, map[tokens.Name]tokens.Name{"empty": "misc"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []StackMove{{Stack: "dev", Project: "proj"}, {Stack: "empty", Project: "misc"}}, moves)
+	assert.True(t, lb.meta.projectScoped())
+	assert.FileExists(t, filepath.Join(tmpDir, ".pulumi", "stacks", "proj", "dev.json"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, ".pulumi", "stacks", "dev.json"))
+
+	ref, err = b.ParseStackReference("organization/proj/dev")
+	require.NoError(t, err)
+	history, err := b.GetHistory(ctx, ref, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	// A stack of another project may share its name, and stacks can be listed by project.
+	otherRef, err := b.ParseStackReference("organization/other/dev")
+	require.NoError(t, err)
+	other, err := b.CreateStack(ctx, otherRef, nil)
+	require.NoError(t, err)
+
+	project := "proj"
+	stacks, _, err := b.ListStacks(ctx, backend.ListStacksFilter{Project: &project}, nil)
+	require.NoError(t, err)
+	if assert.Len(t, stacks, 1) {
+		assert.Equal(t, "organization/proj/dev", stacks[0].Name().String())
+	}
+	stacks, _, err = b.ListStacks(ctx, backend.ListStacksFilter{}, nil)
+	require.NoError(t, err)
+	assert.Len(t, stacks, 3)
+
+	// Stacks that share a name cannot be downgraded.
+	err = lb.Downgrade(ctx)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "have the same name")
+	}
+	_, err = b.RemoveStack(ctx, other, false)
+	require.NoError(t, err)
+
+	require.NoError(t, lb.Downgrade(ctx))
+	assert.FileExists(t, filepath.Join(tmpDir, ".pulumi", "stacks", "dev.json"))
+
+	// A downgraded bucket is not upgraded again when it is opened.
+	b, err = New(cmdutil.Diag(), url)
+	require.NoError(t, err)
+	assert.False(t, b.(*localBackend).meta.projectScoped())
+	ref, err = b.ParseStackReference("dev")
+	require.NoError(t, err)
+	history, err = b.GetHistory(ctx, ref, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
@@ -22,21 +22,33 @@ import (
 	"os"
 	"os/user"
 	"path"
+	"sort"
 	"time"
 
+	"gocloud.dev/gcerrors"
+
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/util/fsutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
+// lockLeaseDuration is how long a lock remains valid without being renewed. A lock whose lease has expired is stale:
+// the process that took it has stopped renewing it, most likely because it crashed or was killed.
+const lockLeaseDuration = 5 * time.Minute
+
+// lockRenewInterval is how often a process holding a lock renews its lease.
+const lockRenewInterval = time.Minute
+
 type lockContent struct {
 	Pid       int       `json:"pid"`
 	Username  string    `json:"username"`
 	Hostname  string    `json:"hostname"`
 	Timestamp time.Time `json:"timestamp"`
+	// Expires is the time at which the lock's lease expires unless it is renewed. Locks written by older versions
+	// of the CLI have no lease.
+	Expires time.Time `json:"expires,omitempty"`
 }
 
 func newLockContent() (*lockContent, error) {
@@ -48,53 +60,93 @@ func newLockContent() (*lockContent, error) {
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now()
 	return &lockContent{
 		Pid:       os.Getpid(),
 		Username:  u.Username,
 		Hostname:  hostname,
-		Timestamp: time.Now(),
+		Timestamp: now,
+		Expires:   now.Add(lockLeaseDuration),
 	}, nil
 }
 
-// checkForLock looks for any existing locks for this stack, and returns a helpful diagnostic if there is one.
-func (b *localBackend) checkForLock(ctx context.Context, stackRef backend.StackReference) error {
-	allFiles, err := listBucket(b.bucket, stackLockDir(stackRef.Name()))
+// isStale returns true if the lock's lease has expired.
+func (l *lockContent) isStale(now time.Time) bool {
+	return !l.Expires.IsZero() && now.After(l.Expires)
+}
+
+// describe returns a description of the lock's holder and whether it is still alive.
+func (l *lockContent) describe(now time.Time) string {
+	desc := fmt.Sprintf("created by %v@%v (pid %v) at %v", l.Username, l.Hostname, l.Pid, l.Timestamp.Format(time.RFC3339))
+	switch {
+	case l.Expires.IsZero():
+		return desc + "; it has no lease, so it is unknown whether its holder is still running"
+	case l.isStale(now):
+		return desc + fmt.Sprintf("; its lease expired at %v, so its holder is no longer running (stale lock)",
+			l.Expires.Format(time.RFC3339))
+	default:
+		return desc + fmt.Sprintf("; its holder is still running (lease expires at %v)", l.Expires.Format(time.RFC3339))
+	}
+}
+
+// lockLease tracks the goroutine that renews a lock held by this backend.
+type lockLease struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// readLocks reads the locks held on the given stack by other backends, keyed by their paths.
+func (b *localBackend) readLocks(ctx context.Context, stackRef backend.StackReference) (map[string]*lockContent, error) {
+	ref := localReference(stackRef)
+	allFiles, err := listBucket(b.bucket, stackLockDir(ref))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var lockKeys []string
+	locks := make(map[string]*lockContent)
 	for _, file := range allFiles {
-		if file.IsDir {
+		if file.IsDir || file.Key == b.lockPath(ref) {
 			continue
 		}
-		if file.Key != b.lockPath(stackRef.Name()) {
-			lockKeys = append(lockKeys, file.Key)
+
+		content, err := b.bucket.ReadAll(ctx, file.Key)
+		if err != nil {
+			return nil, err
+		}
+		l := &lockContent{}
+		if err = json.Unmarshal(content, &l); err != nil {
+			return nil, err
 		}
+		locks[file.Key] = l
+	}
+	return locks, nil
+}
+
+// checkForLock looks for any existing locks for this stack, and returns a helpful diagnostic if there is one.
+func (b *localBackend) checkForLock(ctx context.Context, stackRef backend.StackReference) error {
+	locks, err := b.readLocks(ctx, stackRef)
+	if err != nil {
+		return err
 	}
 
-	if len(lockKeys) > 0 {
+	if len(locks) > 0 {
 		errorString := fmt.Sprintf("the stack is currently locked by %v lock(s). Either wait for the other "+
-			"process(es) to end or manually delete the lock file(s).", len(lockKeys))
+			"process(es) to end or manually delete the lock file(s).", len(locks))
 
-		for _, lock := range lockKeys {
-			content, err := b.bucket.ReadAll(ctx, lock)
-			if err != nil {
-				return err
-			}
-			l := &lockContent{}
-			err = json.Unmarshal(content, &l)
-			if err != nil {
-				return err
-			}
+		lockKeys := make([]string, 0, len(locks))
+		for key := range locks {
+			lockKeys = append(lockKeys, key)
+		}
+		sort.Strings(lockKeys)
 
-			errorString += fmt.Sprintf("\n  %v: created by %v@%v (pid %v) at %v",
-				b.url+"/"+lock,
-				l.Username,
-				l.Hostname,
-				l.Pid,
-				l.Timestamp.Format(time.RFC3339),
-			)
+		now, stale := time.Now(), false
+		for _, lock := range lockKeys {
+			l := locks[lock]
+			stale = stale || l.isStale(now)
+			errorString += fmt.Sprintf("\n  %v: %v", b.url+"/"+lock, l.describe(now))
+		}
+		if stale {
+			errorString += "\nStale locks can be safely removed with `pulumi cancel --break-stale-lock`."
 		}
 
 		return errors.New(errorString)
@@ -103,51 +155,144 @@ func (b *localBackend) checkForLock(ctx context.Context, stackRef backend.StackR
 }
 
 func (b *localBackend) Lock(ctx context.Context, stackRef backend.StackReference) error {
-	//
+	ref := localReference(stackRef)
+
+	// Reserve the stack's lease first. Every lock this process takes on the stack shares the same lock file, so a
+	// second lock would leave the first lease renewing the lock after Unlock deletes it.
+	lease := &lockLease{stop: make(chan struct{}), done: make(chan struct{})}
+	b.leasesMutex.Lock()
+	if _, held := b.leases[ref.pathName()]; held {
+		b.leasesMutex.Unlock()
+		return errors.New("the stack is already locked by this process")
+	}
+	if b.leases == nil {
+		b.leases = make(map[string]*lockLease)
+	}
+	b.leases[ref.pathName()] = lease
+	b.leasesMutex.Unlock()
+	release := func() {
+		b.leasesMutex.Lock()
+		defer b.leasesMutex.Unlock()
+		delete(b.leases, ref.pathName())
+		close(lease.done)
+	}
+
 	err := b.checkForLock(ctx, stackRef)
 	if err != nil {
+		release()
 		return err
 	}
-	lockContent, err := newLockContent()
-	if err != nil {
+	if err = b.writeLock(ctx, ref); err != nil {
+		release()
 		return err
 	}
-	content, err := json.Marshal(lockContent)
+	err = b.checkForLock(ctx, stackRef)
 	if err != nil {
+		release()
+		b.Unlock(ctx, stackRef)
 		return err
 	}
-	err = b.bucket.WriteAll(ctx, b.lockPath(stackRef.Name()), content, nil)
+
+	// Keep renewing the lock's lease for as long as we hold it.
+	go func() {
+		defer close(lease.done)
+
+		ticker := time.NewTicker(lockRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// If the lock has been deleted (e.g. by `pulumi cancel`), don't take it again.
+				exists, err := b.bucket.Exists(context.Background(), b.lockPath(ref))
+				if err == nil && !exists {
+					return
+				}
+				if err == nil {
+					err = b.writeLock(context.Background(), ref)
+				}
+				if err != nil {
+					logging.V(5).Infof("error renewing lock %v: %v", b.lockPath(ref), err)
+				}
+			case <-lease.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// writeLock writes this backend's lock for the given stack, with a fresh lease.
+func (b *localBackend) writeLock(ctx context.Context, ref localBackendReference) error {
+	lockContent, err := newLockContent()
 	if err != nil {
 		return err
 	}
-	err = b.checkForLock(ctx, stackRef)
+	content, err := json.Marshal(lockContent)
 	if err != nil {
-		b.Unlock(ctx, stackRef)
 		return err
 	}
-	return nil
+	return b.bucket.WriteAll(ctx, b.lockPath(ref), content, nil)
 }
 
 func (b *localBackend) Unlock(ctx context.Context, stackRef backend.StackReference) {
-	err := b.bucket.Delete(ctx, b.lockPath(stackRef.Name()))
+	// Stop renewing the lease first, so that the lock isn't written again after we delete it.
+	ref := localReference(stackRef)
+	b.leasesMutex.Lock()
+	lease, has := b.leases[ref.pathName()]
+	delete(b.leases, ref.pathName())
+	b.leasesMutex.Unlock()
+	if has {
+		close(lease.stop)
+		<-lease.done
+	}
+
+	err := b.bucket.Delete(ctx, b.lockPath(ref))
 	if err != nil {
 		b.d.Errorf(
 			diag.Message("", "there was a problem deleting the lock at %v, manual clean up may be required: %v"),
-			path.Join(b.url, b.lockPath(stackRef.Name())),
+			path.Join(b.url, b.lockPath(ref)),
 			err)
 	}
 }
 
+// BreakStaleLocks deletes the locks on the given stack whose leases have expired, leaving locks that are still held
+// by running processes in place. It returns the number of locks that were deleted.
+func (b *localBackend) BreakStaleLocks(ctx context.Context, stackRef backend.StackReference) (int, error) {
+	locks, err := b.readLocks(ctx, stackRef)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	broken, now := 0, time.Now()
+	for key, l := range locks {
+		if !l.isStale(now) {
+			continue
+		}
+		if err := b.bucket.Delete(ctx, key); err != nil {
+			// Another process may have broken the lock between us reading and deleting it.
+			if gcerrors.Code(err) == gcerrors.NotFound {
+				continue
+			}
+			return broken, err
+		}
+		broken++
+	}
+	return broken, nil
+}
+
 func lockDir() string {
 	return path.Join(workspace.BookkeepingDir, workspace.LockDir)
 }
 
-func stackLockDir(stack tokens.Name) string {
-	contract.Require(stack != "", "stack")
-	return path.Join(lockDir(), fsutil.NamePath(stack))
+func stackLockDir(ref localBackendReference) string {
+	contract.Require(ref.name != "", "ref.name")
+	return path.Join(lockDir(), ref.pathName())
 }
 
-func (b *localBackend) lockPath(stack tokens.Name) string {
-	contract.Require(stack != "", "stack")
-	return path.Join(stackLockDir(stack), b.lockID+".json")
+func (b *localBackend) lockPath(ref localBackendReference) string {
+	contract.Require(ref.name != "", "ref.name")
+	return path.Join(stackLockDir(ref), b.lockID+".json")
 }
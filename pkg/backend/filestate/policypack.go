@@ -0,0 +1,676 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	resourceanalyzer "github.com/pulumi/pulumi/pkg/v3/resource/analyzer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// defaultPolicyGroup is the policy group that applies to every stack in the backend, like the default policy group of
+// an organization in the Pulumi service.
+const defaultPolicyGroup = "default-policy-group"
+
+// localPolicyPackVersion describes a published version of a policy pack. It is stored next to the version's tarball.
+type localPolicyPackVersion struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	// Version is the number of the version, counting from 1 in the order the versions were published.
+	Version    int              `json:"version"`
+	VersionTag string           `json:"versionTag"`
+	Policies   []apitype.Policy `json:"policies"`
+}
+
+// key returns the name under which the version's files are stored. Policy packs built with older versions of
+// pulumi/policy have no version tag.
+func (v localPolicyPackVersion) key() string {
+	if v.VersionTag != "" {
+		return v.VersionTag
+	}
+	return strconv.Itoa(v.Version)
+}
+
+// requiredPolicyKey returns the key of the version of a policy pack that is enabled for a policy group.
+func requiredPolicyKey(p apitype.RequiredPolicy) string {
+	return localPolicyPackVersion{Version: p.Version, VersionTag: p.VersionTag}.key()
+}
+
+// localPolicyGroup is a set of policy packs that are enforced on a set of stacks.
+type localPolicyGroup struct {
+	Name string `json:"name"`
+	// Stacks holds the fully qualified names of the stacks the group applies to. The default policy group applies to
+	// every stack instead.
+	Stacks []string `json:"stacks,omitempty"`
+	// PolicyPacks are the policy packs enabled for the group.
+	PolicyPacks []apitype.RequiredPolicy `json:"policyPacks,omitempty"`
+}
+
+func (g *localPolicyGroup) appliesTo(stack string) bool {
+	if g.Name == defaultPolicyGroup {
+		return true
+	}
+	for _, s := range g.Stacks {
+		if s == stack {
+			return true
+		}
+	}
+	return false
+}
+
+// localRequiredPolicy is a policy pack that is enforced on a stack because it is enabled for one of the stack's
+// policy groups.
+type localRequiredPolicy struct {
+	apitype.RequiredPolicy
+	b *localBackend
+}
+
+var _ engine.RequiredPolicy = (*localRequiredPolicy)(nil)
+
+func (rp *localRequiredPolicy) Name() string    { return rp.RequiredPolicy.Name }
+func (rp *localRequiredPolicy) Version() string { return strconv.Itoa(rp.RequiredPolicy.Version) }
+
+func (rp *localRequiredPolicy) Install(ctx context.Context) (string, error) {
+	policy := rp.RequiredPolicy
+
+	version := requiredPolicyKey(policy)
+	policyPackPath, installed, err := workspace.GetPolicyPath(localOrganization,
+		strings.Replace(policy.Name, tokens.QNameDelimiter, "_", -1), version)
+	if err != nil {
+		return "", err
+	} else if installed {
+		return policyPackPath, nil
+	}
+
+	fmt.Printf("Installing policy pack %s %s...\n", policy.Name, version)
+
+	tarball, err := rp.b.bucket.ReadAll(ctx, policy.PackLocation)
+	if err != nil {
+		return "", fmt.Errorf("reading policy pack %s %s: %w", policy.Name, version, err)
+	}
+	return policyPackPath, backend.InstallPolicyPack(policyPackPath, ioutil.NopCloser(bytes.NewReader(tarball)))
+}
+
+func (rp *localRequiredPolicy) Config() map[string]*json.RawMessage { return rp.RequiredPolicy.Config }
+
+// localPolicyPackReference is a reference to a policy pack published to a local backend.
+type localPolicyPackReference struct {
+	name tokens.QName
+}
+
+var _ backend.PolicyPackReference = localPolicyPackReference{}
+
+func (r localPolicyPackReference) String() string {
+	return fmt.Sprintf("%s/%s", localOrganization, r.name)
+}
+
+func (r localPolicyPackReference) OrgName() string {
+	return localOrganization
+}
+
+func (r localPolicyPackReference) Name() tokens.QName {
+	return r.name
+}
+
+// localPolicyPack is a policy pack published to a local backend.
+type localPolicyPack struct {
+	ref localPolicyPackReference
+	b   *localBackend
+}
+
+var _ backend.PolicyPack = (*localPolicyPack)(nil)
+
+func (pack *localPolicyPack) Ref() backend.PolicyPackReference {
+	return pack.ref
+}
+
+func (pack *localPolicyPack) Backend() backend.Backend {
+	return pack.b
+}
+
+func (pack *localPolicyPack) Publish(ctx context.Context, op backend.PublishOperation) result.Result {
+	analyzerInfo, packTarball, err := backend.PackPolicyPack(op)
+	if err != nil {
+		return result.FromError(err)
+	}
+	pack.ref.name = tokens.QName(analyzerInfo.Name)
+	if analyzerInfo.Version != "" && (strings.ContainsAny(analyzerInfo.Version, `/\`) ||
+		analyzerInfo.Version == "." || analyzerInfo.Version == "..") {
+		return result.Errorf("invalid version %q of policy pack %s", analyzerInfo.Version, analyzerInfo.Name)
+	}
+
+	versions, err := pack.b.getPolicyPackVersions(ctx, analyzerInfo.Name)
+	if err != nil {
+		return result.FromError(err)
+	}
+	version := localPolicyPackVersion{
+		Name:        analyzerInfo.Name,
+		DisplayName: analyzerInfo.DisplayName,
+		Version:     len(versions) + 1,
+		VersionTag:  analyzerInfo.Version,
+	}
+	for _, v := range versions {
+		if v.key() == version.key() {
+			return result.Errorf("version %s of policy pack %s has already been published", v.key(), v.Name)
+		}
+		if v.Version >= version.Version {
+			version.Version = v.Version + 1
+		}
+	}
+	for _, policy := range analyzerInfo.Policies {
+		configSchema, err := resourceanalyzer.ConvertPolicyConfigSchema(policy.ConfigSchema)
+		if err != nil {
+			return result.FromError(err)
+		}
+		version.Policies = append(version.Policies, apitype.Policy{
+			Name:             policy.Name,
+			DisplayName:      policy.DisplayName,
+			Description:      policy.Description,
+			EnforcementLevel: policy.EnforcementLevel,
+			Message:          policy.Message,
+			ConfigSchema:     configSchema,
+		})
+	}
+
+	fmt.Printf("Publishing %q - version %s to %s\n", version.Name, version.key(), pack.b.originalURL)
+
+	// Write the tarball first, so that a version whose metadata exists can always be installed.
+	if err = pack.b.bucket.WriteAll(ctx, pack.b.policyPackPath(version, ".tgz"), packTarball, nil); err != nil {
+		return result.FromError(fmt.Errorf("uploading policy pack: %w", err))
+	}
+	byts, err := json.MarshalIndent(version, "", "    ")
+	if err != nil {
+		return result.FromError(err)
+	}
+	if err = pack.b.bucket.WriteAll(ctx, pack.b.policyPackPath(version, ".json"), byts, nil); err != nil {
+		return result.FromError(fmt.Errorf("uploading policy pack: %w", err))
+	}
+	return nil
+}
+
+func (pack *localPolicyPack) Enable(ctx context.Context, policyGroup string, op backend.PolicyPackOperation) error {
+	version, err := pack.b.getPolicyPackVersion(ctx, string(pack.ref.name), op.VersionTag)
+	if err != nil {
+		return err
+	}
+	if op.Config != nil {
+		if err = validatePolicyPackConfig(version, op.Config); err != nil {
+			return err
+		}
+	}
+
+	group, err := pack.b.getPolicyGroup(ctx, policyGroup)
+	if err != nil {
+		return err
+	}
+	policy := apitype.RequiredPolicy{
+		Name:         version.Name,
+		Version:      version.Version,
+		VersionTag:   version.VersionTag,
+		DisplayName:  version.DisplayName,
+		PackLocation: filepath.ToSlash(pack.b.policyPackPath(version, ".tgz")),
+		Config:       op.Config,
+	}
+
+	// Enabling a policy pack replaces any other version of it that is enabled for the group.
+	replaced := false
+	for i, p := range group.PolicyPacks {
+		if p.Name == policy.Name {
+			group.PolicyPacks[i], replaced = policy, true
+		}
+	}
+	if !replaced {
+		group.PolicyPacks = append(group.PolicyPacks, policy)
+	}
+	return pack.b.savePolicyGroup(ctx, group)
+}
+
+func (pack *localPolicyPack) Disable(ctx context.Context, policyGroup string, op backend.PolicyPackOperation) error {
+	group, err := pack.b.getPolicyGroup(ctx, policyGroup)
+	if err != nil {
+		return err
+	}
+
+	var remaining []apitype.RequiredPolicy
+	for _, p := range group.PolicyPacks {
+		if p.Name != string(pack.ref.name) || op.VersionTag != nil && requiredPolicyKey(p) != *op.VersionTag {
+			remaining = append(remaining, p)
+		}
+	}
+	if len(remaining) == len(group.PolicyPacks) {
+		return fmt.Errorf("policy pack %s is not enabled for policy group %s", pack.ref.name, group.Name)
+	}
+	group.PolicyPacks = remaining
+	return pack.b.savePolicyGroup(ctx, group)
+}
+
+func (pack *localPolicyPack) Validate(ctx context.Context, op backend.PolicyPackOperation) error {
+	version, err := pack.b.getPolicyPackVersion(ctx, string(pack.ref.name), op.VersionTag)
+	if err != nil {
+		return err
+	}
+	return validatePolicyPackConfig(version, op.Config)
+}
+
+func (pack *localPolicyPack) Remove(ctx context.Context, op backend.PolicyPackOperation) error {
+	versions, err := pack.b.getPolicyPackVersions(ctx, string(pack.ref.name))
+	if err != nil {
+		return err
+	}
+	if op.VersionTag != nil {
+		version, err := pack.b.getPolicyPackVersion(ctx, string(pack.ref.name), op.VersionTag)
+		if err != nil {
+			return err
+		}
+		versions = []localPolicyPackVersion{version}
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("policy pack %s has not been published", pack.ref.name)
+	}
+
+	groups, err := pack.b.getPolicyGroups(ctx)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		for _, group := range groups {
+			for _, p := range group.PolicyPacks {
+				if p.Name == version.Name && p.Version == version.Version {
+					return fmt.Errorf("version %s of policy pack %s is enabled for policy group %s; "+
+						"disable it before removing it", version.key(), version.Name, group.Name)
+				}
+			}
+		}
+	}
+
+	for _, version := range versions {
+		for _, ext := range []string{".json", ".tgz"} {
+			err := pack.b.bucket.Delete(ctx, pack.b.policyPackPath(version, ext))
+			if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validatePolicyPackConfig validates configuration for a policy pack against the schemas of its policies.
+func validatePolicyPackConfig(version localPolicyPackVersion, config map[string]*json.RawMessage) error {
+	schemas := make(map[string]apitype.PolicyConfigSchema)
+	for _, policy := range version.Policies {
+		if policy.ConfigSchema != nil {
+			schemas[policy.Name] = *policy.ConfigSchema
+		}
+	}
+	return resourceanalyzer.ValidatePolicyPackConfig(schemas, config)
+}
+
+// parsePolicyPackReference parses a reference of the form [<org-name>/]<policy-pack-name>. The local backend has no
+// organizations, so any organization name is accepted.
+func (b *localBackend) parsePolicyPackReference(s string) (localPolicyPackReference, error) {
+	split := strings.Split(s, "/")
+	if len(split) > 2 {
+		return localPolicyPackReference{}, fmt.Errorf("could not parse policy pack name '%s'; must be of the form "+
+			"<org-name>/<policy-pack-name>", s)
+	}
+	// The name is empty when publishing, as it comes from the policy pack that is published.
+	name := split[len(split)-1]
+	if name != "" {
+		if err := validatePolicyName("policy pack", name); err != nil {
+			return localPolicyPackReference{}, err
+		}
+	}
+	return localPolicyPackReference{name: tokens.QName(name)}, nil
+}
+
+func (b *localBackend) GetPolicyPack(ctx context.Context, policyPack string,
+	d diag.Sink) (backend.PolicyPack, error) {
+
+	ref, err := b.parsePolicyPackReference(policyPack)
+	if err != nil {
+		return nil, err
+	}
+	return &localPolicyPack{ref: ref, b: b}, nil
+}
+
+func (b *localBackend) ListPolicyGroups(ctx context.Context, orgName string, _ backend.ContinuationToken) (
+	apitype.ListPolicyGroupsResponse, backend.ContinuationToken, error) {
+
+	groups, err := b.getPolicyGroups(ctx)
+	if err != nil {
+		return apitype.ListPolicyGroupsResponse{}, nil, err
+	}
+
+	var resp apitype.ListPolicyGroupsResponse
+	for _, group := range groups {
+		numStacks := len(group.Stacks)
+		if group.Name == defaultPolicyGroup {
+			stacks, err := b.getLocalStacks(nil)
+			if err != nil {
+				return apitype.ListPolicyGroupsResponse{}, nil, err
+			}
+			numStacks = len(stacks)
+		}
+		resp.PolicyGroups = append(resp.PolicyGroups, apitype.PolicyGroupSummary{
+			Name:                  group.Name,
+			IsOrgDefault:          group.Name == defaultPolicyGroup,
+			NumStacks:             numStacks,
+			NumEnabledPolicyPacks: len(group.PolicyPacks),
+		})
+	}
+	return resp, nil, nil
+}
+
+func (b *localBackend) ListPolicyPacks(ctx context.Context, orgName string, _ backend.ContinuationToken) (
+	apitype.ListPolicyPacksResponse, backend.ContinuationToken, error) {
+
+	files, err := listBucket(b.bucket, b.policyPacksDirectory())
+	if err != nil {
+		return apitype.ListPolicyPacksResponse{}, nil, err
+	}
+
+	var resp apitype.ListPolicyPacksResponse
+	for _, file := range files {
+		if !file.IsDir {
+			continue
+		}
+		versions, err := b.getPolicyPackVersions(ctx, path.Base(file.Key))
+		if err != nil {
+			return apitype.ListPolicyPacksResponse{}, nil, err
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		pack := apitype.PolicyPackWithVersions{
+			Name:        versions[0].Name,
+			DisplayName: versions[len(versions)-1].DisplayName,
+		}
+		for _, v := range versions {
+			pack.Versions = append(pack.Versions, v.Version)
+			pack.VersionTags = append(pack.VersionTags, v.key())
+		}
+		resp.PolicyPacks = append(resp.PolicyPacks, pack)
+	}
+	return resp, nil, nil
+}
+
+// AddStackToPolicyGroup adds a stack to a policy group, creating the group if it does not exist.
+func (b *localBackend) AddStackToPolicyGroup(ctx context.Context, policyGroup string,
+	stackRef backend.StackReference) error {
+
+	if policyGroup == "" || policyGroup == defaultPolicyGroup {
+		return errors.New("every stack belongs to the default policy group")
+	}
+
+	stack := fullyQualifiedName(localReference(stackRef))
+	group, err := b.getPolicyGroup(ctx, policyGroup)
+	if err != nil {
+		return err
+	}
+	if group.appliesTo(stack) {
+		return nil
+	}
+	group.Stacks = append(group.Stacks, stack)
+	return b.savePolicyGroup(ctx, group)
+}
+
+// RemoveStackFromPolicyGroup removes a stack from a policy group.
+func (b *localBackend) RemoveStackFromPolicyGroup(ctx context.Context, policyGroup string,
+	stackRef backend.StackReference) error {
+
+	if policyGroup == "" || policyGroup == defaultPolicyGroup {
+		return errors.New("stacks cannot be removed from the default policy group")
+	}
+
+	stack := fullyQualifiedName(localReference(stackRef))
+	group, err := b.getPolicyGroup(ctx, policyGroup)
+	if err != nil {
+		return err
+	}
+	if !group.appliesTo(stack) {
+		return fmt.Errorf("stack %s is not in policy group %s", stack, group.Name)
+	}
+	return b.renameStackInPolicyGroups(ctx, localReference(stackRef), nil, group.Name)
+}
+
+// getRequiredPolicies returns the policy packs enabled for the policy groups of the given stack. If a policy pack is
+// enabled for several of the stack's groups, the version enabled for the default policy group wins, followed by the
+// other groups in order of their names.
+func (b *localBackend) getRequiredPolicies(ctx context.Context,
+	ref localBackendReference) ([]engine.RequiredPolicy, error) {
+
+	groups, err := b.getPolicyGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stack := fullyQualifiedName(ref)
+	seen := make(map[string]bool)
+	var policies []engine.RequiredPolicy
+	for _, group := range groups {
+		if !group.appliesTo(stack) {
+			continue
+		}
+		for _, p := range group.PolicyPacks {
+			if !seen[p.Name] {
+				seen[p.Name] = true
+				policies = append(policies, &localRequiredPolicy{RequiredPolicy: p, b: b})
+			}
+		}
+	}
+	return policies, nil
+}
+
+// renameStackInPolicyGroups updates the policy groups that name a stack after it is renamed or, if newRef is nil,
+// removes the stack from them. If only is not empty, only that group is updated.
+func (b *localBackend) renameStackInPolicyGroups(ctx context.Context, oldRef localBackendReference,
+	newRef *localBackendReference, only string) error {
+
+	groups, err := b.getPolicyGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	oldName := fullyQualifiedName(oldRef)
+	for _, group := range groups {
+		if group.Name == defaultPolicyGroup || only != "" && group.Name != only || !group.appliesTo(oldName) {
+			continue
+		}
+
+		var stacks []string
+		for _, s := range group.Stacks {
+			switch {
+			case s != oldName:
+				stacks = append(stacks, s)
+			case newRef != nil:
+				stacks = append(stacks, fullyQualifiedName(*newRef))
+			}
+		}
+		group.Stacks = stacks
+		if err = b.savePolicyGroup(ctx, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fullyQualifiedName returns the name of the stack that policy groups use to refer to it.
+func fullyQualifiedName(ref localBackendReference) string {
+	ref.currentProject = nil
+	return ref.String()
+}
+
+func (b *localBackend) policyPacksDirectory() string {
+	return filepath.Join(b.StateDir(), workspace.PolicyDir)
+}
+
+func (b *localBackend) policyPackPath(version localPolicyPackVersion, ext string) string {
+	return filepath.Join(b.policyPacksDirectory(), version.Name, version.key()+ext)
+}
+
+func (b *localBackend) policyGroupsDirectory() string {
+	return filepath.Join(b.StateDir(), workspace.PolicyGroupDir)
+}
+
+func (b *localBackend) policyGroupPath(name string) string {
+	return filepath.Join(b.policyGroupsDirectory(), name+".json")
+}
+
+// validatePolicyName checks that the name of a policy pack or policy group is a valid name, and therefore cannot refer
+// to a file outside the backend's policy directories.
+func validatePolicyName(kind, name string) error {
+	if !tokens.IsName(name) || name == "." || name == ".." {
+		return fmt.Errorf("invalid %s name '%s'; %s names may only contain alphanumerics, hyphens, underscores, "+
+			"or periods", kind, name, kind)
+	}
+	return nil
+}
+
+// getPolicyPackVersions returns the published versions of a policy pack, oldest first.
+func (b *localBackend) getPolicyPackVersions(ctx context.Context, name string) ([]localPolicyPackVersion, error) {
+	if err := validatePolicyName("policy pack", name); err != nil {
+		return nil, err
+	}
+
+	files, err := listBucket(b.bucket, filepath.Join(b.policyPacksDirectory(), name))
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []localPolicyPackVersion
+	for _, file := range files {
+		if file.IsDir || path.Ext(file.Key) != ".json" {
+			continue
+		}
+		byts, err := b.bucket.ReadAll(ctx, file.Key)
+		if err != nil {
+			return nil, err
+		}
+		var version localPolicyPackVersion
+		if err = json.Unmarshal(byts, &version); err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", file.Key, err)
+		}
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+// getPolicyPackVersion returns the given version of a policy pack, or its latest version if versionTag is nil.
+func (b *localBackend) getPolicyPackVersion(ctx context.Context, name string,
+	versionTag *string) (localPolicyPackVersion, error) {
+
+	versions, err := b.getPolicyPackVersions(ctx, name)
+	if err != nil {
+		return localPolicyPackVersion{}, err
+	}
+	if len(versions) == 0 {
+		return localPolicyPackVersion{}, fmt.Errorf("policy pack %s has not been published", name)
+	}
+	if versionTag == nil {
+		return versions[len(versions)-1], nil
+	}
+	for _, v := range versions {
+		if v.key() == *versionTag {
+			return v, nil
+		}
+	}
+	return localPolicyPackVersion{}, fmt.Errorf("policy pack %s has no version %s", name, *versionTag)
+}
+
+// getPolicyGroups returns the backend's policy groups, starting with the default policy group.
+func (b *localBackend) getPolicyGroups(ctx context.Context) ([]*localPolicyGroup, error) {
+	files, err := listBucket(b.bucket, b.policyGroupsDirectory())
+	if err != nil {
+		return nil, err
+	}
+
+	defaultGroup, err := b.getPolicyGroup(ctx, defaultPolicyGroup)
+	if err != nil {
+		return nil, err
+	}
+	groups := []*localPolicyGroup{defaultGroup}
+	for _, file := range files {
+		name := strings.TrimSuffix(objectName(file), ".json")
+		if file.IsDir || path.Ext(file.Key) != ".json" || name == defaultPolicyGroup {
+			continue
+		}
+		group, err := b.getPolicyGroup(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// getPolicyGroup returns the policy group with the given name, or an empty group if it does not exist yet. An empty
+// name refers to the default policy group.
+func (b *localBackend) getPolicyGroup(ctx context.Context, name string) (*localPolicyGroup, error) {
+	if name == "" {
+		name = defaultPolicyGroup
+	}
+	if err := validatePolicyName("policy group", name); err != nil {
+		return nil, err
+	}
+
+	file := b.policyGroupPath(name)
+	exists, err := b.bucket.Exists(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return &localPolicyGroup{Name: name}, nil
+	}
+
+	byts, err := b.bucket.ReadAll(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	var group localPolicyGroup
+	if err = json.Unmarshal(byts, &group); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", file, err)
+	}
+	group.Name = name
+	return &group, nil
+}
+
+func (b *localBackend) savePolicyGroup(ctx context.Context, group *localPolicyGroup) error {
+	byts, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		return err
+	}
+	return b.bucket.WriteAll(ctx, b.policyGroupPath(group.Name), byts, nil)
+}
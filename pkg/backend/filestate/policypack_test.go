@@ -0,0 +1,177 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+// writePolicyPackVersion stores a published version of a policy pack without building it.
+func writePolicyPackVersion(t *testing.T, b *localBackend, version localPolicyPackVersion) {
+	ctx := context.Background()
+	require.NoError(t, b.bucket.WriteAll(ctx, b.policyPackPath(version, ".tgz"), []byte{}, nil))
+	byts, err := json.Marshal(version)
+	require.NoError(t, err)
+	require.NoError(t, b.bucket.WriteAll(ctx, b.policyPackPath(version, ".json"), byts, nil))
+}
+
+func requiredPolicyNames(policies []engine.RequiredPolicy) []string {
+	var names []string
+	for _, p := range policies {
+		names = append(names, p.Name()+"@"+p.Version())
+	}
+	return names
+}
+
+func TestPolicyPacks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+
+	writePolicyPackVersion(t, lb, localPolicyPackVersion{Name: "security", Version: 1, VersionTag: "1.0.0"})
+	writePolicyPackVersion(t, lb, localPolicyPackVersion{Name: "security", Version: 2, VersionTag: "1.1.0"})
+	writePolicyPackVersion(t, lb, localPolicyPackVersion{Name: "cost", Version: 1, VersionTag: "0.1.0"})
+
+	packs, _, err := b.ListPolicyPacks(ctx, "organization", nil)
+	require.NoError(t, err)
+	if assert.Len(t, packs.PolicyPacks, 2) {
+		assert.Equal(t, "cost", packs.PolicyPacks[0].Name)
+		assert.Equal(t, []string{"1.0.0", "1.1.0"}, packs.PolicyPacks[1].VersionTags)
+	}
+
+	devRef, err := b.ParseStackReference("organization/project/dev")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, devRef, nil)
+	require.NoError(t, err)
+	prodRef, err := b.ParseStackReference("organization/project/prod")
+	require.NoError(t, err)
+	prod, err := b.CreateStack(ctx, prodRef, nil)
+	require.NoError(t, err)
+
+	// Enable security for every stack and cost for the stacks of the prod group only.
+	security, err := b.GetPolicyPack(ctx, "organization/security", cmdutil.Diag())
+	require.NoError(t, err)
+	cost, err := b.GetPolicyPack(ctx, "cost", cmdutil.Diag())
+	require.NoError(t, err)
+	oldVersion := "1.0.0"
+	require.NoError(t, security.Enable(ctx, "", backend.PolicyPackOperation{VersionTag: &oldVersion}))
+	require.NoError(t, security.Enable(ctx, "", backend.PolicyPackOperation{}))
+	require.NoError(t, cost.Enable(ctx, "prod", backend.PolicyPackOperation{}))
+	require.NoError(t, lb.AddStackToPolicyGroup(ctx, "prod", prodRef))
+	assert.Error(t, lb.AddStackToPolicyGroup(ctx, defaultPolicyGroup, devRef))
+
+	groups, _, err := b.ListPolicyGroups(ctx, "organization", nil)
+	require.NoError(t, err)
+	if assert.Len(t, groups.PolicyGroups, 2) {
+		assert.True(t, groups.PolicyGroups[0].IsOrgDefault)
+		assert.Equal(t, 2, groups.PolicyGroups[0].NumStacks)
+		assert.Equal(t, 1, groups.PolicyGroups[0].NumEnabledPolicyPacks)
+		assert.Equal(t, 1, groups.PolicyGroups[1].NumStacks)
+	}
+
+	policies, err := lb.getRequiredPolicies(ctx, localReference(devRef))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"security@2"}, requiredPolicyNames(policies))
+	policies, err = lb.getRequiredPolicies(ctx, localReference(prodRef))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"security@2", "cost@1"}, requiredPolicyNames(policies))
+
+	// Groups follow their stacks when they are renamed, and forget them when they are removed.
+	liveRef, err := b.RenameStack(ctx, prod, "live")
+	require.NoError(t, err)
+	policies, err = lb.getRequiredPolicies(ctx, localReference(liveRef))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"security@2", "cost@1"}, requiredPolicyNames(policies))
+
+	live, err := b.GetStack(ctx, liveRef)
+	require.NoError(t, err)
+	_, err = b.RemoveStack(ctx, live, false)
+	require.NoError(t, err)
+	group, err := lb.getPolicyGroup(ctx, "prod")
+	require.NoError(t, err)
+	assert.Empty(t, group.Stacks)
+
+	// Enabled policy packs cannot be removed.
+	assert.Error(t, cost.Remove(ctx, backend.PolicyPackOperation{}))
+	require.NoError(t, cost.Disable(ctx, "prod", backend.PolicyPackOperation{}))
+	require.NoError(t, cost.Remove(ctx, backend.PolicyPackOperation{}))
+	packs, _, err = b.ListPolicyPacks(ctx, "organization", nil)
+	require.NoError(t, err)
+	assert.Len(t, packs.PolicyPacks, 1)
+
+	// Versions without a version tag are referred to by their number.
+	writePolicyPackVersion(t, lb, localPolicyPackVersion{Name: "legacy", Version: 1})
+	legacy, err := b.GetPolicyPack(ctx, "legacy", cmdutil.Diag())
+	require.NoError(t, err)
+	legacyVersion := "1"
+	require.NoError(t, legacy.Enable(ctx, "", backend.PolicyPackOperation{VersionTag: &legacyVersion}))
+	otherVersion := "2"
+	assert.Error(t, legacy.Disable(ctx, "", backend.PolicyPackOperation{VersionTag: &otherVersion}))
+	require.NoError(t, legacy.Disable(ctx, "", backend.PolicyPackOperation{VersionTag: &legacyVersion}))
+	policies, err = lb.getRequiredPolicies(ctx, localReference(devRef))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"security@2"}, requiredPolicyNames(policies))
+}
+
+func TestPolicyNamesAreValidated(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(tmpDir))
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+	writePolicyPackVersion(t, lb, localPolicyPackVersion{Name: "security", Version: 1, VersionTag: "1.0.0"})
+	ref, err := b.ParseStackReference("organization/project/dev")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+
+	// Names that would refer to files outside the policy directories are rejected.
+	for _, name := range []string{"..", "../../.pulumi/stacks/x", `..\x`} {
+		_, err = b.GetPolicyPack(ctx, "organization/"+name, cmdutil.Diag())
+		assert.Error(t, err, name)
+
+		pack := &localPolicyPack{ref: localPolicyPackReference{name: tokens.QName(name)}, b: lb}
+		assert.Error(t, pack.Enable(ctx, "", backend.PolicyPackOperation{}), name)
+		assert.Error(t, pack.Remove(ctx, backend.PolicyPackOperation{}), name)
+
+		security, err := b.GetPolicyPack(ctx, "security", cmdutil.Diag())
+		require.NoError(t, err)
+		assert.Error(t, security.Enable(ctx, name, backend.PolicyPackOperation{}), name)
+		assert.Error(t, security.Disable(ctx, name, backend.PolicyPackOperation{}), name)
+		assert.Error(t, b.AddStackToPolicyGroup(ctx, name, ref), name)
+		assert.Error(t, b.RemoveStackFromPolicyGroup(ctx, name, ref), name)
+	}
+
+	groups, _, err := b.ListPolicyGroups(ctx, "organization", nil)
+	require.NoError(t, err)
+	assert.Len(t, groups.PolicyGroups, 1)
+}
@@ -0,0 +1,315 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+// HistoryRetentionCountEnvVar can be set to the number of updates of each stack to keep. After every update, older
+// history entries and checkpoint backups are removed.
+const HistoryRetentionCountEnvVar = "PULUMI_FILESTATE_HISTORY_RETENTION_COUNT"
+
+// HistoryRetentionDaysEnvVar can be set to the number of days to keep the updates of each stack for. After every
+// update, older history entries and checkpoint backups are removed.
+const HistoryRetentionDaysEnvVar = "PULUMI_FILESTATE_HISTORY_RETENTION_DAYS"
+
+// CompressHistoryEnvVar can be set to a truthy value to gzip the checkpoints saved with history entries and backups.
+const CompressHistoryEnvVar = "PULUMI_FILESTATE_COMPRESS_HISTORY"
+
+// gzipExt is the extension of compressed checkpoints, history checkpoints and backups.
+const gzipExt = ".gz"
+
+// HistoryRetention describes which updates of a stack to keep. The most recent update is always kept.
+type HistoryRetention struct {
+	// Count is the number of most recent updates to keep, or 0 to keep updates regardless of their number.
+	Count int
+	// MaxAge is how long to keep updates for, or 0 to keep updates regardless of their age.
+	MaxAge time.Duration
+}
+
+// IsEmpty returns true if the retention policy keeps every update.
+func (r HistoryRetention) IsEmpty() bool {
+	return r.Count == 0 && r.MaxAge == 0
+}
+
+// keeps returns true if an update should be kept. age is the number of updates that have been performed since.
+func (r HistoryRetention) keeps(age int, t time.Time, now time.Time) bool {
+	switch {
+	case age == 0:
+		return true
+	case r.Count > 0 && age >= r.Count:
+		return false
+	case r.MaxAge > 0 && now.Sub(t) > r.MaxAge:
+		return false
+	default:
+		return true
+	}
+}
+
+// HistoryRetentionFromEnv returns the retention policy configured by HistoryRetentionCountEnvVar and
+// HistoryRetentionDaysEnvVar.
+func HistoryRetentionFromEnv() (HistoryRetention, error) {
+	var retention HistoryRetention
+	if v := os.Getenv(HistoryRetentionCountEnvVar); v != "" {
+		count, err := strconv.Atoi(v)
+		if err != nil || count < 1 {
+			return HistoryRetention{}, fmt.Errorf("%s must be a positive number of updates, not %q",
+				HistoryRetentionCountEnvVar, v)
+		}
+		retention.Count = count
+	}
+	if v := os.Getenv(HistoryRetentionDaysEnvVar); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 {
+			return HistoryRetention{}, fmt.Errorf("%s must be a positive number of days, not %q",
+				HistoryRetentionDaysEnvVar, v)
+		}
+		retention.MaxAge = time.Duration(days) * 24 * time.Hour
+	}
+	return retention, nil
+}
+
+// historyEntry is an entry of a stack's update history.
+type historyEntry struct {
+	file   *blob.ListObject
+	update backend.UpdateInfo
+	// implicitVersion is true if the entry was written without a version number. The version of such an entry is
+	// its position in the history, counting from 1.
+	implicitVersion bool
+}
+
+// checkpointPrefix returns the path of the entry's checkpoint, without its extension.
+func (e historyEntry) checkpointPrefix() string {
+	return strings.TrimSuffix(e.file.Key, ".history.json") + ".checkpoint.json"
+}
+
+// readHistoryEntries returns the history entries of the given stack, oldest first.
+func (b *localBackend) readHistoryEntries(ctx context.Context, ref localBackendReference) ([]historyEntry, error) {
+	files, err := b.listHistoryEntries(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]historyEntry, len(files))
+	for i, file := range files {
+		byts, err := b.bucket.ReadAll(ctx, file.Key)
+		if err != nil {
+			return nil, fmt.Errorf("reading history file %s: %w", file.Key, err)
+		}
+		entry := historyEntry{file: file}
+		if err = json.Unmarshal(byts, &entry.update); err != nil {
+			return nil, fmt.Errorf("reading history file %s: %w", file.Key, err)
+		}
+		if entry.update.Version == 0 {
+			entry.update.Version, entry.implicitVersion = i+1, true
+		}
+		entries[i] = entry
+	}
+	return entries, nil
+}
+
+// PruneHistory removes the history entries and checkpoint backups of a stack that the given retention policy does
+// not keep, and returns the number of each that were removed.
+func (b *localBackend) PruneHistory(ctx context.Context, stackRef backend.StackReference,
+	retention HistoryRetention) (int, int, error) {
+
+	if err := b.Lock(ctx, stackRef); err != nil {
+		return 0, 0, err
+	}
+	defer b.Unlock(ctx, stackRef)
+
+	return b.pruneHistory(ctx, localReference(stackRef), retention, time.Now())
+}
+
+func (b *localBackend) pruneHistory(ctx context.Context, ref localBackendReference, retention HistoryRetention,
+	now time.Time) (int, int, error) {
+
+	if retention.IsEmpty() {
+		return 0, 0, nil
+	}
+
+	entries, err := b.readHistoryEntries(ctx, ref)
+	if err != nil {
+		return 0, 0, err
+	}
+	var pruned, kept []historyEntry
+	for i, entry := range entries {
+		if retention.keeps(len(entries)-1-i, historyFileTime(entry.file, ".history.json", "-"), now) {
+			kept = append(kept, entry)
+		} else {
+			pruned = append(pruned, entry)
+		}
+	}
+
+	if len(pruned) > 0 {
+		// The versions of entries written without one are implied by their position, which is about to change.
+		for _, entry := range kept {
+			if !entry.implicitVersion {
+				continue
+			}
+			byts, err := json.MarshalIndent(&entry.update, "", "    ")
+			if err != nil {
+				return 0, 0, err
+			}
+			if err = b.bucket.WriteAll(ctx, entry.file.Key, byts, nil); err != nil {
+				return 0, 0, err
+			}
+		}
+
+		for _, entry := range pruned {
+			prefix := entry.checkpointPrefix()
+			for _, file := range []string{prefix, prefix + gzipExt, entry.file.Key} {
+				if err = b.bucket.Delete(ctx, file); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+					return 0, 0, err
+				}
+			}
+		}
+	}
+
+	backups, err := listBucket(b.bucket, b.backupDirectory(ref))
+	if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return 0, 0, err
+	}
+	backups = filterFiles(backups)
+	prunedBackups := 0
+	for i, file := range backups {
+		ext := filepath.Ext(strings.TrimSuffix(file.Key, gzipExt))
+		if retention.keeps(len(backups)-1-i, historyFileTime(file, ext, "."), now) {
+			continue
+		}
+		if err = b.bucket.Delete(ctx, file.Key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return 0, 0, err
+		}
+		prunedBackups++
+	}
+
+	return len(pruned), prunedBackups, nil
+}
+
+// pruneHistoryFromEnv prunes the history of a stack according to the retention policy configured in the
+// environment, if any.
+func (b *localBackend) pruneHistoryFromEnv(ctx context.Context, ref localBackendReference) error {
+	retention, err := HistoryRetentionFromEnv()
+	if err != nil {
+		return err
+	}
+	_, _, err = b.pruneHistory(ctx, ref, retention, time.Now())
+	return err
+}
+
+// CompressHistory gzips the uncompressed history checkpoints and backups of a stack, and returns the number of files
+// that were compressed.
+func (b *localBackend) CompressHistory(ctx context.Context, stackRef backend.StackReference) (int, error) {
+	if err := b.Lock(ctx, stackRef); err != nil {
+		return 0, err
+	}
+	defer b.Unlock(ctx, stackRef)
+
+	ref := localReference(stackRef)
+	var files []*blob.ListObject
+	for _, dir := range []string{b.historyDirectory(ref), b.backupDirectory(ref)} {
+		dirFiles, err := listBucket(b.bucket, dir)
+		if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return 0, err
+		}
+		files = append(files, filterFiles(dirFiles)...)
+	}
+
+	compressed := 0
+	for _, file := range files {
+		if strings.HasSuffix(file.Key, gzipExt) || strings.HasSuffix(file.Key, ".history.json") {
+			continue
+		}
+		byts, err := b.bucket.ReadAll(ctx, file.Key)
+		if err != nil {
+			return compressed, err
+		}
+		if byts, err = gzipBytes(byts); err != nil {
+			return compressed, err
+		}
+		if err = b.bucket.WriteAll(ctx, file.Key+gzipExt, byts, nil); err != nil {
+			return compressed, err
+		}
+		if err = b.bucket.Delete(ctx, file.Key); err != nil {
+			return compressed, err
+		}
+		compressed++
+	}
+	return compressed, nil
+}
+
+// compressHistory returns true if new history checkpoints and backups should be compressed.
+func compressHistory() bool {
+	return cmdutil.IsTruthy(os.Getenv(CompressHistoryEnvVar))
+}
+
+// readHistoryCheckpoint reads a history checkpoint, which may have been compressed. Compressed checkpoints are
+// detected when they are unmarshaled.
+func (b *localBackend) readHistoryCheckpoint(ctx context.Context, prefix string) ([]byte, error) {
+	byts, err := b.bucket.ReadAll(ctx, prefix)
+	if gcerrors.Code(err) != gcerrors.NotFound {
+		return byts, err
+	}
+	return b.bucket.ReadAll(ctx, prefix+gzipExt)
+}
+
+func gzipBytes(byts []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(byts); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// historyFileTime returns the time at which a history file or backup was written. The names of these files end in
+// the time in nanoseconds, followed by the given extension.
+func historyFileTime(file *blob.ListObject, ext, sep string) time.Time {
+	name := strings.TrimSuffix(strings.TrimSuffix(objectName(file), gzipExt), ext)
+	if nanos, err := strconv.ParseInt(name[strings.LastIndex(name, sep)+1:], 10, 64); err == nil {
+		return time.Unix(0, nanos)
+	}
+	return file.ModTime
+}
+
+// filterFiles returns the files that are not directories.
+func filterFiles(files []*blob.ListObject) []*blob.ListObject {
+	var result []*blob.ListObject
+	for _, file := range files {
+		if !file.IsDir {
+			result = append(result, file)
+		}
+	}
+	return result
+}
@@ -0,0 +1,211 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+// recordUpdates saves a checkpoint with one more resource than the last, a history entry and a backup for each of
+// the given resource names.
+func recordUpdates(t *testing.T, lb *localBackend, ref backend.StackReference, names ...tokens.QName) {
+	sm := b64.NewBase64SecretsManager()
+	snap, _, err := lb.getStack(localReference(ref))
+	require.NoError(t, err)
+	var resources []*resource.State
+	if snap != nil {
+		resources = snap.Resources
+	}
+	for _, name := range names {
+		resources = append(resources, &resource.State{
+			URN:  resource.NewURN("a", "proj", "", "a:b:c", name),
+			Type: "a:b:c",
+		})
+		_, err = lb.saveStack(localReference(ref), deploy.NewSnapshot(deploy.Manifest{}, sm, resources, nil), sm)
+		require.NoError(t, err)
+		require.NoError(t, lb.addToHistory(localReference(ref), backend.UpdateInfo{Kind: apitype.UpdateUpdate}))
+		require.NoError(t, lb.backupStack(localReference(ref)))
+	}
+}
+
+func historyVersions(t *testing.T, b Backend, ref backend.StackReference) []int {
+	updates, err := b.GetHistory(context.Background(), ref, 0, 0)
+	require.NoError(t, err)
+	var versions []int
+	for _, update := range updates {
+		versions = append(versions, update.Version)
+	}
+	return versions
+}
+
+func TestPruneHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	s, err := b.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+
+	// Write an entry without a version number, as older versions of the CLI did.
+	recordUpdates(t, lb, ref, "first")
+	entries, err := lb.readHistoryEntries(ctx, localReference(ref))
+	require.NoError(t, err)
+	byts, err := json.Marshal(backend.UpdateInfo{Kind: apitype.UpdateUpdate})
+	require.NoError(t, err)
+	require.NoError(t, lb.bucket.WriteAll(ctx, entries[0].file.Key, byts, nil))
+
+	recordUpdates(t, lb, ref, "second", "third", "fourth")
+	assert.Equal(t, []int{4, 3, 2, 1}, historyVersions(t, b, ref))
+
+	// Keep the last two updates. Versions stay the same, and so do the checkpoints they refer to.
+	updates, backups, err := b.PruneHistory(ctx, ref, HistoryRetention{Count: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updates)
+	assert.Equal(t, 2, backups)
+	assert.Equal(t, []int{4, 3}, historyVersions(t, b, ref))
+	for _, version := range []int{3, 4} {
+		deployment, err := lb.ExportDeploymentForVersion(ctx, s, strconv.Itoa(version))
+		require.NoError(t, err)
+		snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+		require.NoError(t, err)
+		assert.Len(t, snap.Resources, version)
+	}
+	_, err = lb.ExportDeploymentForVersion(ctx, s, "2")
+	assert.Error(t, err)
+
+	// New updates continue the numbering.
+	recordUpdates(t, lb, ref, "fifth")
+	assert.Equal(t, []int{5, 4, 3}, historyVersions(t, b, ref))
+
+	// Compressed checkpoints can still be read.
+	compressed, err := b.CompressHistory(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, 6, compressed)
+	deployment, err := lb.ExportDeploymentForVersion(ctx, s, "5")
+	require.NoError(t, err)
+	snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	assert.Len(t, snap.Resources, 5)
+
+	// Updates older than the maximum age are pruned, except for the most recent one.
+	updates, backups, err = lb.pruneHistory(ctx, localReference(ref), HistoryRetention{MaxAge: time.Hour},
+		time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, updates)
+	assert.Equal(t, 2, backups)
+	assert.Equal(t, []int{5}, historyVersions(t, b, ref))
+}
+
+func TestReplaceHistoryCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, err := New(cmdutil.Diag(), "file://"+filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	s, err := b.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+
+	// Record two updates, compress their history, and back up the checkpoint next to it in the compressed format.
+	recordUpdates(t, lb, ref, "first", "second")
+	_, err = b.CompressHistory(ctx, ref)
+	require.NoError(t, err)
+	stackPath, err := lb.stackPath(localReference(ref))
+	require.NoError(t, err)
+	byts, err := lb.bucket.ReadAll(ctx, stackPath)
+	require.NoError(t, err)
+	byts, err = gzipBytes(byts)
+	require.NoError(t, err)
+	bak := lb.plainStackPath(localReference(ref)) + gzipExt + ".bak"
+	require.NoError(t, lb.bucket.WriteAll(ctx, bak, byts, nil))
+
+	deployments, err := b.HistoryCheckpoints(ctx, ref)
+	require.NoError(t, err)
+	assert.Len(t, deployments, 5)
+	require.Contains(t, deployments, bak)
+
+	// Replace every checkpoint with one without resources. Compressed files stay compressed.
+	sm := b64.NewBase64SecretsManager()
+	empty, err := stack.SerializeDeployment(deploy.NewSnapshot(deploy.Manifest{}, sm, nil, nil), sm, false)
+	require.NoError(t, err)
+	data, err := json.Marshal(empty)
+	require.NoError(t, err)
+	for file := range deployments {
+		deployments[file] = &apitype.UntypedDeployment{Version: 3, Deployment: data}
+	}
+	require.NoError(t, b.ReplaceHistoryCheckpoints(ctx, ref, deployments))
+
+	byts, err = lb.bucket.ReadAll(ctx, bak)
+	require.NoError(t, err)
+	assert.True(t, stack.IsCompressed(byts))
+	replaced, err := b.HistoryCheckpoints(ctx, ref)
+	require.NoError(t, err)
+	assert.Len(t, replaced, 5)
+	deployment, err := lb.ExportDeploymentForVersion(ctx, s, "2")
+	require.NoError(t, err)
+	snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	assert.Empty(t, snap.Resources)
+
+	// Only the stack's own history can be replaced.
+	err = b.ReplaceHistoryCheckpoints(ctx, ref, map[string]*apitype.UntypedDeployment{
+		stackPath: {Version: 3, Deployment: data},
+	})
+	assert.Error(t, err)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestHistoryRetentionFromEnv(t *testing.T) {
+	t.Setenv(HistoryRetentionCountEnvVar, "")
+	t.Setenv(HistoryRetentionDaysEnvVar, "")
+	retention, err := HistoryRetentionFromEnv()
+	require.NoError(t, err)
+	assert.True(t, retention.IsEmpty())
+
+	t.Setenv(HistoryRetentionCountEnvVar, "10")
+	t.Setenv(HistoryRetentionDaysEnvVar, "30")
+	retention, err = HistoryRetentionFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, HistoryRetention{Count: 10, MaxAge: 30 * 24 * time.Hour}, retention)
+
+	t.Setenv(HistoryRetentionDaysEnvVar, "forever")
+	_, err = HistoryRetentionFromEnv()
+	assert.Error(t, err)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/operations"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 )
 
@@ -36,17 +37,21 @@ type Stack interface {
 
 // localStack is a local stack descriptor.
 type localStack struct {
-	ref      backend.StackReference // the stack's reference (qualified name).
-	path     string                 // a path to the stack's checkpoint file on disk.
-	snapshot *deploy.Snapshot       // a snapshot representing the latest deployment state.
-	b        *localBackend          // a pointer to the backend this stack belongs to.
+	ref      backend.StackReference          // the stack's reference (qualified name).
+	path     string                          // a path to the stack's checkpoint file on disk.
+	snapshot *deploy.Snapshot                // a snapshot representing the latest deployment state.
+	tags     map[apitype.StackTagName]string // the stack's tags.
+	b        *localBackend                   // a pointer to the backend this stack belongs to.
 }
 
-func newStack(ref backend.StackReference, path string, snapshot *deploy.Snapshot, b *localBackend) Stack {
+func newStack(ref backend.StackReference, path string, snapshot *deploy.Snapshot,
+	tags map[apitype.StackTagName]string, b *localBackend) Stack {
+
 	return &localStack{
 		ref:      ref,
 		path:     path,
 		snapshot: snapshot,
+		tags:     tags,
 		b:        b,
 	}
 }
@@ -55,7 +60,7 @@ func (s *localStack) Ref() backend.StackReference                            { r
 func (s *localStack) Snapshot(ctx context.Context) (*deploy.Snapshot, error) { return s.snapshot, nil }
 func (s *localStack) Backend() backend.Backend                               { return s.b }
 func (s *localStack) Path() string                                           { return s.path }
-func (s *localStack) Tags() map[apitype.StackTagName]string                  { return nil }
+func (s *localStack) Tags() map[apitype.StackTagName]string                  { return s.tags }
 
 func (s *localStack) Remove(ctx context.Context, force bool) (bool, error) {
 	return backend.RemoveStack(ctx, s, force)
@@ -106,6 +111,10 @@ func (s *localStack) ImportDeployment(ctx context.Context, deployment *apitype.U
 	return backend.ImportStackDeployment(ctx, s, deployment)
 }
 
+func (s *localStack) GetResourceHistory(ctx context.Context, urn resource.URN) ([]backend.ResourceHistoryEntry, error) {
+	return backend.GetStackResourceHistory(ctx, s, urn)
+}
+
 type localStackSummary struct {
 	name backend.StackReference
 	chk  *apitype.CheckpointV3
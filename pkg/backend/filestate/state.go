@@ -15,10 +15,12 @@
 package filestate
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -37,18 +39,19 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/util/fsutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 const DisableCheckpointBackupsEnvVar = "PULUMI_DISABLE_CHECKPOINT_BACKUPS"
 
+// GzipCheckpointsEnvVar can be set to a truthy value to gzip the checkpoints of stacks when they are saved. Compressed
+// checkpoints are stored as .json.gz files, and are read regardless of this setting.
+const GzipCheckpointsEnvVar = "PULUMI_FILESTATE_GZIP_CHECKPOINTS"
+
 // DisableIntegrityChecking can be set to true to disable checkpoint state integrity verification.  This is not
 // recommended, because it could mean proceeding even in the face of a corrupted checkpoint state file, but can
 // be used as a last resort when a command absolutely must be run.
@@ -93,11 +96,11 @@ func (b *localBackend) newQuery(ctx context.Context,
 	return &localQuery{root: op.Root, proj: op.Proj}, nil
 }
 
-func (b *localBackend) newUpdate(stackName tokens.Name, op backend.UpdateOperation) (*update, error) {
-	contract.Require(stackName != "", "stackName")
+func (b *localBackend) newUpdate(ref localBackendReference, op backend.UpdateOperation) (*update, error) {
+	contract.Require(ref.name != "", "ref.name")
 
 	// Construct the deployment target.
-	target, err := b.getTarget(stackName, op.StackConfiguration.Config, op.StackConfiguration.Decrypter)
+	target, err := b.getTarget(ref, op.StackConfiguration.Config, op.StackConfiguration.Decrypter)
 	if err != nil {
 		return nil, err
 	}
@@ -111,35 +114,55 @@ func (b *localBackend) newUpdate(stackName tokens.Name, op backend.UpdateOperati
 	}, nil
 }
 
-func (b *localBackend) getTarget(stackName tokens.Name, cfg config.Map, dec config.Decrypter) (*deploy.Target, error) {
-	snapshot, _, err := b.getStack(stackName)
+func (b *localBackend) getTarget(ref localBackendReference, cfg config.Map,
+	dec config.Decrypter) (*deploy.Target, error) {
+
+	snapshot, _, err := b.getStack(ref)
 	if err != nil {
 		return nil, err
 	}
 	return &deploy.Target{
-		Name:      stackName,
+		Name:      ref.name,
 		Config:    cfg,
 		Decrypter: dec,
 		Snapshot:  snapshot,
 	}, nil
 }
 
-func (b *localBackend) getStack(name tokens.Name) (*deploy.Snapshot, string, error) {
-	if name == "" {
+func (b *localBackend) getStack(ref localBackendReference) (*deploy.Snapshot, string, error) {
+	if ref.name == "" {
 		return nil, "", errors.New("invalid empty stack name")
 	}
 
-	file := b.stackPath(name)
-
-	chk, err := b.getCheckpoint(name)
+	file, err := b.stackPath(ref)
 	if err != nil {
-		return nil, file, fmt.Errorf("failed to load checkpoint: %w", err)
+		return nil, "", err
 	}
 
-	// Materialize an actual snapshot object.
-	snapshot, err := stack.DeserializeCheckpoint(chk)
+	// If an update is in progress or was interrupted, the stack's current state is given by its journal. Otherwise
+	// the snapshot is materialized as its checkpoint is read.
+	entries, err := b.readJournal(ref)
 	if err != nil {
-		return nil, "", err
+		return nil, file, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	var snapshot *deploy.Snapshot
+	if len(entries) != 0 {
+		chk, err := b.replayCheckpoint(ref, file, entries)
+		if err != nil {
+			return nil, file, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		if snapshot, err = stack.DeserializeCheckpoint(chk); err != nil {
+			return nil, "", err
+		}
+	} else {
+		r, err := b.bucket.NewReader(context.TODO(), file, nil)
+		if err != nil {
+			return nil, file, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		defer contract.IgnoreClose(r)
+		if snapshot, err = stack.DecodeSnapshot(r, stack.DefaultSecretsProvider); err != nil {
+			return nil, file, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
 	}
 
 	// Ensure the snapshot passes verification before returning it, to catch bugs early.
@@ -153,43 +176,67 @@ func (b *localBackend) getStack(name tokens.Name) (*deploy.Snapshot, string, err
 }
 
 // GetCheckpoint loads a checkpoint file for the given stack in this project, from the current project workspace.
-func (b *localBackend) getCheckpoint(stackName tokens.Name) (*apitype.CheckpointV3, error) {
-	chkpath := b.stackPath(stackName)
-	bytes, err := b.bucket.ReadAll(context.TODO(), chkpath)
+func (b *localBackend) getCheckpoint(ref localBackendReference) (*apitype.CheckpointV3, error) {
+	chkpath, err := b.stackPath(ref)
 	if err != nil {
 		return nil, err
 	}
-
-	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(bytes)
+	entries, err := b.readJournal(ref)
+	if err != nil {
+		return nil, err
+	}
+	return b.replayCheckpoint(ref, chkpath, entries)
 }
 
-func (b *localBackend) saveStack(name tokens.Name, snap *deploy.Snapshot, sm secrets.Manager) (string, error) {
-	// Make a serializable stack and then use the encoder to encode it.
-	file := b.stackPath(name)
-	m, ext := encoding.Detect(file)
-	if m == nil {
-		return "", fmt.Errorf("resource serialization failed; illegal markup extension: '%v'", ext)
-	}
-	if filepath.Ext(file) == "" {
-		file = file + ext
-	}
-	chk, err := stack.SerializeCheckpoint(name, snap, sm, false /* showSecrets */)
+// replayCheckpoint reads the checkpoint at chkpath. If an update is in progress or was interrupted, the checkpoint is
+// out of date, and its latest deployment is replaced by the one that the stack's journal entries give.
+func (b *localBackend) replayCheckpoint(ref localBackendReference, chkpath string,
+	entries []journalEntry) (*apitype.CheckpointV3, error) {
+
+	r, err := b.bucket.NewReader(context.TODO(), chkpath, nil)
 	if err != nil {
-		return "", fmt.Errorf("serializaing checkpoint: %w", err)
+		return nil, err
 	}
-	byts, err := m.Marshal(chk)
+	defer contract.IgnoreClose(r)
+	chk, err := stack.DecodeCheckpoint(r)
 	if err != nil {
-		return "", fmt.Errorf("An IO error occurred while marshalling the checkpoint: %w", err)
+		return nil, err
+	}
+
+	if len(entries) != 0 {
+		if entries[0].Kind != journalEntryRebase {
+			return nil, fmt.Errorf("the journal for stack '%s' is corrupt", ref)
+		}
+		chk.Latest = replayJournal(entries)
+	}
+	return chk, nil
+}
+
+func (b *localBackend) saveStack(ref localBackendReference, snap *deploy.Snapshot,
+	sm secrets.Manager) (string, error) {
+
+	// Checkpoints are written in the format that GzipCheckpointsEnvVar asks for, replacing any checkpoint that is
+	// stored in the other format.
+	file, other := b.plainStackPath(ref), b.plainStackPath(ref)+gzipExt
+	if gzipCheckpoints() {
+		file, other = other, file
 	}
 
-	// Back up the existing file if it already exists. Don't delete the original, the following WriteAll will
+	// Back up the existing file if it already exists. Don't delete the original, the following write will
 	// atomically replace it anyway and various other bits of the system depend on being able to find the
 	// .json file to know the stack currently exists (see https://github.com/pulumi/pulumi/issues/9033 for
 	// context).
-	bck := backupTarget(b.bucket, file, true)
+	current, err := b.stackPath(ref)
+	if err != nil {
+		return "", err
+	}
+	bck := backupTarget(b.bucket, current, true)
 
 	// And now write out the new snapshot file, overwriting that location.
-	if err = b.bucket.WriteAll(context.TODO(), file, byts, nil); err != nil {
+	if ioErr, err := b.writeCheckpoint(file, ref, snap, sm); err != nil {
+		if !ioErr {
+			return "", fmt.Errorf("serializaing checkpoint: %w", err)
+		}
 
 		b.mutex.Lock()
 		defer b.mutex.Unlock()
@@ -206,9 +253,12 @@ func (b *localBackend) saveStack(name tokens.Name, snap *deploy.Snapshot, sm sec
 			Backoff:  &backoff,
 			Accept: func(try int, nextRetryTime time.Duration) (bool, interface{}, error) {
 				// And now write out the new snapshot file, overwriting that location.
-				err := b.bucket.WriteAll(context.TODO(), file, byts, nil)
+				ioErr, err := b.writeCheckpoint(file, ref, snap, sm)
 				if err != nil {
 					logging.V(7).Infof("Error while writing snapshot to: %s (attempt=%d, error=%s)", file, try, err)
+					if !ioErr {
+						return false, nil, fmt.Errorf("serializaing checkpoint: %w", err)
+					}
 					if try > 10 {
 						return false, nil, fmt.Errorf("An IO error occurred while writing the new snapshot file: %w", err)
 					}
@@ -221,12 +271,22 @@ func (b *localBackend) saveStack(name tokens.Name, snap *deploy.Snapshot, sm sec
 			return "", err
 		}
 	}
+	if err := b.bucket.Delete(context.TODO(), other); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return "", err
+	}
+	b.stackPaths.Store(b.plainStackPath(ref), file)
 
-	logging.V(7).Infof("Saved stack %s checkpoint to: %s (backup=%s)", name, file, bck)
+	logging.V(7).Infof("Saved stack %s checkpoint to: %s (backup=%s)", ref, file, bck)
+
+	// The checkpoint now reflects the stack's current state, so any journal of a previous update is obsolete.
+	if err := b.removeJournal(ref); err != nil {
+		return "", err
+	}
 
 	// And if we are retaining historical checkpoint information, write it out again
 	if cmdutil.IsTruthy(os.Getenv("PULUMI_RETAIN_CHECKPOINTS")) {
-		if err = b.bucket.WriteAll(context.TODO(), fmt.Sprintf("%v.%v", file, time.Now().UnixNano()), byts, nil); err != nil {
+		retained := fmt.Sprintf("%v.%v", file, time.Now().UnixNano())
+		if err := b.bucket.Copy(context.TODO(), retained, file, nil); err != nil {
 			return "", fmt.Errorf("An IO error occurred while writing the new snapshot file: %w", err)
 		}
 	}
@@ -246,15 +306,77 @@ func (b *localBackend) saveStack(name tokens.Name, snap *deploy.Snapshot, sm sec
 	return file, nil
 }
 
+// writeCheckpoint streams the checkpoint of a stack into the given file, compressing it if the file's name ends in
+// .json.gz. The file is only replaced if the whole checkpoint is written. If writing fails, ioErr tells whether that
+// was because of the bucket, in which case it is worth trying again, rather than because of the snapshot.
+func (b *localBackend) writeCheckpoint(file string, ref localBackendReference, snap *deploy.Snapshot,
+	sm secrets.Manager) (ioErr bool, err error) {
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	bw, err := b.bucket.NewWriter(ctx, file, nil)
+	if err != nil {
+		return true, err
+	}
+	w := &recordingWriter{w: bw}
+	var zw *gzip.Writer
+	var out io.Writer = w
+	if strings.HasSuffix(file, gzipExt) {
+		zw = gzip.NewWriter(w)
+		out = zw
+	}
+
+	err = stack.EncodeCheckpoint(out, ref.name, snap, sm, false /* showSecrets */)
+	if err == nil && zw != nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		// Canceling the write before closing the writer leaves the existing file in place.
+		cancel()
+		contract.IgnoreClose(bw)
+		return w.err != nil, err
+	}
+	return true, bw.Close()
+}
+
+// recordingWriter remembers whether writing to the underlying writer failed.
+type recordingWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
 // removeStack removes information about a stack from the current workspace.
-func (b *localBackend) removeStack(name tokens.Name) error {
-	contract.Require(name != "", "name")
+func (b *localBackend) removeStack(ref localBackendReference) error {
+	contract.Require(ref.name != "", "ref.name")
 
 	// Just make a backup of the file and don't write out anything new.
-	file := b.stackPath(name)
+	file, err := b.stackPath(ref)
+	if err != nil {
+		return err
+	}
 	backupTarget(b.bucket, file, false)
+	b.stackPaths.Delete(b.plainStackPath(ref))
 
-	historyDir := b.historyDirectory(name)
+	if err := b.removeJournal(ref); err != nil {
+		return err
+	}
+	if err := b.bucket.Delete(context.TODO(), b.tagsPath(ref)); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return err
+	}
+	if err := b.renameStackInPolicyGroups(context.TODO(), ref, nil, ""); err != nil {
+		return err
+	}
+
+	historyDir := b.historyDirectory(ref)
 	return removeAllByPrefix(b.bucket, historyDir)
 }
 
@@ -280,8 +402,8 @@ func backupTarget(bucket Bucket, file string, keepOriginal bool) string {
 }
 
 // backupStack copies the current Checkpoint file to ~/.pulumi/backups.
-func (b *localBackend) backupStack(name tokens.Name) error {
-	contract.Require(name != "", "name")
+func (b *localBackend) backupStack(ref localBackendReference) error {
+	contract.Require(ref.name != "", "ref.name")
 
 	// Exit early if backups are disabled.
 	if cmdutil.IsTruthy(os.Getenv(DisableCheckpointBackupsEnvVar)) {
@@ -289,48 +411,130 @@ func (b *localBackend) backupStack(name tokens.Name) error {
 	}
 
 	// Read the current checkpoint file. (Assuming it aleady exists.)
-	stackPath := b.stackPath(name)
+	stackPath, err := b.stackPath(ref)
+	if err != nil {
+		return err
+	}
 	byts, err := b.bucket.ReadAll(context.TODO(), stackPath)
 	if err != nil {
 		return err
 	}
 
 	// Get the backup directory.
-	backupDir := b.backupDirectory(name)
+	backupDir := b.backupDirectory(ref)
 
 	// Write out the new backup checkpoint file.
-	stackFile := filepath.Base(stackPath)
+	stackFile := filepath.Base(b.plainStackPath(ref))
 	ext := filepath.Ext(stackFile)
 	base := strings.TrimSuffix(stackFile, ext)
 	backupFile := fmt.Sprintf("%s.%v%s", base, time.Now().UnixNano(), ext)
+	switch {
+	case stack.IsCompressed(byts):
+		backupFile += gzipExt
+	case compressHistory():
+		if byts, err = gzipBytes(byts); err != nil {
+			return err
+		}
+		backupFile += gzipExt
+	}
 	return b.bucket.WriteAll(context.TODO(), filepath.Join(backupDir, backupFile), byts, nil)
 }
 
-func (b *localBackend) stackPath(stack tokens.Name) string {
-	path := filepath.Join(b.StateDir(), workspace.StackDir)
-	if stack != "" {
-		path = filepath.Join(path, fsutil.NamePath(stack)+".json")
+// stacksDirectory returns the directory that holds the checkpoints of the backend's stacks.
+func (b *localBackend) stacksDirectory() string {
+	return filepath.Join(b.StateDir(), workspace.StackDir)
+}
+
+// plainStackPath returns the path of the stack's checkpoint when it is not compressed. The paths of the stack's other
+// files are derived from it.
+func (b *localBackend) plainStackPath(ref localBackendReference) string {
+	contract.Require(ref.name != "", "ref.name")
+	return filepath.Join(b.stacksDirectory(), ref.pathName()+".json")
+}
+
+// stackPath returns the path of the stack's checkpoint. Checkpoints are compressed, and their path ends in .json.gz,
+// if GzipCheckpointsEnvVar is set; a checkpoint that is stored in the other format keeps it until it is next saved.
+// The path of an existing checkpoint is only looked up in the bucket once, and is then remembered.
+func (b *localBackend) stackPath(ref localBackendReference) (string, error) {
+	plain := b.plainStackPath(ref)
+	if file, ok := b.stackPaths.Load(plain); ok {
+		return file.(string), nil
 	}
 
-	return path
+	preferred, other := plain, plain+gzipExt
+	if gzipCheckpoints() {
+		preferred, other = other, preferred
+	}
+	for _, file := range []string{preferred, other} {
+		exists, err := b.bucket.Exists(context.TODO(), file)
+		if err != nil {
+			return "", fmt.Errorf("looking up the checkpoint of stack '%s': %w", ref, err)
+		}
+		if exists {
+			b.stackPaths.Store(plain, file)
+			return file, nil
+		}
+	}
+	return preferred, nil
 }
 
-func (b *localBackend) historyDirectory(stack tokens.Name) string {
-	contract.Require(stack != "", "stack")
-	return filepath.Join(b.StateDir(), workspace.HistoryDir, fsutil.NamePath(stack))
+// gzipCheckpoints returns true if checkpoints should be compressed when they are saved.
+func gzipCheckpoints() bool {
+	return cmdutil.IsTruthy(os.Getenv(GzipCheckpointsEnvVar))
 }
 
-func (b *localBackend) backupDirectory(stack tokens.Name) string {
-	contract.Require(stack != "", "stack")
-	return filepath.Join(b.StateDir(), workspace.BackupDir, fsutil.NamePath(stack))
+func (b *localBackend) historyDirectory(ref localBackendReference) string {
+	contract.Require(ref.name != "", "ref.name")
+	return filepath.Join(b.StateDir(), workspace.HistoryDir, ref.pathName())
 }
 
-// getHistory returns locally stored update history. The first element of the result will be
-// the most recent update record.
-func (b *localBackend) getHistory(name tokens.Name, pageSize int, page int) ([]backend.UpdateInfo, error) {
-	contract.Require(name != "", "name")
+func (b *localBackend) backupDirectory(ref localBackendReference) string {
+	contract.Require(ref.name != "", "ref.name")
+	return filepath.Join(b.StateDir(), workspace.BackupDir, ref.pathName())
+}
+
+// tagsPath returns the path of the file that holds the stack's tags, which sits next to its checkpoint.
+func (b *localBackend) tagsPath(ref localBackendReference) string {
+	return b.plainStackPath(ref) + ".tags"
+}
+
+// getStackTags returns the tags of the given stack. Stacks created by older versions of the CLI have no tags.
+func (b *localBackend) getStackTags(ctx context.Context,
+	ref localBackendReference) (map[apitype.StackTagName]string, error) {
+
+	file := b.tagsPath(ref)
+	exists, err := b.bucket.Exists(ctx, file)
+	if err != nil || !exists {
+		return nil, err
+	}
 
-	dir := b.historyDirectory(name)
+	byts, err := b.bucket.ReadAll(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	var tags map[apitype.StackTagName]string
+	if err = json.Unmarshal(byts, &tags); err != nil {
+		return nil, fmt.Errorf("could not parse the tags of stack '%s': %w", ref, err)
+	}
+	return tags, nil
+}
+
+// saveStackTags replaces the tags of the given stack.
+func (b *localBackend) saveStackTags(ctx context.Context, ref localBackendReference,
+	tags map[apitype.StackTagName]string) error {
+
+	byts, err := json.MarshalIndent(tags, "", "    ")
+	if err != nil {
+		return err
+	}
+	return b.bucket.WriteAll(ctx, b.tagsPath(ref), byts, nil)
+}
+
+// listHistoryEntries returns the history entries stored for the given stack, oldest first.
+func (b *localBackend) listHistoryEntries(ref localBackendReference) ([]*blob.ListObject, error) {
+	contract.Require(ref.name != "", "ref.name")
+
+	dir := b.historyDirectory(ref)
 	// TODO: we could consider optimizing the list operation using `page` and `pageSize`.
 	// Unfortunately, this is mildly invasive given the gocloud List API.
 	allFiles, err := listBucket(b.bucket, dir)
@@ -342,23 +546,35 @@ func (b *localBackend) getHistory(name tokens.Name, pageSize int, page int) ([]b
 		return nil, err
 	}
 
+	// filter down to just history entries. listBucket returns the array sorted by file name, but because of how we
+	// name files, older updates come before newer ones.
 	var historyEntries []*blob.ListObject
-
-	// filter down to just history entries, reversing list to be in most recent order.
-	// listBucket returns the array sorted by file name, but because of how we name files, older updates come before
-	// newer ones.
-	for i := len(allFiles) - 1; i >= 0; i-- {
-		file := allFiles[i]
-		filepath := file.Key
-
+	for _, file := range allFiles {
 		// ignore checkpoints
-		if !strings.HasSuffix(filepath, ".history.json") {
+		if !strings.HasSuffix(file.Key, ".history.json") {
 			continue
 		}
 
 		historyEntries = append(historyEntries, file)
 	}
 
+	return historyEntries, nil
+}
+
+// getHistory returns locally stored update history. The first element of the result will be
+// the most recent update record.
+func (b *localBackend) getHistory(ref localBackendReference, pageSize int, page int) ([]backend.UpdateInfo, error) {
+	allEntries, err := b.listHistoryEntries(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// reverse the list to be in most recent order.
+	historyEntries := make([]*blob.ListObject, len(allEntries))
+	for i, file := range allEntries {
+		historyEntries[len(allEntries)-1-i] = file
+	}
+
 	start := 0
 	end := len(historyEntries) - 1
 	if pageSize > 0 {
@@ -388,18 +604,45 @@ func (b *localBackend) getHistory(name tokens.Name, pageSize int, page int) ([]b
 			return nil, fmt.Errorf("reading history file %s: %w", filepath, err)
 		}
 
+		// Local updates are numbered in the order in which they were performed, starting from 1.
+		if update.Version == 0 {
+			update.Version = len(historyEntries) - i
+		}
+
 		updates = append(updates, update)
 	}
 
 	return updates, nil
 }
 
-func (b *localBackend) renameHistory(oldName tokens.Name, newName tokens.Name) error {
-	contract.Require(oldName != "", "oldName")
-	contract.Require(newName != "", "newName")
+// getHistoryCheckpoint returns the copy of the stack's checkpoint that was saved alongside the given version of its
+// update history. Versions are numbered from 1, in the order in which the updates were performed.
+func (b *localBackend) getHistoryCheckpoint(ref localBackendReference, version int) (*apitype.CheckpointV3, error) {
+	// Once a stack's history has been pruned, versions no longer correspond to positions in it.
+	entries, err := b.readHistoryEntries(context.TODO(), ref)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.update.Version != version {
+			continue
+		}
+		checkpointFile := entry.checkpointPrefix()
+		bytes, err := b.readHistoryCheckpoint(context.TODO(), checkpointFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading checkpoint file %s: %w", checkpointFile, err)
+		}
+		return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(bytes)
+	}
+	return nil, fmt.Errorf("stack '%s' has no version %d", ref, version)
+}
 
-	oldHistory := b.historyDirectory(oldName)
-	newHistory := b.historyDirectory(newName)
+func (b *localBackend) renameHistory(oldRef, newRef localBackendReference) error {
+	contract.Require(oldRef.name != "", "oldRef.name")
+	contract.Require(newRef.name != "", "newRef.name")
+
+	oldHistory := b.historyDirectory(oldRef)
+	newHistory := b.historyDirectory(newRef)
 
 	allFiles, err := listBucket(b.bucket, oldHistory)
 	if err != nil {
@@ -416,7 +659,7 @@ func (b *localBackend) renameHistory(oldName tokens.Name, newName tokens.Name) e
 
 		// The filename format is <stack-name>-<timestamp>.[checkpoint|history].json, we need to change
 		// the stack name part but retain the other parts.
-		newFileName := string(newName) + fileName[strings.LastIndex(fileName, "-"):]
+		newFileName := string(newRef.name) + fileName[strings.LastIndex(fileName, "-"):]
 		newBlob := path.Join(newHistory, newFileName)
 
 		if err := b.bucket.Copy(context.TODO(), newBlob, oldBlob, nil); err != nil {
@@ -431,26 +674,235 @@ func (b *localBackend) renameHistory(oldName tokens.Name, newName tokens.Name) e
 }
 
 // addToHistory saves the UpdateInfo and makes a copy of the current Checkpoint file.
-func (b *localBackend) addToHistory(name tokens.Name, update backend.UpdateInfo) error {
-	contract.Require(name != "", "name")
+func (b *localBackend) addToHistory(ref localBackendReference, update backend.UpdateInfo) error {
+	checkpointFile, err := b.writeHistoryFile(ref, time.Now().UnixNano(), update)
+	if err != nil {
+		return err
+	}
+
+	// Make a copy of the checkpoint file. (Assuming it already exists.)
+	stackPath, err := b.stackPath(ref)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(stackPath, gzipExt) {
+		return b.bucket.Copy(context.TODO(), checkpointFile+gzipExt, stackPath, nil)
+	}
+	if !compressHistory() {
+		return b.bucket.Copy(context.TODO(), checkpointFile, stackPath, nil)
+	}
+	byts, err := b.bucket.ReadAll(context.TODO(), stackPath)
+	if err != nil {
+		return err
+	}
+	return b.writeHistoryCheckpoint(checkpointFile, byts)
+}
+
+// ImportHistoryEntry appends an update, along with the deployment it resulted in, to the history of a stack. Entries
+// must be imported oldest first, and are given the next version number unless they have one.
+func (b *localBackend) ImportHistoryEntry(ctx context.Context, stackRef backend.StackReference,
+	update backend.UpdateInfo, deployment *apitype.UntypedDeployment) error {
+
+	if err := b.Lock(ctx, stackRef); err != nil {
+		return err
+	}
+	defer b.Unlock(ctx, stackRef)
+
+	ref := localReference(stackRef)
+	byts, err := marshalHistoryCheckpoint(ref, deployment)
+	if err != nil {
+		return err
+	}
+
+	// History files are named for the time they were written at, which keeps them in order. Imported entries are
+	// named for the time their update started instead, but must still come after the entries before them.
+	nanos := time.Unix(update.StartTime, 0).UnixNano()
+	entries, err := b.listHistoryEntries(ref)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		if last := historyFileTime(entries[len(entries)-1], ".history.json", "-").UnixNano(); nanos <= last {
+			nanos = last + 1
+		}
+	}
+
+	checkpointFile, err := b.writeHistoryFile(ref, nanos, update)
+	if err != nil {
+		return err
+	}
+	if !compressHistory() {
+		return b.bucket.WriteAll(ctx, checkpointFile, byts, nil)
+	}
+	return b.writeHistoryCheckpoint(checkpointFile, byts)
+}
+
+// HistoryCheckpoints returns the deployments saved in the history checkpoints and checkpoint backups of a stack, keyed
+// by the files that hold them.
+func (b *localBackend) HistoryCheckpoints(ctx context.Context,
+	stackRef backend.StackReference) (map[string]*apitype.UntypedDeployment, error) {
+
+	files, err := b.historyCheckpointFiles(ctx, localReference(stackRef))
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := make(map[string]*apitype.UntypedDeployment, len(files))
+	for _, file := range files {
+		byts, err := b.bucket.ReadAll(ctx, file)
+		if err != nil {
+			return nil, fmt.Errorf("reading checkpoint file %s: %w", file, err)
+		}
+		chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(byts)
+		if err != nil {
+			return nil, fmt.Errorf("reading checkpoint file %s: %w", file, err)
+		}
+		if chk.Latest == nil {
+			continue
+		}
+		data, err := json.Marshal(chk.Latest)
+		if err != nil {
+			return nil, err
+		}
+		deployments[file] = &apitype.UntypedDeployment{
+			Version:    apitype.DeploymentSchemaVersionCurrent,
+			Deployment: json.RawMessage(data),
+		}
+	}
+	return deployments, nil
+}
 
-	dir := b.historyDirectory(name)
+// ReplaceHistoryCheckpoints replaces the deployments saved in history checkpoints and checkpoint backups of a stack,
+// which are keyed by the files that hold them, as HistoryCheckpoints returns them. Files keep their compression.
+func (b *localBackend) ReplaceHistoryCheckpoints(ctx context.Context, stackRef backend.StackReference,
+	deployments map[string]*apitype.UntypedDeployment) error {
+
+	if err := b.Lock(ctx, stackRef); err != nil {
+		return err
+	}
+	defer b.Unlock(ctx, stackRef)
+
+	ref := localReference(stackRef)
+	files, err := b.historyCheckpointFiles(ctx, ref)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(files))
+	for _, file := range files {
+		known[file] = true
+	}
+
+	for file, deployment := range deployments {
+		if !known[file] {
+			return fmt.Errorf("%s is not a history checkpoint or backup of stack '%s'", file, ref)
+		}
+		byts, err := marshalHistoryCheckpoint(ref, deployment)
+		if err != nil {
+			return err
+		}
+		// The backup next to a compressed checkpoint is named for it, so its name ends in .json.gz.bak.
+		if strings.HasSuffix(strings.TrimSuffix(file, ".bak"), gzipExt) {
+			if byts, err = gzipBytes(byts); err != nil {
+				return err
+			}
+		}
+		if err = b.bucket.WriteAll(ctx, file, byts, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// historyCheckpointFiles returns the history checkpoints and checkpoint backups of a stack, including the backup that
+// sits next to its checkpoint.
+func (b *localBackend) historyCheckpointFiles(ctx context.Context, ref localBackendReference) ([]string, error) {
+	var files []string
+	for _, dir := range []string{b.historyDirectory(ref), b.backupDirectory(ref)} {
+		dirFiles, err := listBucket(b.bucket, dir)
+		if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return nil, err
+		}
+		for _, file := range filterFiles(dirFiles) {
+			if !strings.HasSuffix(file.Key, ".history.json") {
+				files = append(files, file.Key)
+			}
+		}
+	}
+
+	for _, file := range []string{b.plainStackPath(ref) + ".bak", b.plainStackPath(ref) + gzipExt + ".bak"} {
+		exists, err := b.bucket.Exists(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// marshalHistoryCheckpoint returns the checkpoint of a stack that holds the given deployment, as it is saved alongside
+// history entries.
+func marshalHistoryCheckpoint(ref localBackendReference, deployment *apitype.UntypedDeployment) ([]byte, error) {
+	if deployment.Version != apitype.DeploymentSchemaVersionCurrent {
+		return nil, fmt.Errorf("unsupported deployment version %d", deployment.Version)
+	}
+	var latest apitype.DeploymentV3
+	if err := json.Unmarshal(deployment.Deployment, &latest); err != nil {
+		return nil, err
+	}
+	chk, err := json.Marshal(apitype.CheckpointV3{Stack: ref.name.Q(), Latest: &latest})
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(apitype.VersionedCheckpoint{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Checkpoint: json.RawMessage(chk),
+	}, "", "    ")
+}
+
+// writeHistoryFile saves an UpdateInfo to the history of a stack, as though it was written at the given time, and
+// returns the path of the checkpoint that belongs with it.
+func (b *localBackend) writeHistoryFile(ref localBackendReference, nanos int64,
+	update backend.UpdateInfo) (string, error) {
+
+	contract.Require(ref.name != "", "ref.name")
+
+	dir := b.historyDirectory(ref)
+
+	// Number the update explicitly, so that it keeps its version when older updates are pruned.
+	if update.Version == 0 {
+		latest, err := b.getHistory(ref, 1, 1)
+		if err != nil {
+			return "", err
+		}
+		update.Version = 1
+		if len(latest) > 0 {
+			update.Version = latest[0].Version + 1
+		}
+	}
 
 	// Prefix for the update and checkpoint files.
-	pathPrefix := path.Join(dir, fmt.Sprintf("%s-%d", name, time.Now().UnixNano()))
+	pathPrefix := path.Join(dir, fmt.Sprintf("%s-%d", ref.name, nanos))
 
 	// Save the history file.
 	byts, err := json.MarshalIndent(&update, "", "    ")
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	historyFile := fmt.Sprintf("%s.history.json", pathPrefix)
 	if err = b.bucket.WriteAll(context.TODO(), historyFile, byts, nil); err != nil {
-		return err
+		return "", err
 	}
+	return fmt.Sprintf("%s.checkpoint.json", pathPrefix), nil
+}
 
-	// Make a copy of the checkpoint file. (Assuming it already exists.)
-	checkpointFile := fmt.Sprintf("%s.checkpoint.json", pathPrefix)
-	return b.bucket.Copy(context.TODO(), checkpointFile, b.stackPath(name), nil)
+// writeHistoryCheckpoint compresses a checkpoint and saves it alongside a history entry.
+func (b *localBackend) writeHistoryCheckpoint(checkpointFile string, byts []byte) error {
+	byts, err := gzipBytes(byts)
+	if err != nil {
+		return err
+	}
+	return b.bucket.WriteAll(context.TODO(), checkpointFile+gzipExt, byts, nil)
 }
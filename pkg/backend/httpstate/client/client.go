@@ -31,6 +31,7 @@ import (
 	"github.com/blang/semver"
 
 	"github.com/pulumi/pulumi/pkg/v3/engine"
+	resourceanalyzer "github.com/pulumi/pulumi/pkg/v3/resource/analyzer"
 	"github.com/pulumi/pulumi/pkg/v3/util/validation"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
@@ -608,7 +609,7 @@ func (pc *Client) PublishPolicyPack(ctx context.Context, orgName string,
 
 	policies := make([]apitype.Policy, len(analyzerInfo.Policies))
 	for i, policy := range analyzerInfo.Policies {
-		configSchema, err := convertPolicyConfigSchema(policy.ConfigSchema)
+		configSchema, err := resourceanalyzer.ConvertPolicyConfigSchema(policy.ConfigSchema)
 		if err != nil {
 			return "", err
 		}
@@ -683,27 +684,6 @@ func (pc *Client) PublishPolicyPack(ctx context.Context, orgName string,
 	return version, nil
 }
 
-// convertPolicyConfigSchema converts a policy's schema from the analyzer to the apitype.
-func convertPolicyConfigSchema(schema *plugin.AnalyzerPolicyConfigSchema) (*apitype.PolicyConfigSchema, error) {
-	if schema == nil {
-		return nil, nil
-	}
-	properties := map[string]*json.RawMessage{}
-	for k, v := range schema.Properties {
-		bytes, err := json.Marshal(v)
-		if err != nil {
-			return nil, err
-		}
-		raw := json.RawMessage(bytes)
-		properties[k] = &raw
-	}
-	return &apitype.PolicyConfigSchema{
-		Type:       apitype.Object,
-		Properties: properties,
-		Required:   schema.Required,
-	}, nil
-}
-
 // validatePolicyPackVersion validates the version of a Policy Pack. The version may be empty,
 // as it is likely an older version of pulumi/policy that does not gather the version.
 func validatePolicyPackVersion(s string) error {
@@ -25,6 +25,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/operations"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
@@ -187,6 +188,10 @@ func (s *cloudStack) ImportDeployment(ctx context.Context, deployment *apitype.U
 	return backend.ImportStackDeployment(ctx, s, deployment)
 }
 
+func (s *cloudStack) GetResourceHistory(ctx context.Context, urn resource.URN) ([]backend.ResourceHistoryEntry, error) {
+	return backend.GetStackResourceHistory(ctx, s, urn)
+}
+
 func (s *cloudStack) ConsoleURL() (string, error) {
 	return s.b.StackConsoleURL(s.ref)
 }
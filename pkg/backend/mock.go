@@ -22,6 +22,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
@@ -355,8 +356,9 @@ type MockStack struct {
 	RenameF  func(ctx context.Context, newName tokens.QName) (StackReference, error)
 	GetLogsF func(ctx context.Context, cfg StackConfiguration,
 		query operations.LogQuery) ([]operations.LogEntry, error)
-	ExportDeploymentF func(ctx context.Context) (*apitype.UntypedDeployment, error)
-	ImportDeploymentF func(ctx context.Context, deployment *apitype.UntypedDeployment) error
+	ExportDeploymentF   func(ctx context.Context) (*apitype.UntypedDeployment, error)
+	ImportDeploymentF   func(ctx context.Context, deployment *apitype.UntypedDeployment) error
+	GetResourceHistoryF func(ctx context.Context, urn resource.URN) ([]ResourceHistoryEntry, error)
 }
 
 var _ Stack = (*MockStack)(nil)
@@ -484,3 +486,10 @@ func (ms *MockStack) ImportDeployment(ctx context.Context, deployment *apitype.U
 	}
 	panic("not implemented")
 }
+
+func (ms *MockStack) GetResourceHistory(ctx context.Context, urn resource.URN) ([]ResourceHistoryEntry, error) {
+	if ms.GetResourceHistoryF != nil {
+		return ms.GetResourceHistoryF(ctx, urn)
+	}
+	panic("not implemented")
+}
@@ -0,0 +1,90 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ResourceHistoryEntry records how a single update to a stack changed one of its resources.
+type ResourceHistoryEntry struct {
+	// Update is the update that changed the resource.
+	Update UpdateInfo
+	// Diff describes the change: whether the resource was created, updated, replaced or deleted, its states before
+	// and after the update, and the properties that changed.
+	Diff deploy.ResourceDiff
+}
+
+// GetStackResourceHistory walks the checkpoints saved by each of the stack's past updates and returns the changes
+// made to the resource with the given URN, oldest first. The stack's backend must be able to export previous
+// deployments.
+func GetStackResourceHistory(ctx context.Context, s Stack, urn resource.URN) ([]ResourceHistoryEntry, error) {
+	be := s.Backend()
+	exporter, ok := be.(SpecificDeploymentExporter)
+	if !ok {
+		return nil, fmt.Errorf("the %s backend does not provide the ability to export previous deployments", be.Name())
+	}
+
+	updates, err := be.GetHistory(ctx, s.Ref(), 0 /*pageSize*/, 0 /*page*/)
+	if err != nil {
+		return nil, fmt.Errorf("getting history: %w", err)
+	}
+
+	var entries []ResourceHistoryEntry
+	var previous *deploy.Snapshot
+	// Updates are returned newest first.
+	for i := len(updates) - 1; i >= 0; i-- {
+		update := updates[i]
+		if update.Result == InProgressResult {
+			continue
+		}
+
+		deployment, err := exporter.ExportDeploymentForVersion(ctx, s, strconv.Itoa(update.Version))
+		if err != nil {
+			return nil, fmt.Errorf("exporting version %d: %w", update.Version, err)
+		}
+		snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+		if err != nil {
+			return nil, fmt.Errorf("reading version %d: %w", update.Version, err)
+		}
+		current := filterSnapshot(snap, urn)
+
+		for _, diff := range deploy.DiffSnapshots(previous, current) {
+			entries = append(entries, ResourceHistoryEntry{Update: update, Diff: diff})
+		}
+		previous = current
+	}
+	return entries, nil
+}
+
+// filterSnapshot returns a snapshot that contains only the states of the resource with the given URN.
+func filterSnapshot(snap *deploy.Snapshot, urn resource.URN) *deploy.Snapshot {
+	filtered := &deploy.Snapshot{}
+	if snap == nil {
+		return filtered
+	}
+	for _, res := range snap.Resources {
+		if res.URN == urn {
+			filtered.Resources = append(filtered.Resources, res)
+		}
+	}
+	return filtered
+}
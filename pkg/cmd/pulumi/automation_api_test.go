@@ -0,0 +1,108 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optimport"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optrefresh"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
+)
+
+// TestAutomationAPIOptionParity checks that every flag of the commands that the Automation API runs has a
+// counterpart in the options of the Automation API's operation. A flag's counterpart is the field of the operation's
+// Options struct that is named after it, e.g. TargetDependents for --target-dependents. When adding a flag to one of
+// these commands, add the option to the Automation API too, or list the flag below if the option does not apply.
+func TestAutomationAPIOptionParity(t *testing.T) {
+	t.Parallel()
+
+	// Flags that the Automation API sets itself, or whose behavior it provides in other ways.
+	notApplicable := map[string]string{
+		"client":           "set for inline programs",
+		"config":           "configuration is managed with the Workspace's config methods",
+		"config-file":      "configuration is managed with the Workspace's config methods",
+		"config-path":      "configuration is managed with the Workspace's config methods",
+		"event-log":        "set for EventStreams",
+		"exec-kind":        "set to identify the Automation API",
+		"json":             "the Automation API reads the engine's events instead",
+		"out":              "generated code is returned in the ImportResult",
+		"parent":           "resources are always imported from a spec, which names their parents",
+		"properties":       "resources are always imported from a spec, which lists their properties",
+		"provider":         "resources are always imported from a spec, which names their providers",
+		"secrets-provider": "stacks are created with the Workspace's CreateStack",
+		"skip-preview":     "operations are never previewed first",
+		"stack":            "set to the Stack's name",
+		"yes":              "operations are never confirmed interactively",
+	}
+
+	// Options that are not named after their flags.
+	renamed := map[string]string{
+		"debug":              "DebugLogOpts",
+		"exec-agent":         "UserAgent",
+		"file":               "Resources",
+		"policy-pack":        "PolicyPacks",
+		"policy-pack-config": "PolicyPackConfigs",
+		"save-plan":          "Plan",
+	}
+
+	tests := []struct {
+		cmd     *cobra.Command
+		options interface{}
+	}{
+		{newUpCmd(), optup.Options{}},
+		{newPreviewCmd(), optpreview.Options{}},
+		{newDestroyCmd(), optdestroy.Options{}},
+		{newRefreshCmd(), optrefresh.Options{}},
+		{newImportCmd(), optimport.Options{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.cmd.Name(), func(t *testing.T) {
+			t.Parallel()
+
+			options := reflect.TypeOf(tt.options)
+			tt.cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+				if _, ok := notApplicable[flag.Name]; ok {
+					return
+				}
+				field, ok := renamed[flag.Name]
+				if !ok {
+					field = flagFieldName(flag.Name)
+				}
+				_, ok = options.FieldByName(field)
+				assert.True(t, ok, "--%s has no Automation API counterpart; add %s.%s", flag.Name, options, field)
+			})
+		})
+	}
+}
+
+// flagFieldName returns the name of the Options field that is named after a flag, e.g. TargetDependents for
+// target-dependents.
+func flagFieldName(flag string) string {
+	var name strings.Builder
+	for _, word := range strings.Split(flag, "-") {
+		name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return name.String()
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 )
@@ -29,6 +30,7 @@ import (
 func newCancelCmd() *cobra.Command {
 	var yes bool
 	var stack string
+	var breakStaleLock bool
 	var cmd = &cobra.Command{
 		Use:   "cancel [<stack-name>]",
 		Args:  cmdutil.MaximumNArgs(1),
@@ -40,7 +42,11 @@ func newCancelCmd() *cobra.Command {
 			"inconsistent state if a resource operation was pending when the update was canceled.\n" +
 			"\n" +
 			"After this command completes successfully, the stack will be ready for further\n" +
-			"updates.",
+			"updates.\n" +
+			"\n" +
+			"For self-managed backends, --break-stale-lock removes only the locks whose holders\n" +
+			"have stopped renewing them (for example because the process that took the lock\n" +
+			"crashed), leaving any update that is still running untouched.",
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
 			// Use the stack provided or, if missing, default to the current one.
 			if len(args) > 0 {
@@ -60,8 +66,22 @@ func newCancelCmd() *cobra.Command {
 				return result.FromError(err)
 			}
 
-			// Ensure the user really wants to do this.
 			stackName := string(s.Ref().Name())
+			if breakStaleLock {
+				lb, ok := s.Backend().(filestate.Backend)
+				if !ok {
+					return result.Errorf("the current backend (%s) does not support --break-stale-lock",
+						s.Backend().Name())
+				}
+				broken, err := lb.BreakStaleLocks(commandContext(), s.Ref())
+				if err != nil {
+					return result.FromError(err)
+				}
+				fmt.Printf("Removed %d stale lock(s) from '%s'\n", broken, stackName)
+				return nil
+			}
+
+			// Ensure the user really wants to do this.
 			prompt := fmt.Sprintf("This will irreversibly cancel the currently running update for '%s'!", stackName)
 			if cmdutil.Interactive() && (!yes && !confirmPrompt(prompt, stackName, opts)) {
 				fmt.Println("confirmation declined")
@@ -85,6 +105,9 @@ func newCancelCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVarP(
 		&yes, "yes", "y", false,
 		"Skip confirmation prompts, and proceed with cancellation anyway")
+	cmd.PersistentFlags().BoolVar(
+		&breakStaleLock, "break-stale-lock", false,
+		"Only remove locks whose leases have expired, leaving updates that are still running alone")
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
@@ -32,6 +32,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -540,6 +541,9 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err = validateSetConfigValue(ps.Config, key, path); err != nil {
+				return err
+			}
 
 			return saveProjectStack(s, ps)
 		}),
@@ -605,6 +609,9 @@ func newConfigSetAllCmd(stack *string) *cobra.Command {
 				if err != nil {
 					return err
 				}
+				if err = validateSetConfigValue(ps.Config, key, path); err != nil {
+					return err
+				}
 			}
 
 			for _, sArg := range secretArgs {
@@ -626,6 +633,9 @@ func newConfigSetAllCmd(stack *string) *cobra.Command {
 				if err != nil {
 					return err
 				}
+				if err = validateSetConfigValue(ps.Config, key, path); err != nil {
+					return err
+				}
 			}
 
 			return saveProjectStack(s, ps)
@@ -691,6 +701,38 @@ func saveProjectStack(stack backend.Stack, ps *workspace.ProjectStack) error {
 	return ps.Save(stackConfigFile)
 }
 
+// backupProjectStack reads the stack's configuration file and returns a function that restores it to its current
+// contents, so that commands that rewrite the file in several steps can undo their changes when a later step fails.
+func backupProjectStack(stack backend.Stack) (func() error, error) {
+	path := stackConfigFile
+	if path == "" {
+		p, err := workspace.DetectProjectStackPath(stack.Ref().Name().Q())
+		if err != nil {
+			return nil, err
+		}
+		path = p
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return func() error {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		return ioutil.WriteFile(path, contents, info.Mode().Perm())
+	}, nil
+}
+
 func parseConfigKey(key string) (config.Key, error) {
 	// As a convenience, we'll treat any key with no delimiter as if:
 	// <program-name>:<key> had been written instead
@@ -723,6 +765,41 @@ func prettyKeyForProject(k config.Key, proj *workspace.Project) string {
 	return fmt.Sprintf("%s:%s", k.Namespace(), k.Name())
 }
 
+// validateSetConfigValue returns an error if the value of a configuration key that was just set does not conform to
+// the project's declaration of the key. If the key is a path, the value of its top-level key is validated.
+func validateSetConfigValue(cfg config.Map, key config.Key, path bool) error {
+	proj, err := workspace.DetectProject()
+	if err != nil {
+		return err
+	}
+
+	if path {
+		p, err := resource.ParsePropertyPath(key.Name())
+		if err != nil {
+			return err
+		}
+		if name, ok := p[0].(string); ok {
+			key = config.MustMakeKey(key.Namespace(), name)
+		}
+	}
+	return proj.ValidateConfigValue(key, cfg[key])
+}
+
+// applyProjectConfigSchema validates a stack's configuration against the configuration keys that the project
+// declares, and adds the default values of the declared keys that the stack does not set.
+func applyProjectConfigSchema(proj *workspace.Project, cfg *backend.StackConfiguration) error {
+	if err := proj.ValidateConfig(cfg.Config); err != nil {
+		return err
+	}
+
+	withDefaults, err := proj.ApplyConfigDefaults(cfg.Config)
+	if err != nil {
+		return err
+	}
+	cfg.Config = withDefaults
+	return nil
+}
+
 // configValueJSON is the shape of the --json output for a configuration value.  While we can add fields to this
 // structure in the future, we should not change existing fields.
 type configValueJSON struct {
@@ -809,6 +886,10 @@ func listConfig(stack backend.Stack, showSecrets bool, jsonOut bool) error {
 			Headers: []string{"KEY", "VALUE"},
 			Rows:    rows,
 		})
+
+		if proj, err := workspace.DetectProject(); err == nil {
+			printUnsetConfigKeys(proj, cfg)
+		}
 	}
 
 	if showSecrets {
@@ -818,6 +899,45 @@ func listConfig(stack backend.Stack, showSecrets bool, jsonOut bool) error {
 	return nil
 }
 
+// printUnsetConfigKeys prints the configuration keys that the project declares but the stack does not set.
+func printUnsetConfigKeys(proj *workspace.Project, cfg config.Map) {
+	unset := proj.UnsetConfigKeys(cfg)
+	if len(unset) == 0 {
+		return
+	}
+	types, err := proj.ConfigTypes()
+	if err != nil {
+		return
+	}
+
+	rows := []cmdutil.TableRow{}
+	for _, key := range unset {
+		t := types[key]
+		typ := t.Type
+		if typ == "" {
+			typ = workspace.ConfigTypeString
+		}
+		required := ""
+		if t.Required && t.Default == nil {
+			required = "yes"
+		}
+		def := ""
+		if t.Default != nil {
+			def = fmt.Sprintf("%v", t.Default)
+		}
+		rows = append(rows, cmdutil.TableRow{
+			Columns: []string{prettyKeyForProject(key, proj), typ, required, def, t.Description},
+		})
+	}
+
+	fmt.Println()
+	fmt.Println("The project declares these configuration keys, which are not set:")
+	cmdutil.PrintTable(cmdutil.Table{
+		Headers: []string{"KEY", "TYPE", "REQUIRED", "DEFAULT", "DESCRIPTION"},
+		Rows:    rows,
+	})
+}
+
 func getConfig(stack backend.Stack, key config.Key, path, jsonOut bool) error {
 	ps, err := loadProjectStack(stack)
 	if err != nil {
@@ -15,10 +15,14 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
@@ -46,3 +50,49 @@ func TestSecretDetection(t *testing.T) {
 	// The key name does not match the pattern, so even though this "looks like" a secret, we say it is not.
 	assert.False(t, looksLikeSecret(config.MustMakeKey("test", "okay"), "1415fc1f4eaeb5e096ee58c1480016638fff29bf"))
 }
+
+//nolint:paralleltest // changes the working directory
+func TestValidateSetConfigValue(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pulumi.yaml"), []byte(`name: proj
+runtime: go
+config:
+  count:
+    type: integer
+    default: 2
+  zones:
+    type: array
+  password:
+    secret: true
+    required: true
+`), 0600))
+	chdir(t, dir)
+
+	cfg := config.Map{}
+	key := config.MustMakeKey("proj", "zones[0]")
+	require.NoError(t, cfg.Set(key, config.NewValue("us-west-2a"), true /*path*/))
+	assert.NoError(t, validateSetConfigValue(cfg, key, true /*path*/))
+
+	key = config.MustMakeKey("proj", "count")
+	require.NoError(t, cfg.Set(key, config.NewValue("two"), false /*path*/))
+	assert.Error(t, validateSetConfigValue(cfg, key, false /*path*/))
+
+	key = config.MustMakeKey("proj", "cuont")
+	require.NoError(t, cfg.Set(key, config.NewValue("3"), false /*path*/))
+	err := validateSetConfigValue(cfg, key, false /*path*/)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not declared")
+
+	proj, err := workspace.DetectProject()
+	require.NoError(t, err)
+	stackCfg := backend.StackConfiguration{Config: config.Map{
+		config.MustMakeKey("proj", "password"): config.NewSecureValue("ciphertext"),
+	}}
+	require.NoError(t, applyProjectConfigSchema(proj, &stackCfg))
+	assert.Equal(t, config.NewValue("2"), stackCfg.Config[config.MustMakeKey("proj", "count")])
+
+	stackCfg = backend.StackConfiguration{Config: config.Map{}}
+	err = applyProjectConfigSchema(proj, &stackCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required configuration key 'proj:password' is not set")
+}
@@ -0,0 +1,85 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/external"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newExternalSecretsManager(stackName tokens.Name, configFile, secretsProvider string) (secrets.Manager, error) {
+	contract.Assertf(stackName != "", "stackName %s", "!= \"\"")
+
+	if configFile == "" {
+		f, err := workspace.DetectProjectStackPath(stackName.Q())
+		if err != nil {
+			return nil, err
+		}
+		configFile = f
+	}
+
+	info, err := workspace.LoadProjectStack(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	secretsManager, err := external.NewExternalSecretsManager(secretsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	// The external program holds all of the state it needs, so the salt of a passphrase provider and the key of a
+	// cloud secrets provider are removed.
+	if info.SecretsProvider != secretsProvider || info.EncryptionSalt != "" || info.EncryptedKey != "" {
+		info.SecretsProvider = secretsProvider
+		info.EncryptionSalt = ""
+		info.EncryptedKey = ""
+		if err = info.Save(configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return secretsManager, nil
+}
+
+// trustConfiguredSecretsProviders trusts the external secrets provider programs that the current project's stack
+// configuration files select, so that the checkpoints they encrypted can be read. Checkpoints that name any other
+// program are refused, as a checkpoint from an untrusted source could otherwise run arbitrary programs.
+func trustConfiguredSecretsProviders() {
+	var configFiles []string
+	if stackConfigFile != "" {
+		configFiles = append(configFiles, stackConfigFile)
+	}
+	// The name of any stack gives the directory and extension of the project's stack configuration files.
+	if path, err := workspace.DetectProjectStackPath("stack"); err == nil {
+		pattern := filepath.Join(filepath.Dir(path), workspace.ProjectFile+".*"+filepath.Ext(path))
+		matches, err := filepath.Glob(pattern)
+		if err == nil {
+			configFiles = append(configFiles, matches...)
+		}
+	}
+
+	for _, configFile := range configFiles {
+		info, err := workspace.LoadProjectStack(configFile)
+		if err == nil && external.IsExternalSecretsProviderURL(info.SecretsProvider) {
+			external.Trust(info.SecretsProvider)
+		}
+	}
+}
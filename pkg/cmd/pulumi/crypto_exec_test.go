@@ -0,0 +1,80 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/secrets/external"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func TestNewExternalSecretsManager(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub secrets provider program is a shell script")
+	}
+
+	// The stub program responds with the values it is sent, so "ciphertexts" are the same as plaintexts.
+	dir := t.TempDir()
+	program := filepath.Join(dir, "secrets-provider")
+	require.NoError(t, os.WriteFile(program, []byte("#!/bin/sh\ncat\n"), 0700)) //nolint:gosec // must be executable
+	configFile := filepath.Join(dir, "Pulumi.dev.yaml")
+	ps := &workspace.ProjectStack{Config: make(config.Map), EncryptionSalt: "v1:salt"}
+	require.NoError(t, ps.Save(configFile))
+
+	sm, err := newExternalSecretsManager("dev", configFile, "exec://"+program)
+	require.NoError(t, err)
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue("hunter2")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", ciphertext)
+
+	// The provider replaces the passphrase provider in the stack's configuration file.
+	ps, err = workspace.LoadProjectStack(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "exec://"+program, ps.SecretsProvider)
+	assert.Empty(t, ps.EncryptionSalt)
+}
+
+//nolint:paralleltest // changes the working directory
+func TestTrustConfiguredSecretsProviders(t *testing.T) {
+	dir := t.TempDir()
+	chdir(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pulumi.yaml"), []byte("name: proj\nruntime: go\n"), 0600))
+	configured := "exec:///tmp/pulumi-test-configured-program"
+	ps := &workspace.ProjectStack{Config: make(config.Map), SecretsProvider: configured}
+	require.NoError(t, ps.Save(filepath.Join(dir, "Pulumi.dev.yaml")))
+
+	trustConfiguredSecretsProviders()
+
+	// The programs are never run, so they do not need to exist.
+	_, err := external.NewExternalSecretsManagerFromState([]byte(`{"url":"` + configured + `"}`))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "refusing to run")
+
+	// Checkpoints cannot name programs that no stack configuration file selects.
+	_, err = external.NewExternalSecretsManagerFromState([]byte(`{"url":"exec:///tmp/pulumi-test-other-program"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to run")
+}
@@ -44,6 +44,8 @@ func newDestroyCmd() *cobra.Command {
 	var diffDisplay bool
 	var eventLogPath string
 	var parallel int
+	var parallelLimits []string
+	var retries int
 	var refresh string
 	var showConfig bool
 	var showReplacementSteps bool
@@ -75,7 +77,7 @@ func newDestroyCmd() *cobra.Command {
 				return result.FromError(errors.New("--yes must be passed in to proceed when running in non-interactive mode"))
 			}
 
-			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes, false /*previewOnly*/)
 			if err != nil {
 				return result.FromError(err)
 			}
@@ -162,6 +164,10 @@ func newDestroyCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			limits, err := getParallelismLimits(proj, parallelLimits)
+			if err != nil {
+				return result.FromError(err)
+			}
 
 			if targets != nil && len(*targets) > 0 && excludeProtected {
 				return result.FromError(errors.New("You cannot specify --target and --exclude-protected"))
@@ -187,6 +193,8 @@ func newDestroyCmd() *cobra.Command {
 
 			opts.Engine = engine.UpdateOptions{
 				Parallel:                  parallel,
+				ParallelismLimits:         limits,
+				Retries:                   retries,
 				Debug:                     debug,
 				Refresh:                   refreshOption,
 				DestroyTargets:            targetUrns,
@@ -254,6 +262,14 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().StringArrayVar(
+		&parallelLimits, "parallel-limit", []string{},
+		"Limit how many resource operations of a package or resource type run in parallel at once, as "+
+			"`type=limit` (e.g. aws=10 or aws:route53/record:Record=2). May be specified multiple times")
+	cmd.PersistentFlags().IntVar(
+		&retries, "retries", 0,
+		"Retry resource operations that fail with an error the provider reports as transient up to N times, "+
+			"waiting longer between each attempt")
 	cmd.PersistentFlags().StringVarP(
 		&refresh, "refresh", "r", "",
 		"Refresh the state of the stack's resources before this update")
@@ -405,7 +405,7 @@ func newImportCmd() *cobra.Command {
 				return result.FromError(errors.New("--yes must be passed in to proceed when running in non-interactive mode"))
 			}
 
-			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes, false /*previewOnly*/)
 			if err != nil {
 				return result.FromError(err)
 			}
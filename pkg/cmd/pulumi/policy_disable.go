@@ -34,7 +34,7 @@ func newPolicyDisableCmd() *cobra.Command {
 		Short: "Disable a Policy Pack for a Pulumi organization",
 		Long:  "Disable a Policy Pack for a Pulumi organization",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, cliArgs []string) error {
-			// Obtain current PolicyPack, tied to the Pulumi service backend.
+			// Obtain current PolicyPack, tied to the current backend.
 			var err error
 			policyPack, err := requirePolicyPack(cliArgs[0])
 			if err != nil {
@@ -41,7 +41,7 @@ func newPolicyEnableCmd() *cobra.Command {
 		Long: "Enable a Policy Pack for a Pulumi organization. " +
 			"Can specify latest to enable the latest version of the Policy Pack or a specific version number.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, cliArgs []string) error {
-			// Obtain current PolicyPack, tied to the Pulumi service backend.
+			// Obtain current PolicyPack, tied to the current backend.
 			policyPack, err := requirePolicyPack(cliArgs[0])
 			if err != nil {
 				return err
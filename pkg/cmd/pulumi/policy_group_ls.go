@@ -34,6 +34,8 @@ func newPolicyGroupCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(newPolicyGroupLsCmd())
+	cmd.AddCommand(newPolicyGroupAddStackCmd())
+	cmd.AddCommand(newPolicyGroupRmStackCmd())
 	return cmd
 }
 
@@ -0,0 +1,78 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newPolicyGroupAddStackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-stack <policy-group> <stack-name>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Add a stack to a Policy Group of a self-managed backend",
+		Long: "Add a stack to a Policy Group of a self-managed backend\n" +
+			"\n" +
+			"The Policy Packs enabled for the group are enforced on every update of the stack.\n" +
+			"The group is created if it does not exist. Policy Groups of the Pulumi service are\n" +
+			"managed in the Pulumi Console instead.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, cliArgs []string) error {
+			lb, err := currentFilestateBackend("policy group add-stack")
+			if err != nil {
+				return err
+			}
+			ref, err := lb.ParseStackReference(cliArgs[1])
+			if err != nil {
+				return err
+			}
+			if err = lb.AddStackToPolicyGroup(commandContext(), cliArgs[0], ref); err != nil {
+				return err
+			}
+			fmt.Printf("Added stack %s to Policy Group %s\n", ref, cliArgs[0])
+			return nil
+		}),
+	}
+}
+
+func newPolicyGroupRmStackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm-stack <policy-group> <stack-name>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Remove a stack from a Policy Group of a self-managed backend",
+		Long: "Remove a stack from a Policy Group of a self-managed backend\n" +
+			"\n" +
+			"The Policy Packs enabled for the group are no longer enforced on the stack, unless they\n" +
+			"are also enabled for another group the stack belongs to.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, cliArgs []string) error {
+			lb, err := currentFilestateBackend("policy group rm-stack")
+			if err != nil {
+				return err
+			}
+			ref, err := lb.ParseStackReference(cliArgs[1])
+			if err != nil {
+				return err
+			}
+			if err = lb.RemoveStackFromPolicyGroup(commandContext(), cliArgs[0], ref); err != nil {
+				return err
+			}
+			fmt.Printf("Removed stack %s from Policy Group %s\n", ref, cliArgs[0])
+			return nil
+		}),
+	}
+}
@@ -21,11 +21,9 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
-	"github.com/pulumi/pulumi/pkg/v3/backend/httpstate"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -33,10 +31,11 @@ func newPolicyPublishCmd() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "publish [org-name]",
 		Args:  cmdutil.MaximumNArgs(1),
-		Short: "Publish a Policy Pack to the Pulumi service",
-		Long: "Publish a Policy Pack to the Pulumi service\n" +
+		Short: "Publish a Policy Pack to the current backend",
+		Long: "Publish a Policy Pack to the current backend\n" +
 			"\n" +
-			"If an organization name is not specified, the current user account is used.",
+			"If an organization name is not specified, the current user account is used. Self-managed\n" +
+			"backends have no organizations and store published Policy Packs alongside their stacks.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 
 			var orgName string
@@ -57,7 +56,7 @@ func newPolicyPublishCmd() *cobra.Command {
 			policyPackRef := fmt.Sprintf("%s/", orgName)
 
 			//
-			// Obtain current PolicyPack, tied to the Pulumi service backend.
+			// Obtain current PolicyPack, tied to the current backend.
 			//
 
 			policyPack, err := requirePolicyPack(policyPackRef)
@@ -105,20 +104,14 @@ func newPolicyPublishCmd() *cobra.Command {
 
 func requirePolicyPack(policyPack string) (backend.PolicyPack, error) {
 	//
-	// Attempt to log into cloud backend.
+	// Attempt to log into the current backend. Self-managed backends store policy packs in their bucket.
 	//
 
-	cloudURL, err := workspace.GetCurrentCloudURL()
-	if err != nil {
-		return nil, fmt.Errorf("`pulumi policy` command requires the user to be logged into the Pulumi service: %w", err)
-
-	}
-
 	displayOptions := display.Options{
 		Color: cmdutil.GetGlobalColorization(),
 	}
 
-	b, err := httpstate.Login(commandContext(), cmdutil.Diag(), cloudURL, displayOptions)
+	b, err := currentBackend(displayOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +27,6 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 )
 
@@ -51,6 +50,8 @@ func newPreviewCmd() *cobra.Command {
 	var diffDisplay bool
 	var eventLogPath string
 	var parallel int
+	var parallelLimits []string
+	var retries int
 	var refresh string
 	var showConfig bool
 	var showReplacementSteps bool
@@ -151,6 +152,9 @@ func newPreviewCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(fmt.Errorf("getting stack configuration: %w", err))
 			}
+			if err = applyProjectConfigSchema(proj, &cfg); err != nil {
+				return result.FromError(err)
+			}
 
 			targetURNs := []resource.URN{}
 			for _, t := range targets {
@@ -171,11 +175,17 @@ func newPreviewCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			limits, err := getParallelismLimits(proj, parallelLimits)
+			if err != nil {
+				return result.FromError(err)
+			}
 
 			opts := backend.UpdateOptions{
 				Engine: engine.UpdateOptions{
 					LocalPolicyPacks:          engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
 					Parallel:                  parallel,
+					ParallelismLimits:         limits,
+					Retries:                   retries,
 					Debug:                     debug,
 					Refresh:                   refreshOption,
 					ReplaceTargets:            replaceURNs,
@@ -185,7 +195,7 @@ func newPreviewCmd() *cobra.Command {
 					DisableOutputValues:       disableOutputValues(),
 					UpdateTargets:             targetURNs,
 					TargetDependents:          targetDependents,
-					ExperimentalPlans:         hasExperimentalCommands() || planFilePath != "",
+					GeneratePlan:              hasExperimentalCommands() || planFilePath != "",
 				},
 				Display: displayOpts,
 			}
@@ -249,10 +259,7 @@ func newPreviewCmd() *cobra.Command {
 		"Config keys contain a path to a property in a map or list to set")
 	cmd.PersistentFlags().StringVar(
 		&planFilePath, "save-plan", "",
-		"[EXPERIMENTAL] Save the operations proposed by the preview to a plan file at the given path")
-	if !hasExperimentalCommands() {
-		contract.AssertNoError(cmd.PersistentFlags().MarkHidden("save-plan"))
-	}
+		"Save the operations proposed by the preview to a plan file at the given path")
 	cmd.Flags().BoolVarP(
 		&showSecrets, "show-secrets", "", false, "Emit secrets in plaintext in the plan file. Defaults to `false`")
 
@@ -295,6 +302,14 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().StringArrayVar(
+		&parallelLimits, "parallel-limit", []string{},
+		"Limit how many resource operations of a package or resource type run in parallel at once, as "+
+			"`type=limit` (e.g. aws=10 or aws:route53/record:Record=2). May be specified multiple times")
+	cmd.PersistentFlags().IntVar(
+		&retries, "retries", 0,
+		"Retry resource operations that fail with an error the provider reports as transient up to N times, "+
+			"waiting longer between each attempt")
 	cmd.PersistentFlags().StringVarP(
 		&refresh, "refresh", "r", "",
 		"Refresh the state of the stack's resources before this update")
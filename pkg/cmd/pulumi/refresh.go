@@ -18,12 +18,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
@@ -32,6 +37,8 @@ import (
 func newRefreshCmd() *cobra.Command {
 	var debug bool
 	var expectNop bool
+	var previewOnly bool
+	var exitCode bool
 	var message string
 	var execKind string
 	var execAgent string
@@ -42,6 +49,8 @@ func newRefreshCmd() *cobra.Command {
 	var diffDisplay bool
 	var eventLogPath string
 	var parallel int
+	var parallelLimits []string
+	var retries int
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
@@ -62,16 +71,21 @@ func newRefreshCmd() *cobra.Command {
 			"synch with respect to the cloud provider's source of truth.\n" +
 			"\n" +
 			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
-			"`--cwd` flag to use a different directory.",
+			"`--cwd` flag to use a different directory.\n" +
+			"\n" +
+			"To detect drift without changing the stack, pass `--preview-only`. Once the preview finishes,\n" +
+			"the resources whose outputs have changed or that no longer exist are summarized; with `--json`\n" +
+			"the summary is emitted as JSON and the progress display is written to stderr instead. Pass\n" +
+			"`--exit-code` to exit with code 2 when drift is detected.",
 		Args: cmdutil.NoArgs,
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
 			yes = yes || skipConfirmations()
 			interactive := cmdutil.Interactive()
-			if !interactive && !yes {
+			if !interactive && !yes && !previewOnly {
 				return result.FromError(errors.New("--yes must be passed in to proceed when running in non-interactive mode"))
 			}
 
-			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes, previewOnly)
 			if err != nil {
 				return result.FromError(err)
 			}
@@ -94,6 +108,13 @@ func newRefreshCmd() *cobra.Command {
 				JSONDisplay:          jsonDisplay,
 			}
 
+			// When reporting drift as JSON, the report takes the place of the JSON display on stdout.
+			driftJSON := previewOnly && jsonDisplay
+			if driftJSON {
+				opts.Display.JSONDisplay = false
+				opts.Display.Stdout = os.Stderr
+			}
+
 			// we only suppress permalinks if the user passes true. the default is an empty string
 			// which we pass as 'false'
 			if suppressPermalink == "true" {
@@ -143,8 +164,15 @@ func newRefreshCmd() *cobra.Command {
 				targetUrns = append(targetUrns, resource.URN(t))
 			}
 
+			limits, err := getParallelismLimits(proj, parallelLimits)
+			if err != nil {
+				return result.FromError(err)
+			}
+
 			opts.Engine = engine.UpdateOptions{
 				Parallel:                  parallel,
+				ParallelismLimits:         limits,
+				Retries:                   retries,
 				Debug:                     debug,
 				UseLegacyDiff:             useLegacyDiff(),
 				DisableProviderPreview:    disableProviderPreview(),
@@ -152,6 +180,9 @@ func newRefreshCmd() *cobra.Command {
 				DisableOutputValues:       disableOutputValues(),
 				RefreshTargets:            targetUrns,
 			}
+			if previewOnly || exitCode {
+				opts.Engine.DriftReport = engine.NewDriftReport()
+			}
 
 			changes, res := s.Refresh(commandContext(), backend.UpdateOperation{
 				Proj:               proj,
@@ -170,9 +201,25 @@ func newRefreshCmd() *cobra.Command {
 				return PrintEngineResult(res)
 			case expectNop && changes != nil && changes.HasChanges():
 				return result.FromError(errors.New("error: no changes were expected but changes occurred"))
-			default:
-				return nil
 			}
+
+			drift := opts.Engine.DriftReport
+			if previewOnly {
+				if driftJSON {
+					if err := printJSON(makeDriftReportJSON(drift.Resources())); err != nil {
+						return result.FromError(err)
+					}
+				} else {
+					printDriftReport(drift.Resources(), opts.Display)
+				}
+			}
+			if exitCode && drift.HasDrift() {
+				return result.FromError(&cmdutil.ExitCodeError{
+					Code: driftExitCode,
+					Err:  fmt.Errorf("drift was detected in %d resources", len(drift.Resources())),
+				})
+			}
+			return nil
 		}),
 	}
 
@@ -182,6 +229,12 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&expectNop, "expect-no-changes", false,
 		"Return an error if any changes occur during this update")
+	cmd.PersistentFlags().BoolVar(
+		&previewOnly, "preview-only", false,
+		"Only show a preview of the refresh and summarize any drift, but don't perform the refresh itself")
+	cmd.PersistentFlags().BoolVar(
+		&exitCode, "exit-code", false,
+		"Exit with code 2 if any resources have drifted from the state recorded in the stack")
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
@@ -207,6 +260,14 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().StringArrayVar(
+		&parallelLimits, "parallel-limit", []string{},
+		"Limit how many resource operations of a package or resource type run in parallel at once, as "+
+			"`type=limit` (e.g. aws=10 or aws:route53/record:Record=2). May be specified multiple times")
+	cmd.PersistentFlags().IntVar(
+		&retries, "retries", 0,
+		"Retry resource operations that fail with an error the provider reports as transient up to N times, "+
+			"waiting longer between each attempt")
 	cmd.PersistentFlags().BoolVar(
 		&showReplacementSteps, "show-replacement-steps", false,
 		"Show detailed resource replacement creates and deletes instead of a single step")
@@ -243,3 +304,57 @@ func newRefreshCmd() *cobra.Command {
 
 	return cmd
 }
+
+// driftExitCode is the code `pulumi refresh --exit-code` exits with when drift is detected.
+const driftExitCode = 2
+
+// resourceDriftJSON is the shape of a single drifted resource in the output of `pulumi refresh --preview-only --json`.
+type resourceDriftJSON struct {
+	URN  resource.URN  `json:"urn"`
+	Type string        `json:"type"`
+	Op   deploy.StepOp `json:"op"`
+	// Outputs describes the outputs of an updated resource that have changed.
+	Outputs map[string]stackDiffPropertyJSON `json:"outputs,omitempty"`
+}
+
+func makeDriftReportJSON(drift []engine.ResourceDrift) []resourceDriftJSON {
+	result := make([]resourceDriftJSON, len(drift))
+	for i, d := range drift {
+		result[i] = resourceDriftJSON{
+			URN:     d.URN,
+			Type:    string(d.Type),
+			Op:      d.Op,
+			Outputs: makeStackDiffPropertiesJSON(d.Outputs, false /*showSecrets*/),
+		}
+	}
+	return result
+}
+
+func printDriftReport(drift []engine.ResourceDrift, opts display.Options) {
+	out := opts.Stdout
+	if out == nil {
+		out = os.Stdout
+	}
+
+	fmt.Fprintln(out)
+	if len(drift) == 0 {
+		fmt.Fprintln(out, "No drift detected.")
+		return
+	}
+
+	fmt.Fprintf(out, "Drift detected in %d resources:\n", len(drift))
+	for _, d := range drift {
+		fmt.Fprint(out, opts.Color.Colorize(
+			fmt.Sprintf("    %s%s%s%s\n", d.Op.Color(), d.Op.RawPrefix(), d.URN, colors.Reset)))
+		if d.Op == deploy.OpDelete {
+			fmt.Fprintln(out, "        the resource no longer exists")
+		} else if d.Outputs != nil {
+			var keys []string
+			for _, k := range d.Outputs.ChangedKeys() {
+				keys = append(keys, string(k))
+			}
+			sort.Strings(keys)
+			fmt.Fprintf(out, "        changed outputs: %s\n", strings.Join(keys, ", "))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestDriftReport(t *testing.T) {
+	t.Parallel()
+
+	updated := resource.NewURN("dev", "proj", "", "pkg:index:typ", "updated")
+	deleted := resource.NewURN("dev", "proj", "", "pkg:index:typ", "deleted")
+	olds := resource.PropertyMap{
+		"size":     resource.NewNumberProperty(1),
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+	}
+	news := resource.PropertyMap{
+		"size":     resource.NewNumberProperty(2),
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter3")),
+	}
+	drift := []engine.ResourceDrift{
+		{URN: updated, Type: "pkg:index:typ", Op: deploy.OpUpdate, Outputs: olds.Diff(news)},
+		{URN: deleted, Type: "pkg:index:typ", Op: deploy.OpDelete},
+	}
+
+	// Secrets are never shown in the JSON report.
+	report := makeDriftReportJSON(drift)
+	if assert.Len(t, report, 2) {
+		assert.Equal(t, deploy.OpUpdate, report[0].Op)
+		assert.Equal(t, stackDiffPropertyJSON{Kind: "update", Old: 1.0, New: 2.0}, report[0].Outputs["size"])
+		assert.Equal(t, "[secret]", report[0].Outputs["password"].New)
+		assert.Equal(t, deploy.OpDelete, report[1].Op)
+		assert.Empty(t, report[1].Outputs)
+	}
+
+	var out bytes.Buffer
+	printDriftReport(drift, display.Options{Color: colors.Never, Stdout: &out})
+	assert.Equal(t, "\n"+
+		"Drift detected in 2 resources:\n"+
+		"    ~ "+string(updated)+"\n"+
+		"        changed outputs: password, size\n"+
+		"    - "+string(deleted)+"\n"+
+		"        the resource no longer exists\n", out.String())
+
+	out.Reset()
+	printDriftReport(nil, display.Options{Color: colors.Never, Stdout: &out})
+	assert.Equal(t, "\nNo drift detected.\n", out.String())
+}
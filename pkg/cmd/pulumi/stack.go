@@ -174,6 +174,7 @@ func newStackCmd() *cobra.Command {
 	cmd.Flags().BoolVar(
 		&showStackName, "show-name", false, "Display only the stack name")
 
+	cmd.AddCommand(newStackDiffCmd())
 	cmd.AddCommand(newStackExportCmd())
 	cmd.AddCommand(newStackGraphCmd())
 	cmd.AddCommand(newStackImportCmd())
@@ -186,6 +187,9 @@ func newStackCmd() *cobra.Command {
 	cmd.AddCommand(newStackRenameCmd())
 	cmd.AddCommand(newStackChangeSecretsProviderCmd())
 	cmd.AddCommand(newStackHistoryCmd())
+	cmd.AddCommand(newStackMigrateCmd())
+	cmd.AddCommand(newStackRotateSecretsCmd())
+	cmd.AddCommand(newStackAuditSecretsCmd())
 	cmd.AddCommand(newStackUnselectCmd())
 
 	return cmd
@@ -0,0 +1,160 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/secretscan"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+// secretFindingJSON is the shape of the --json output of `pulumi stack audit-secrets`. The values themselves are
+// never printed.
+type secretFindingJSON struct {
+	URN      resource.URN `json:"urn"`
+	Kind     string       `json:"kind"`
+	Property string       `json:"property"`
+	Reason   string       `json:"reason"`
+}
+
+func newStackAuditSecretsCmd() *cobra.Command {
+	var stackName string
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "audit-secrets",
+		Args:  cmdutil.NoArgs,
+		Short: "Find plaintext values in a stack's state that look like secrets",
+		Long: "Find plaintext values in a stack's state that look like secrets\n" +
+			"\n" +
+			"Scans the inputs and outputs of the stack's resources for values that are stored in plaintext but\n" +
+			"look like they should have been secrets: values that match the patterns of well-known credentials,\n" +
+			"high-entropy strings, and the values of the stack's secret configuration keys. The values themselves\n" +
+			"are never printed. The command fails if any such values are found, so that it can be used in CI.\n" +
+			"\n" +
+			"To check the state of a stack's resources as they are updated, pass --audit-secrets to `pulumi up`.",
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(stackName, false /*offerNew*/, opts, false /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			findings, err := auditStackSecrets(commandContext(), s)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			if jsonOut {
+				findingsJSON := make([]secretFindingJSON, 0, len(findings))
+				for _, f := range findings {
+					findingsJSON = append(findingsJSON, secretFindingJSON{
+						URN:      f.URN,
+						Kind:     secretFindingKind(f),
+						Property: f.Property.String(),
+						Reason:   f.Reason,
+					})
+				}
+				if err = printJSON(findingsJSON); err != nil {
+					return result.FromError(err)
+				}
+			} else if len(findings) == 0 {
+				fmt.Printf("No plaintext values that look like secrets were found in stack %s\n", s.Ref())
+			} else {
+				printSecretFindings(findings)
+			}
+
+			if len(findings) != 0 {
+				return result.Errorf("found %d plaintext value(s) that look like secrets in stack %s; "+
+					"mark them as secrets so that they are encrypted", len(findings), s.Ref())
+			}
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit output as JSON")
+
+	return cmd
+}
+
+// auditStackSecrets returns the plaintext values in the state of a stack's resources that look like secrets.
+func auditStackSecrets(ctx context.Context, s backend.Stack) ([]secretscan.Finding, error) {
+	ps, err := loadProjectStack(s)
+	if err != nil {
+		return nil, err
+	}
+	secretConfig := make(map[config.Key]string)
+	if ps.Config.HasSecureValue() {
+		decrypter, err := getStackDecrypter(s)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range ps.Config.SecureKeys() {
+			v, err := ps.Config[k].Value(decrypter)
+			if err != nil {
+				return nil, fmt.Errorf("decrypting configuration value %s: %w", k, err)
+			}
+			secretConfig[k] = v
+		}
+	}
+
+	deployment, err := s.ExportDeployment(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+
+	return secretscan.NewScanner(secretConfig).ScanSnapshot(snap), nil
+}
+
+func secretFindingKind(f secretscan.Finding) string {
+	if f.Output {
+		return "output"
+	}
+	return "input"
+}
+
+func printSecretFindings(findings []secretscan.Finding) {
+	rows := []cmdutil.TableRow{}
+	for _, f := range findings {
+		rows = append(rows, cmdutil.TableRow{
+			Columns: []string{string(f.URN), secretFindingKind(f), f.Property.String(), f.Reason},
+		})
+	}
+
+	cmdutil.PrintTable(cmdutil.Table{
+		Headers: []string{"URN", "KIND", "PROPERTY", "REASON"},
+		Rows:    rows,
+	})
+}
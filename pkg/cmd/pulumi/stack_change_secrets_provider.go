@@ -21,7 +21,9 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -29,12 +31,14 @@ import (
 )
 
 func newStackChangeSecretsProviderCmd() *cobra.Command {
+	var stack string
 	var cmd = &cobra.Command{
 		Use:   "change-secrets-provider <new-secrets-provider>",
 		Args:  cmdutil.ExactArgs(1),
 		Short: "Change the secrets provider for the current stack",
 		Long: "Change the secrets provider for the current stack. " +
-			"Valid secret providers types are `default`, `passphrase`, `awskms`, `azurekeyvault`, `gcpkms`, `hashivault`.\n\n" +
+			"Valid secret providers types are `default`, `passphrase`, `awskms`, `azurekeyvault`, `gcpkms`, `hashivault`, " +
+			"`exec`.\n\n" +
 			"To change to using the Pulumi Default Secrets Provider, use the following:\n" +
 			"\n" +
 			"pulumi stack change-secrets-provider default" +
@@ -50,7 +54,11 @@ func newStackChangeSecretsProviderCmd() *cobra.Command {
 			"\"azurekeyvault://mykeyvaultname.vault.azure.net/keys/mykeyname\"`\n" +
 			"* `pulumi stack change-secrets-provider " +
 			"\"gcpkms://projects/<p>/locations/<l>/keyRings/<r>/cryptoKeys/<k>\"`\n" +
-			"* `pulumi stack change-secrets-provider \"hashivault://mykey\"`",
+			"* `pulumi stack change-secrets-provider \"hashivault://mykey\"`\n" +
+			"\n" +
+			"To change the stack to use an external program that encrypts and decrypts secrets, use the following:\n" +
+			"\n" +
+			"* `pulumi stack change-secrets-provider \"exec:///path/to/program\"`",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
@@ -68,7 +76,7 @@ func newStackChangeSecretsProviderCmd() *cobra.Command {
 			}
 
 			// Get the current stack and its project
-			currentStack, err := requireStack("", false, opts, true /*setCurrent*/)
+			currentStack, err := requireStack(stack, false, opts, stack == "" /*setCurrent*/)
 			if err != nil {
 				return err
 			}
@@ -104,6 +112,9 @@ func newStackChangeSecretsProviderCmd() *cobra.Command {
 		}),
 	}
 
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+
 	return cmd
 }
 
@@ -116,6 +127,35 @@ func migrateOldConfigAndCheckpointToNewSecretsProvider(ctx context.Context, curr
 		return err
 	}
 
+	if err := migrateOldConfigToNewSecretsProvider(currentStack, currentConfig, decrypter,
+		newSecretsManager); err != nil {
+		return err
+	}
+
+	// Load the current checkpoint so those secrets can also be decrypted
+	checkpoint, err := currentStack.ExportDeployment(ctx)
+	if err != nil {
+		return err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(checkpoint, stack.DefaultSecretsProvider)
+	if err != nil {
+		return checkDeploymentVersionError(err, currentStack.Ref().Name().String())
+	}
+
+	// Reserialize the Snapshopshot with the NewSecrets Manager
+	dep, err := serializeUntypedDeployment(snap, newSecretsManager)
+	if err != nil {
+		return err
+	}
+
+	// Import the newly changes Deployment
+	return currentStack.ImportDeployment(ctx, dep)
+}
+
+// migrateOldConfigToNewSecretsProvider re-encrypts the given configuration, which was read before the stack's secrets
+// provider was changed, with the stack's new secrets manager and saves it to the stack's configuration file.
+func migrateOldConfigToNewSecretsProvider(currentStack backend.Stack, currentConfig config.Map,
+	decrypter config.Decrypter, newSecretsManager secrets.Manager) error {
 	// get the encrypter for the new secrets manager
 	newEncrypter, err := newSecretsManager.Encrypter()
 	if err != nil {
@@ -140,36 +180,23 @@ func migrateOldConfigAndCheckpointToNewSecretsProvider(ctx context.Context, curr
 		}
 	}
 
-	if err := saveProjectStack(currentStack, reloadedProjectStack); err != nil {
-		return err
-	}
-
-	// Load the current checkpoint so those secrets can also be decrypted
-	checkpoint, err := currentStack.ExportDeployment(ctx)
-	if err != nil {
-		return err
-	}
-	snap, err := stack.DeserializeUntypedDeployment(checkpoint, stack.DefaultSecretsProvider)
-	if err != nil {
-		return checkDeploymentVersionError(err, currentStack.Ref().Name().String())
-	}
+	return saveProjectStack(currentStack, reloadedProjectStack)
+}
 
-	// Reserialize the Snapshopshot with the NewSecrets Manager
-	reserializedDeployment, err := stack.SerializeDeployment(snap, newSecretsManager, false /*showSecrets*/)
+// serializeUntypedDeployment serializes a snapshot with the given secrets manager, ready to be imported into a stack.
+func serializeUntypedDeployment(snap *deploy.Snapshot, sm secrets.Manager) (*apitype.UntypedDeployment, error) {
+	deployment, err := stack.SerializeDeployment(snap, sm, false /*showSecrets*/)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	bytes, err := json.Marshal(reserializedDeployment)
+	bytes, err := json.Marshal(deployment)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	dep := apitype.UntypedDeployment{
+	return &apitype.UntypedDeployment{
 		Version:    apitype.DeploymentSchemaVersionCurrent,
 		Deployment: bytes,
-	}
-
-	// Import the newly changes Deployment
-	return currentStack.ImportDeployment(ctx, &dep)
+	}, nil
 }
@@ -0,0 +1,248 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newStackDiffCmd() *cobra.Command {
+	var stackName string
+	var version string
+	var file string
+	var jsonOut bool
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Args:  cmdutil.NoArgs,
+		Short: "Show how a stack's state has changed since a previous checkpoint",
+		Long: "Show how a stack's state has changed since a previous checkpoint.\n" +
+			"\n" +
+			"This command compares the resources recorded in the stack's current checkpoint with\n" +
+			"those recorded in an earlier one, and prints the resources that were created, deleted,\n" +
+			"replaced or updated along with their property-level differences. The earlier checkpoint\n" +
+			"is either a previous version of the stack (see `pulumi stack history`), or a deployment\n" +
+			"previously written by `pulumi stack export`.\n" +
+			"\n" +
+			"Secret values are not shown unless --show-secrets is passed.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if (version == "") == (file == "") {
+				return fmt.Errorf("exactly one of --version or --file must be specified")
+			}
+
+			s, err := requireStack(stackName, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return err
+			}
+
+			var deployment *apitype.UntypedDeployment
+			if version != "" {
+				be := s.Backend()
+				specificExpBE, ok := be.(backend.SpecificDeploymentExporter)
+				if !ok {
+					return fmt.Errorf("the current backend (%s) does not provide the ability to export previous deployments",
+						be.Name())
+				}
+				deployment, err = specificExpBE.ExportDeploymentForVersion(ctx, s, version)
+				if err != nil {
+					return err
+				}
+			} else {
+				f, err := os.Open(file)
+				if err != nil {
+					return fmt.Errorf("could not open file: %w", err)
+				}
+				defer f.Close()
+
+				deployment = &apitype.UntypedDeployment{}
+				if err = json.NewDecoder(f).Decode(deployment); err != nil {
+					return fmt.Errorf("could not read deployment: %w", err)
+				}
+			}
+
+			old, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+			if err != nil {
+				return checkDeploymentVersionError(err, stackName)
+			}
+			current, err := s.Snapshot(ctx)
+			if err != nil {
+				return err
+			}
+
+			diffs := deploy.DiffSnapshots(old, current)
+			if showSecrets {
+				log3rdPartySecretsProviderDecryptionEvent(ctx, s, "", "pulumi stack diff")
+			}
+
+			if jsonOut {
+				return printJSON(makeStackDiffJSON(diffs, showSecrets))
+			}
+			printStackDiff(diffs, opts, showSecrets)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().StringVar(
+		&version, "version", "", "The previous stack version to compare the current state against")
+	cmd.Flags().StringVar(
+		&file, "file", "", "A file containing a deployment exported with `pulumi stack export` to compare against")
+	cmd.Flags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit the differences as JSON")
+	cmd.Flags().BoolVar(
+		&showSecrets, "show-secrets", false, "Display secret values in plaintext. Defaults to `false`")
+
+	return cmd
+}
+
+// stackDiffResourceJSON is the shape of a single resource's changes in the output of `pulumi stack diff --json`.
+type stackDiffResourceJSON struct {
+	URN  resource.URN  `json:"urn"`
+	Type string        `json:"type"`
+	Op   deploy.StepOp `json:"op"`
+	// Inputs and Outputs describe the changed properties of updated and replaced resources.
+	Inputs  map[string]stackDiffPropertyJSON `json:"inputs,omitempty"`
+	Outputs map[string]stackDiffPropertyJSON `json:"outputs,omitempty"`
+}
+
+// stackDiffPropertyJSON describes how a single top-level property changed.
+type stackDiffPropertyJSON struct {
+	Kind string      `json:"kind"` // one of "add", "delete" or "update".
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+func makeStackDiffJSON(diffs []deploy.ResourceDiff, showSecrets bool) []stackDiffResourceJSON {
+	result := make([]stackDiffResourceJSON, len(diffs))
+	for i, d := range diffs {
+		result[i] = stackDiffResourceJSON{
+			URN:     d.URN,
+			Type:    string(latestState(d).Type),
+			Op:      d.Op,
+			Inputs:  makeStackDiffPropertiesJSON(d.Inputs, showSecrets),
+			Outputs: makeStackDiffPropertiesJSON(d.Outputs, showSecrets),
+		}
+	}
+	return result
+}
+
+func makeStackDiffPropertiesJSON(diff *resource.ObjectDiff, showSecrets bool) map[string]stackDiffPropertyJSON {
+	if diff == nil {
+		return nil
+	}
+
+	mappable := func(v resource.PropertyValue) interface{} {
+		return display.MassageSecrets(resource.PropertyMap{"v": v}, showSecrets)["v"].Mappable()
+	}
+
+	props := make(map[string]stackDiffPropertyJSON)
+	for k, v := range diff.Adds {
+		props[string(k)] = stackDiffPropertyJSON{Kind: "add", New: mappable(v)}
+	}
+	for k, v := range diff.Deletes {
+		props[string(k)] = stackDiffPropertyJSON{Kind: "delete", Old: mappable(v)}
+	}
+	for k, v := range diff.Updates {
+		props[string(k)] = stackDiffPropertyJSON{Kind: "update", Old: mappable(v.Old), New: mappable(v.New)}
+	}
+	return props
+}
+
+func printStackDiff(diffs []deploy.ResourceDiff, opts display.Options, showSecrets bool) {
+	if len(diffs) == 0 {
+		fmt.Println("No changes.")
+		return
+	}
+
+	counts := make(map[deploy.StepOp]int)
+	for _, d := range diffs {
+		printResourceDiff(d, opts, showSecrets)
+		counts[d.Op]++
+	}
+
+	fmt.Println()
+	fmt.Println("Resources:")
+	for _, op := range []deploy.StepOp{deploy.OpCreate, deploy.OpUpdate, deploy.OpReplace, deploy.OpDelete} {
+		if c := counts[op]; c > 0 {
+			fmt.Print(opts.Color.Colorize(fmt.Sprintf("    %s%d %s%s\n", op.Color(), c, op.PastTense(), colors.Reset)))
+		}
+	}
+}
+
+// printResourceDiff prints a single resource's changes in the same format used to display the steps of an update.
+func printResourceDiff(d deploy.ResourceDiff, opts display.Options, showSecrets bool) {
+	stateMetadata := func(state *resource.State) *engine.StepEventStateMetadata {
+		if state == nil {
+			return nil
+		}
+		return &engine.StepEventStateMetadata{
+			State:    state,
+			Type:     state.Type,
+			URN:      state.URN,
+			Custom:   state.Custom,
+			Delete:   state.Delete,
+			ID:       state.ID,
+			Parent:   state.Parent,
+			Protect:  state.Protect,
+			Inputs:   display.MassageSecrets(state.Inputs, showSecrets),
+			Outputs:  display.MassageSecrets(state.Outputs, showSecrets),
+			Provider: state.Provider,
+		}
+	}
+
+	res := latestState(d)
+	metadata := engine.StepEventMetadata{
+		Op:       d.Op,
+		URN:      d.URN,
+		Type:     res.Type,
+		Old:      stateMetadata(d.Old),
+		New:      stateMetadata(d.New),
+		Res:      stateMetadata(res),
+		Provider: res.Provider,
+	}
+
+	fmt.Print(opts.Color.Colorize(
+		engine.GetResourcePropertiesSummary(metadata, 0) +
+			engine.GetResourcePropertiesDetails(metadata, 0, false /*planning*/, false /*summary*/, false /*debug*/)))
+}
+
+// latestState returns the newest known state of a changed resource.
+func latestState(d deploy.ResourceDiff) *resource.State {
+	if d.New != nil {
+		return d.New
+	}
+	return d.Old
+}
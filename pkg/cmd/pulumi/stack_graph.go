@@ -1,4 +1,4 @@
-// Copyright 2016-2018, Pulumi Corporation.
+// Copyright 2016-2022, Pulumi Corporation.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,15 +15,20 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/graph"
 	"github.com/pulumi/pulumi/pkg/v3/graph/dotconv"
+	"github.com/pulumi/pulumi/pkg/v3/graph/graphmlconv"
+	"github.com/pulumi/pulumi/pkg/v3/graph/mermaidconv"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/spf13/cobra"
 )
@@ -40,8 +45,18 @@ var dependencyEdgeColor string
 // The color of parent edges in the graph. Defaults to #AA6639, an orange.
 var parentEdgeColor string
 
+// The formats that a stack's dependency graph can be written in.
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+	graphFormatJSON    = "json"
+	graphFormatGraphML = "graphml"
+)
+
 func newStackGraphCmd() *cobra.Command {
 	var stackName string
+	var format string
+	var filter graphFilter
 
 	cmd := &cobra.Command{
 		Use:   "graph [filename]",
@@ -50,13 +65,33 @@ func newStackGraphCmd() *cobra.Command {
 		Long: "Export a stack's dependency graph to a file.\n" +
 			"\n" +
 			"This command can be used to view the dependency graph that a Pulumi program\n" +
-			"admitted when it was ran. This graph is output in the DOT format. This command operates\n" +
-			"on your stack's most recent deployment.",
+			"admitted when it was ran. This graph is output in the DOT format by default; use --format\n" +
+			"to write a Mermaid flowchart, JSON nodes and edges, or GraphML instead. This command operates\n" +
+			"on your stack's most recent deployment.\n" +
+			"\n" +
+			"The graph can be limited to resources of particular types with --type, to resources whose URNs\n" +
+			"start with a prefix with --urn-prefix, or to a component and its descendants with --component.\n" +
+			"Edges to resources that are left out are omitted.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
 			}
 
+			var printGraph func(dg *dependencyGraph, w io.Writer) error
+			switch format {
+			case graphFormatDOT:
+				printGraph = func(dg *dependencyGraph, w io.Writer) error { return dotconv.Print(dg, w) }
+			case graphFormatMermaid:
+				printGraph = func(dg *dependencyGraph, w io.Writer) error { return mermaidconv.Print(dg, w) }
+			case graphFormatGraphML:
+				printGraph = func(dg *dependencyGraph, w io.Writer) error { return graphmlconv.Print(dg, w) }
+			case graphFormatJSON:
+				printGraph = printDependencyGraphJSON
+			default:
+				return fmt.Errorf("unknown graph format %q; expected one of %s", format, strings.Join([]string{
+					graphFormatDOT, graphFormatMermaid, graphFormatJSON, graphFormatGraphML}, ", "))
+			}
+
 			s, err := requireStack(stackName, false, opts, false /*setCurrent*/)
 			if err != nil {
 				return err
@@ -71,13 +106,16 @@ func newStackGraphCmd() *cobra.Command {
 				return fmt.Errorf("unable to find snapshot for stack %q", stackName)
 			}
 
-			dg := makeDependencyGraph(snap)
+			dg, err := makeDependencyGraph(snap, filter)
+			if err != nil {
+				return err
+			}
 			file, err := os.Create(args[0])
 			if err != nil {
 				return err
 			}
 
-			if err := dotconv.Print(dg, file); err != nil {
+			if err := printGraph(dg, file); err != nil {
 				_ = file.Close()
 				return err
 			}
@@ -97,9 +135,69 @@ func newStackGraphCmd() *cobra.Command {
 		"Sets the color of dependency edges in the graph")
 	cmd.PersistentFlags().StringVar(&parentEdgeColor, "parent-edge-color", "#AA6639",
 		"Sets the color of parent edges in the graph")
+	cmd.PersistentFlags().StringVar(&format, "format", graphFormatDOT,
+		"The format of the graph: dot, mermaid, json or graphml")
+	cmd.PersistentFlags().StringSliceVar(&filter.types, "type", nil,
+		"Only include resources of the given type. May be specified multiple times")
+	cmd.PersistentFlags().StringVar(&filter.urnPrefix, "urn-prefix", "",
+		"Only include resources whose URNs start with the given prefix")
+	cmd.PersistentFlags().StringVar(&filter.component, "component", "",
+		"Only include the component resource with the given URN and its descendants")
 	return cmd
 }
 
+// graphFilter selects the resources that are included in a stack's dependency graph.
+type graphFilter struct {
+	types     []string // if non-empty, only resources of these types are included.
+	urnPrefix string   // if non-empty, only resources whose URNs have this prefix are included.
+	component string   // if non-empty, only this resource and its descendants are included.
+}
+
+// includedResources returns the URNs of the snapshot's resources that the filter selects.
+func (f graphFilter) includedResources(snapshot *deploy.Snapshot) (map[resource.URN]bool, error) {
+	parents := make(map[resource.URN]resource.URN)
+	for _, res := range snapshot.Resources {
+		parents[res.URN] = res.Parent
+	}
+	if f.component != "" {
+		if _, has := parents[resource.URN(f.component)]; !has {
+			return nil, fmt.Errorf("no resource with URN %q exists in the stack", f.component)
+		}
+	}
+
+	types := make(map[tokens.Type]bool)
+	for _, t := range f.types {
+		types[tokens.Type(t)] = true
+	}
+
+	// inComponent reports whether the resource with the given URN is the filter's component or one of its descendants.
+	inComponent := func(urn resource.URN) bool {
+		seen := make(map[resource.URN]bool)
+		for ; urn != "" && !seen[urn]; urn = parents[urn] {
+			if urn == resource.URN(f.component) {
+				return true
+			}
+			seen[urn] = true
+		}
+		return false
+	}
+
+	included := make(map[resource.URN]bool)
+	for _, res := range snapshot.Resources {
+		if len(types) > 0 && !types[res.Type] {
+			continue
+		}
+		if f.urnPrefix != "" && !strings.HasPrefix(string(res.URN), f.urnPrefix) {
+			continue
+		}
+		if f.component != "" && !inComponent(res.URN) {
+			continue
+		}
+		included[res.URN] = true
+	}
+	return included, nil
+}
+
 // All of the types and code within this file are to provide implementations of the interfaces
 // in the `graph` package, so that we can use the `dotconv` package to output our graph in the
 // DOT format.
@@ -195,13 +293,14 @@ func (vertex *dependencyVertex) Outs() []graph.Edge {
 // the graph. It is constructed directly from a snapshot.
 type dependencyGraph struct {
 	vertices map[resource.URN]*dependencyVertex
+	order    []*dependencyVertex // the vertices in the order their resources appear in the snapshot.
 }
 
 // Roots are edges that point to the root set of our graph. In our case,
 // for simplicity, we define the root set of our dependency graph to be everything.
 func (dg *dependencyGraph) Roots() []graph.Edge {
 	rootEdges := []graph.Edge{}
-	for _, vertex := range dg.vertices {
+	for _, vertex := range dg.order {
 		edge := &dependencyEdge{
 			to:   vertex,
 			from: nil,
@@ -214,22 +313,31 @@ func (dg *dependencyGraph) Roots() []graph.Edge {
 }
 
 // Makes a dependency graph from a deployment snapshot, allocating a vertex
-// for every resource in the graph.
-func makeDependencyGraph(snapshot *deploy.Snapshot) *dependencyGraph {
+// for every resource in the graph that the filter selects.
+func makeDependencyGraph(snapshot *deploy.Snapshot, filter graphFilter) (*dependencyGraph, error) {
+	included, err := filter.includedResources(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
 	dg := &dependencyGraph{
 		vertices: make(map[resource.URN]*dependencyVertex),
 	}
 
 	for _, resource := range snapshot.Resources {
+		if !included[resource.URN] {
+			continue
+		}
 		vertex := &dependencyVertex{
 			graph:    dg,
 			resource: resource,
 		}
 
 		dg.vertices[resource.URN] = vertex
+		dg.order = append(dg.order, vertex)
 	}
 
-	for _, vertex := range dg.vertices {
+	for _, vertex := range dg.order {
 		if !ignoreDependencyEdges {
 			// If we have per-property dependency information, annotate the dependency edges
 			// we generate with the names of the properties associated with each dependency.
@@ -243,7 +351,10 @@ func makeDependencyGraph(snapshot *deploy.Snapshot) *dependencyGraph {
 			// Incoming edges are directly stored within the checkpoint file; they represent
 			// resources on which this vertex immediately depends upon.
 			for _, dep := range vertex.resource.Dependencies {
-				vertexWeDependOn := vertex.graph.vertices[dep]
+				vertexWeDependOn, ok := vertex.graph.vertices[dep]
+				if !ok {
+					continue
+				}
 				edge := &dependencyEdge{to: vertex, from: vertexWeDependOn, labels: depBlame[dep]}
 				vertex.incomingEdges = append(vertex.incomingEdges, edge)
 				vertexWeDependOn.outgoingEdges = append(vertexWeDependOn.outgoingEdges, edge)
@@ -254,8 +365,7 @@ func makeDependencyGraph(snapshot *deploy.Snapshot) *dependencyGraph {
 		// is also displayed as part of this graph, although with different colored
 		// edges.
 		if !ignoreParentEdges {
-			if parent := vertex.resource.Parent; parent != resource.URN("") {
-				parentVertex := dg.vertices[parent]
+			if parentVertex, ok := dg.vertices[vertex.resource.Parent]; ok {
 				vertex.outgoingEdges = append(vertex.outgoingEdges, &parentEdge{
 					to:   parentVertex,
 					from: vertex,
@@ -264,5 +374,68 @@ func makeDependencyGraph(snapshot *deploy.Snapshot) *dependencyGraph {
 		}
 	}
 
-	return dg
+	return dg, nil
+}
+
+// dependencyGraphJSON is the JSON representation of a stack's dependency graph.
+type dependencyGraphJSON struct {
+	Nodes []dependencyNodeJSON `json:"nodes"`
+	Edges []dependencyEdgeJSON `json:"edges"`
+}
+
+type dependencyNodeJSON struct {
+	URN      resource.URN `json:"urn"`
+	Type     tokens.Type  `json:"type"`
+	Provider string       `json:"provider,omitempty"`
+	Parent   resource.URN `json:"parent,omitempty"`
+}
+
+// dependencyEdgeJSON is an edge from a resource to a resource that it depends on or to its parent.
+type dependencyEdgeJSON struct {
+	From resource.URN `json:"from"`
+	To   resource.URN `json:"to"`
+	// Kind is "dependency" or "parent".
+	Kind string `json:"kind"`
+	// Properties are the properties of the dependent resource that depend on the other resource, if known.
+	Properties []string `json:"properties,omitempty"`
+}
+
+// printDependencyGraphJSON prints a dependency graph as JSON.
+func printDependencyGraphJSON(dg *dependencyGraph, w io.Writer) error {
+	out := dependencyGraphJSON{
+		Nodes: []dependencyNodeJSON{},
+		Edges: []dependencyEdgeJSON{},
+	}
+	for _, vertex := range dg.order {
+		res := vertex.resource
+		out.Nodes = append(out.Nodes, dependencyNodeJSON{
+			URN:      res.URN,
+			Type:     res.Type,
+			Provider: res.Provider,
+			Parent:   res.Parent,
+		})
+
+		for _, in := range vertex.incomingEdges {
+			edge := in.(*dependencyEdge)
+			out.Edges = append(out.Edges, dependencyEdgeJSON{
+				From:       res.URN,
+				To:         edge.from.resource.URN,
+				Kind:       "dependency",
+				Properties: edge.labels,
+			})
+		}
+		for _, o := range vertex.outgoingEdges {
+			if edge, ok := o.(*parentEdge); ok {
+				out.Edges = append(out.Edges, dependencyEdgeJSON{
+					From: res.URN,
+					To:   edge.to.resource.URN,
+					Kind: "parent",
+				})
+			}
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(out)
 }
@@ -0,0 +1,122 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph/graphmlconv"
+	"github.com/pulumi/pulumi/pkg/v3/graph/mermaidconv"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func graphTestSnapshot() *deploy.Snapshot {
+	newResource := func(name, t string, parent resource.URN, deps ...resource.URN) *resource.State {
+		return &resource.State{
+			URN:          resource.URN("urn:pulumi:dev::proj::" + t + "::" + name),
+			Type:         tokens.Type(t),
+			Parent:       parent,
+			Dependencies: deps,
+		}
+	}
+
+	comp := newResource("comp", "my:index:Component", "")
+	bucket := newResource("bucket", "aws:s3/bucket:Bucket", comp.URN)
+	object := newResource("object", "aws:s3/bucketObject:BucketObject", comp.URN, bucket.URN)
+	object.PropertyDependencies = map[resource.PropertyKey][]resource.URN{"bucket": {bucket.URN}}
+	other := newResource("other", "aws:s3/bucketObject:BucketObject", "", bucket.URN)
+	return &deploy.Snapshot{Resources: []*resource.State{comp, bucket, object, other}}
+}
+
+func TestDependencyGraphFilters(t *testing.T) {
+	t.Parallel()
+
+	snap := graphTestSnapshot()
+	urns := func(dg *dependencyGraph) []resource.URN {
+		var result []resource.URN
+		for _, v := range dg.order {
+			result = append(result, v.resource.URN)
+		}
+		return result
+	}
+
+	dg, err := makeDependencyGraph(snap, graphFilter{})
+	require.NoError(t, err)
+	assert.Len(t, urns(dg), 4)
+
+	dg, err = makeDependencyGraph(snap, graphFilter{types: []string{"aws:s3/bucketObject:BucketObject"}})
+	require.NoError(t, err)
+	assert.Equal(t, []resource.URN{snap.Resources[2].URN, snap.Resources[3].URN}, urns(dg))
+	// The bucket was left out, so the edges to it are too.
+	for _, v := range dg.order {
+		assert.Empty(t, v.Ins())
+	}
+
+	dg, err = makeDependencyGraph(snap, graphFilter{urnPrefix: "urn:pulumi:dev::proj::aws:s3/bucket:"})
+	require.NoError(t, err)
+	assert.Equal(t, []resource.URN{snap.Resources[1].URN}, urns(dg))
+
+	dg, err = makeDependencyGraph(snap, graphFilter{component: string(snap.Resources[0].URN)})
+	require.NoError(t, err)
+	assert.Equal(t, []resource.URN{snap.Resources[0].URN, snap.Resources[1].URN, snap.Resources[2].URN}, urns(dg))
+
+	_, err = makeDependencyGraph(snap, graphFilter{component: "urn:pulumi:dev::proj::my:index:Component::missing"})
+	assert.Error(t, err)
+}
+
+func TestDependencyGraphFormats(t *testing.T) {
+	t.Parallel()
+
+	snap := graphTestSnapshot()
+	dg, err := makeDependencyGraph(snap, graphFilter{component: string(snap.Resources[0].URN)})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, printDependencyGraphJSON(dg, &buf))
+	var out dependencyGraphJSON
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Len(t, out.Nodes, 3)
+	assert.Equal(t, snap.Resources[0].URN, out.Nodes[1].Parent)
+	assert.Contains(t, out.Edges, dependencyEdgeJSON{
+		From:       snap.Resources[2].URN,
+		To:         snap.Resources[1].URN,
+		Kind:       "dependency",
+		Properties: []string{"bucket"},
+	})
+	assert.Contains(t, out.Edges, dependencyEdgeJSON{
+		From: snap.Resources[1].URN,
+		To:   snap.Resources[0].URN,
+		Kind: "parent",
+	})
+
+	buf.Reset()
+	require.NoError(t, mermaidconv.Print(dg, &buf))
+	assert.Contains(t, buf.String(), "flowchart TD\n")
+	assert.Contains(t, buf.String(), `Resource0["urn:pulumi:dev::proj::my:index:Component::comp"]`)
+	assert.Contains(t, buf.String(), `-->|"bucket"|`)
+
+	buf.Reset()
+	require.NoError(t, graphmlconv.Print(dg, &buf))
+	assert.Contains(t, buf.String(), `<graph id="G" edgedefault="directed">`)
+	assert.Contains(t, buf.String(),
+		`<data key="label">urn:pulumi:dev::proj::aws:s3/bucket:Bucket::bucket</data>`)
+}
@@ -13,7 +13,9 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 )
@@ -27,6 +29,7 @@ func newStackHistoryCmd() *cobra.Command {
 	var pageSize int
 	var page int
 	var showFullDates bool
+	var urn string
 
 	cmd := &cobra.Command{
 		Use:        "history",
@@ -35,7 +38,10 @@ func newStackHistoryCmd() *cobra.Command {
 		Short:      "[PREVIEW] Display history for a stack",
 		Long: `Display history for a stack
 
-This command displays data about previous updates for a stack.`,
+This command displays data about previous updates for a stack.
+
+When --urn is passed, this command instead shows the history of a single resource: the updates
+that created, updated, replaced or deleted it, along with the properties each of them changed.`,
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
@@ -44,6 +50,20 @@ This command displays data about previous updates for a stack.`,
 			if err != nil {
 				return err
 			}
+			if urn != "" {
+				if showSecrets {
+					log3rdPartySecretsProviderDecryptionEvent(commandContext(), s, "", "pulumi stack history")
+				}
+				entries, err := s.GetResourceHistory(commandContext(), resource.URN(urn))
+				if err != nil {
+					return fmt.Errorf("getting resource history: %w", err)
+				}
+				if jsonOut {
+					return displayResourceHistoryJSON(entries, showSecrets)
+				}
+				return displayResourceHistoryConsole(entries, resource.URN(urn), opts, showSecrets, showFullDates)
+			}
+
 			b := s.Backend()
 			updates, err := b.GetHistory(commandContext(), s.Ref(), pageSize, page)
 			if err != nil {
@@ -84,6 +104,10 @@ This command displays data about previous updates for a stack.`,
 		&pageSize, "page-size", 10, "Used with 'page' to control number of results returned")
 	cmd.PersistentFlags().IntVar(
 		&page, "page", 1, "Used with 'page-size' to paginate results")
+	cmd.Flags().StringVar(
+		&urn, "urn", "", "Show the changes made to the resource with this URN by each update")
+
+	cmd.AddCommand(newStackHistoryPruneCmd(&stack))
 	return cmd
 }
 
@@ -221,3 +245,67 @@ func displayUpdatesConsole(updates []backend.UpdateInfo, page int, opts display.
 
 	return nil
 }
+
+// resourceHistoryEntryJSON is the shape of a single update's changes to a resource in the output of
+// `pulumi stack history --urn --json`.
+type resourceHistoryEntryJSON struct {
+	Version   int           `json:"version"`
+	Kind      string        `json:"kind"`
+	StartTime string        `json:"startTime"`
+	Message   string        `json:"message"`
+	Result    string        `json:"result,omitempty"`
+	Op        deploy.StepOp `json:"op"`
+	// Inputs and Outputs describe the changed properties of updated and replaced resources.
+	Inputs  map[string]stackDiffPropertyJSON `json:"inputs,omitempty"`
+	Outputs map[string]stackDiffPropertyJSON `json:"outputs,omitempty"`
+}
+
+func displayResourceHistoryJSON(entries []backend.ResourceHistoryEntry, showSecrets bool) error {
+	entriesJSON := make([]resourceHistoryEntryJSON, len(entries))
+	for idx, entry := range entries {
+		entriesJSON[idx] = resourceHistoryEntryJSON{
+			Version:   entry.Update.Version,
+			Kind:      string(entry.Update.Kind),
+			StartTime: time.Unix(entry.Update.StartTime, 0).UTC().Format(timeFormat),
+			Message:   entry.Update.Message,
+			Result:    string(entry.Update.Result),
+			Op:        entry.Diff.Op,
+			Inputs:    makeStackDiffPropertiesJSON(entry.Diff.Inputs, showSecrets),
+			Outputs:   makeStackDiffPropertiesJSON(entry.Diff.Outputs, showSecrets),
+		}
+	}
+	return printJSON(entriesJSON)
+}
+
+func displayResourceHistoryConsole(entries []backend.ResourceHistoryEntry, urn resource.URN, opts display.Options,
+	showSecrets, noHumanize bool) error {
+
+	if len(entries) == 0 {
+		fmt.Printf("No updates to the stack have changed resource '%s'\n", urn)
+		return nil
+	}
+
+	for _, entry := range entries {
+		update := entry.Update
+		fmt.Printf("Version: %d\n", update.Version)
+		fmt.Printf("UpdateKind: %v\n", update.Kind)
+		if update.Result == "succeeded" {
+			fmt.Print(opts.Color.Colorize(fmt.Sprintf("%sStatus: %v%s\n", colors.Green, update.Result, colors.Reset)))
+		} else {
+			fmt.Print(opts.Color.Colorize(fmt.Sprintf("%sStatus: %v%s\n", colors.Red, update.Result, colors.Reset)))
+		}
+		fmt.Printf("Message: %v\n", update.Message)
+
+		timeStart := time.Unix(update.StartTime, 0)
+		if noHumanize {
+			fmt.Printf("Updated %s\n", timeStart.String())
+		} else {
+			fmt.Printf("Updated %s\n", humanize.Time(timeStart))
+		}
+
+		printResourceDiff(entry.Diff, opts, showSecrets)
+		fmt.Println("")
+	}
+
+	return nil
+}
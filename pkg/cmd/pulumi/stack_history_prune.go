@@ -0,0 +1,117 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+func newStackHistoryPruneCmd(stack *string) *cobra.Command {
+	var keep int
+	var keepDays int
+	var compress bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Args:  cmdutil.NoArgs,
+		Short: "Remove old updates from the history of a stack in a self-managed backend",
+		Long: "Remove old updates from the history of a stack in a self-managed backend\n" +
+			"\n" +
+			"Self-managed backends save a history entry and copies of the stack's checkpoint on every\n" +
+			"update. This command removes the history entries and checkpoint backups that are not kept\n" +
+			"by --keep and --keep-days; the most recent update is always kept. Without either flag, the\n" +
+			"retention policy set by " + filestate.HistoryRetentionCountEnvVar + " and\n" +
+			filestate.HistoryRetentionDaysEnvVar + " is used. That policy is also applied after every update.\n" +
+			"\n" +
+			"Pass --compress to gzip the history checkpoints and backups that are kept. Set\n" +
+			filestate.CompressHistoryEnvVar + " to compress them as they are written.",
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if keep < 0 || keepDays < 0 {
+				return result.Error("--keep and --keep-days must not be negative")
+			}
+			retention := filestate.HistoryRetention{Count: keep, MaxAge: time.Duration(keepDays) * 24 * time.Hour}
+			if retention.IsEmpty() {
+				fromEnv, err := filestate.HistoryRetentionFromEnv()
+				if err != nil {
+					return result.FromError(err)
+				}
+				retention = fromEnv
+			}
+			if retention.IsEmpty() && !compress {
+				return result.Errorf("pass --keep, --keep-days or --compress, or set %s or %s",
+					filestate.HistoryRetentionCountEnvVar, filestate.HistoryRetentionDaysEnvVar)
+			}
+
+			s, err := requireStack(*stack, false /*offerNew */, opts, false /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+			lb, ok := s.Backend().(filestate.Backend)
+			if !ok {
+				return result.Errorf("the current backend (%s) does not support `pulumi stack history prune`; "+
+					"the Pulumi service manages the history of its stacks itself", s.Backend().Name())
+			}
+
+			if !retention.IsEmpty() {
+				prompt := fmt.Sprintf("This will permanently remove old updates from the history of the '%s' stack!",
+					s.Ref())
+				if !yes && !confirmPrompt(prompt, s.Ref().String(), opts) {
+					fmt.Println("confirmation declined")
+					return result.Bail()
+				}
+
+				updates, backups, err := lb.PruneHistory(commandContext(), s.Ref(), retention)
+				if err != nil {
+					return result.FromError(fmt.Errorf("pruning history: %w", err))
+				}
+				fmt.Printf("Removed %d updates and %d checkpoint backups from the history of stack %s\n",
+					updates, backups, s.Ref())
+			}
+
+			if compress {
+				compressed, err := lb.CompressHistory(commandContext(), s.Ref())
+				if err != nil {
+					return result.FromError(fmt.Errorf("compressing history: %w", err))
+				}
+				fmt.Printf("Compressed %d checkpoints in the history of stack %s\n", compressed, s.Ref())
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().IntVar(
+		&keep, "keep", 0, "The number of most recent updates to keep")
+	cmd.Flags().IntVar(
+		&keepDays, "keep-days", 0, "The number of days to keep updates for")
+	cmd.Flags().BoolVar(
+		&compress, "compress", false, "Compress the history checkpoints and backups that are kept")
+	cmd.Flags().BoolVarP(
+		&yes, "yes", "y", false, "Skip confirmation prompts, and proceed with pruning anyway")
+	return cmd
+}
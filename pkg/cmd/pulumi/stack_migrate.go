@@ -0,0 +1,496 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+	"github.com/pulumi/pulumi/pkg/v3/backend/httpstate"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newStackMigrateCmd() *cobra.Command {
+	var stackName string
+	var to string
+	var all bool
+	var secretsProvider string
+	var updateConfig bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Args:  cmdutil.NoArgs,
+		Short: "Copy stacks to another backend",
+		Long: "Copy stacks to another backend\n" +
+			"\n" +
+			"Copies the current stack, the stack given by --stack, or with --all every stack of the current\n" +
+			"project from the current backend to the backend at --to, which may be the Pulumi service or a\n" +
+			"self-managed backend such as `file://~` or `s3://my-bucket`. Each stack is copied with its\n" +
+			"tags, its configuration and its checkpoint. When the target is a self-managed backend, the\n" +
+			"stack's update history is copied as well.\n" +
+			"\n" +
+			"Secrets in the stack's configuration and checkpoint are re-encrypted for the target. Stacks that\n" +
+			"use a passphrase or cloud secrets provider keep it, and stacks that use the Pulumi service's\n" +
+			"secrets provider switch to the default provider of the target, unless --secrets-provider is given.\n" +
+			"\n" +
+			"A stack and its copy share the stack's configuration file. If the copy uses a different secrets\n" +
+			"provider, the current backend can no longer decrypt the file once it has been re-encrypted, so\n" +
+			"--update-config must be passed; the file is then backed up to Pulumi.<stack>.yaml.bak first.\n" +
+			"\n" +
+			"The copied checkpoint is read back from the target and compared with the original before the\n" +
+			"migration of a stack is reported as successful. The stacks are not removed from the current\n" +
+			"backend, and the current backend stays logged in; use `pulumi login` to switch to the target.",
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if to == "" {
+				return result.Error("missing required flag --to")
+			}
+			if all && stackName != "" {
+				return result.Error("only one of --stack and --all may be specified")
+			}
+			if secretsProvider != "" {
+				if err := validateSecretsProvider(secretsProvider); err != nil {
+					return result.FromError(err)
+				}
+			}
+
+			source, err := currentBackend(opts)
+			if err != nil {
+				return result.FromError(err)
+			}
+			target, err := migrationTargetBackend(to)
+			if err != nil {
+				return result.FromError(err)
+			}
+			if target.URL() == source.URL() {
+				return result.Errorf("the stacks are already stored in %s", to)
+			}
+
+			var stacks []backend.Stack
+			if all {
+				if stacks, err = currentProjectStacks(commandContext(), source); err != nil {
+					return result.FromError(err)
+				}
+				if len(stacks) == 0 {
+					return result.Errorf("the current project has no stacks in %s", source.URL())
+				}
+			} else {
+				s, err := requireStack(stackName, false /*offerNew*/, opts, false /*setCurrent*/)
+				if err != nil {
+					return result.FromError(err)
+				}
+				stacks = []backend.Stack{s}
+			}
+
+			var configFiles []string
+			for _, s := range stacks {
+				ps, err := loadProjectStack(s)
+				if err != nil {
+					return result.FromError(err)
+				}
+				path, reencrypt, err := configFileReencryption(s, ps, secretsProvider)
+				if err != nil {
+					return result.FromError(err)
+				}
+				if reencrypt {
+					configFiles = append(configFiles, path)
+				}
+			}
+			if len(configFiles) > 0 && !updateConfig {
+				return result.Errorf("the secrets in %s must be re-encrypted for a different secrets provider, after "+
+					"which %s can no longer decrypt them; pass --update-config to back the files up and re-encrypt "+
+					"them", strings.Join(configFiles, ", "), source.URL())
+			}
+
+			prompt := fmt.Sprintf("This will copy %d stack(s) from %s to %s!", len(stacks), source.URL(), target.URL())
+			if len(configFiles) > 0 {
+				prompt += fmt.Sprintf("\nThe secrets in %s will be re-encrypted for the target, and %s will no longer "+
+					"be able to decrypt them.", strings.Join(configFiles, ", "), source.URL())
+			}
+			if !yes && !confirmPrompt(prompt, target.URL(), opts) {
+				fmt.Println("confirmation declined")
+				return result.Bail()
+			}
+
+			for _, s := range stacks {
+				fmt.Printf("Migrating stack %s to %s\n", s.Ref(), target.URL())
+				migrated, err := migrateStack(commandContext(), s, target, secretsProvider, updateConfig)
+				if err != nil {
+					return result.FromError(fmt.Errorf("migrating stack '%s': %w", s.Ref(), err))
+				}
+				fmt.Printf("Migrated and verified stack %s as %s\n", s.Ref(), migrated.Ref())
+			}
+
+			fmt.Printf("Run `pulumi login %s` to use the migrated stacks\n", to)
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to migrate. Defaults to the current stack")
+	cmd.Flags().StringVar(
+		&to, "to", "", "The URL of the backend to copy the stacks to")
+	cmd.Flags().BoolVar(
+		&all, "all", false, "Migrate all of the stacks of the current project")
+	cmd.Flags().StringVar(
+		&secretsProvider, "secrets-provider", "", possibleSecretsProviderChoices)
+	cmd.Flags().BoolVar(
+		&updateConfig, "update-config", false,
+		"Back up and re-encrypt configuration files whose secrets provider changes, which the current backend "+
+			"can then no longer decrypt")
+	cmd.Flags().BoolVarP(
+		&yes, "yes", "y", false, "Skip confirmation prompts, and proceed with the migration anyway")
+	return cmd
+}
+
+// migrationTargetBackend returns the backend at the given URL without logging in to it, so that the current backend
+// stays logged in. The Pulumi service must have been logged in to before.
+func migrationTargetBackend(url string) (backend.Backend, error) {
+	if filestate.IsFileStateBackendURL(url) {
+		return filestate.New(cmdutil.Diag(), url)
+	}
+
+	b, err := httpstate.New(cmdutil.Diag(), url)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = b.CurrentUser(); err != nil {
+		return nil, fmt.Errorf("could not authenticate with %s; run `pulumi login %s` and then log back in to "+
+			"the current backend before migrating: %w", b.URL(), url, err)
+	}
+	return b, nil
+}
+
+// currentProjectStacks returns the stacks of the current project in the given backend.
+func currentProjectStacks(ctx context.Context, b backend.Backend) ([]backend.Stack, error) {
+	proj, _, err := readProject()
+	if err != nil {
+		return nil, err
+	}
+	projName := string(proj.Name)
+
+	var (
+		stacks      []backend.Stack
+		inContToken backend.ContinuationToken
+	)
+	for {
+		summaries, outContToken, err := b.ListStacks(ctx, backend.ListStacksFilter{Project: &projName}, inContToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range summaries {
+			s, err := b.GetStack(ctx, summary.Name())
+			if err != nil {
+				return nil, err
+			}
+			if s != nil {
+				stacks = append(stacks, s)
+			}
+		}
+
+		if outContToken == nil {
+			return stacks, nil
+		}
+		inContToken = outContToken
+	}
+}
+
+// migrateStack copies a stack to the target backend, along with its tags, configuration and, if the target supports
+// it, its history, and verifies the copied checkpoint. The stack's configuration file is re-encrypted for the copy;
+// if the copy uses a different secrets provider, updateConfig must be set, and the file is backed up first. If the
+// migration fails, the file is restored and the copy is removed.
+func migrateStack(ctx context.Context, s backend.Stack, target backend.Backend,
+	secretsProvider string, updateConfig bool) (_ backend.Stack, err error) {
+
+	ref, err := target.ParseStackReference(s.Ref().Name().String())
+	if err != nil {
+		return nil, err
+	}
+	existing, err := target.GetStack(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("stack '%s' already exists in %s", ref, target.URL())
+	}
+
+	// Read everything that needs the stack's current secrets provider before the configuration file is changed.
+	ps, err := loadProjectStack(s)
+	if err != nil {
+		return nil, err
+	}
+	path, reencrypt, err := configFileReencryption(s, ps, secretsProvider)
+	if err != nil {
+		return nil, err
+	}
+	if reencrypt && !updateConfig {
+		return nil, fmt.Errorf("the secrets in %s must be re-encrypted for a different secrets provider, after "+
+			"which %s can no longer decrypt them", path, s.Backend().URL())
+	}
+	currentConfig := ps.Config
+	var decrypter config.Decrypter = config.NewPanicCrypter()
+	if currentConfig.HasSecureValue() {
+		if decrypter, err = getStackDecrypter(s); err != nil {
+			return nil, err
+		}
+	}
+	deployment, err := s.ExportDeployment(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+	history, err := s.Backend().GetHistory(ctx, s.Ref(), 0 /*pageSize*/, 0 /*page*/)
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	restoreConfig, err := backupProjectStack(s)
+	if err != nil {
+		return nil, err
+	}
+	if reencrypt {
+		if err = backupConfigFile(path); err != nil {
+			return nil, fmt.Errorf("backing up configuration: %w", err)
+		}
+	}
+
+	migrated, err := target.CreateStack(ctx, ref, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating stack: %w", err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		contract.IgnoreError(restoreConfig())
+		_, rmErr := target.RemoveStack(ctx, migrated, true /*force*/)
+		contract.IgnoreError(rmErr)
+	}()
+
+	if target.SupportsTags() && len(s.Tags()) > 0 {
+		tags := make(map[apitype.StackTagName]string)
+		for k, v := range migrated.Tags() {
+			tags[k] = v
+		}
+		for k, v := range s.Tags() {
+			tags[k] = v
+		}
+		if err = backend.UpdateStackTags(ctx, migrated, tags); err != nil {
+			return nil, fmt.Errorf("copying tags: %w", err)
+		}
+	}
+
+	sm, err := newMigratedStackSecretsManager(migrated, ps, secretsProvider)
+	if err != nil {
+		return nil, err
+	}
+	if err = migrateOldConfigToNewSecretsProvider(migrated, currentConfig, decrypter, sm); err != nil {
+		return nil, fmt.Errorf("re-encrypting configuration: %w", err)
+	}
+
+	if err = migrateHistory(ctx, s, migrated, history, sm); err != nil {
+		return nil, fmt.Errorf("copying history: %w", err)
+	}
+
+	if snap != nil {
+		dep, err := serializeUntypedDeployment(snap, sm)
+		if err != nil {
+			return nil, err
+		}
+		if err = migrated.ImportDeployment(ctx, dep); err != nil {
+			return nil, fmt.Errorf("importing checkpoint: %w", err)
+		}
+	}
+
+	if err = verifyMigratedStack(ctx, migrated, snap); err != nil {
+		return nil, fmt.Errorf("verifying checkpoint: %w", err)
+	}
+	return migrated, nil
+}
+
+// configFileReencryption returns the path of a stack's configuration file, and whether its copy uses a different
+// secrets provider. The stack and its copy share the file, so once the file has been re-encrypted for the copy, the
+// stack can no longer decrypt it.
+func configFileReencryption(s backend.Stack, ps *workspace.ProjectStack,
+	secretsProvider string) (string, bool, error) {
+
+	path, err := getProjectStackPath(s)
+	if err != nil {
+		return "", false, err
+	}
+	return path, !keepsSecretsProvider(ps, secretsProvider), nil
+}
+
+// keepsSecretsProvider returns true if the copy of a stack keeps the stack's secrets provider, which passphrase and
+// cloud providers do unless another provider is given.
+func keepsSecretsProvider(ps *workspace.ProjectStack, secretsProvider string) bool {
+	return secretsProvider == "" &&
+		(ps.EncryptionSalt != "" || (ps.SecretsProvider != "" && ps.SecretsProvider != "default"))
+}
+
+// backupConfigFile copies a stack's configuration file, if it exists, to a file of the same name with a .bak
+// extension.
+func backupConfigFile(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path+".bak", contents, info.Mode().Perm())
+}
+
+// newMigratedStackSecretsManager configures the secrets provider of a migrated stack and returns its secrets manager.
+// Unless a provider is given, passphrase and cloud providers are kept, and stacks that used the Pulumi service's
+// provider switch to the default provider of the target backend.
+func newMigratedStackSecretsManager(s backend.Stack, ps *workspace.ProjectStack,
+	secretsProvider string) (secrets.Manager, error) {
+
+	if keepsSecretsProvider(ps, secretsProvider) {
+		return getStackSecretsManager(s)
+	}
+	if secretsProvider == "" {
+		secretsProvider = "default"
+	}
+
+	if hs, ok := s.(httpstate.Stack); ok && secretsProvider == "default" {
+		// createSecretsManager configures the service's provider for the current stack, which is not this one.
+		if _, err := newServiceSecretsManager(hs, s.Ref().Name(), stackConfigFile); err != nil {
+			return nil, err
+		}
+	} else if err := createSecretsManager(s.Backend(), s.Ref(), secretsProvider,
+		secretsProvider == "passphrase"); err != nil {
+		return nil, err
+	}
+	return getStackSecretsManager(s)
+}
+
+// migrateHistory copies the history of a stack, oldest update first, to a migrated stack in a self-managed backend.
+// The Pulumi service records the history of its stacks itself, so history cannot be copied to it.
+func migrateHistory(ctx context.Context, s, migrated backend.Stack, history []backend.UpdateInfo,
+	sm secrets.Manager) error {
+
+	if len(history) == 0 {
+		return nil
+	}
+	lb, ok := migrated.Backend().(filestate.Backend)
+	if !ok {
+		cmdutil.Diag().Warningf(diag.Message("", "the Pulumi service records the history of its stacks itself; "+
+			"the %d updates of stack '%s' were not copied"), len(history), s.Ref())
+		return nil
+	}
+	exporter, ok := s.Backend().(backend.SpecificDeploymentExporter)
+	if !ok {
+		return errors.New("the current backend cannot export the checkpoints of past updates")
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		update := history[i]
+		deployment, err := exporter.ExportDeploymentForVersion(ctx, s, strconv.Itoa(update.Version))
+		if err != nil {
+			return fmt.Errorf("exporting update %d: %w", update.Version, err)
+		}
+		snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+		if err != nil {
+			return fmt.Errorf("reading update %d: %w", update.Version, err)
+		}
+		dep, err := serializeUntypedDeployment(snap, sm)
+		if err != nil {
+			return fmt.Errorf("re-encrypting update %d: %w", update.Version, err)
+		}
+		if err = lb.ImportHistoryEntry(ctx, migrated.Ref(), update, dep); err != nil {
+			return fmt.Errorf("importing update %d: %w", update.Version, err)
+		}
+	}
+	return nil
+}
+
+// verifyMigratedStack reads the checkpoint of a migrated stack back and checks that it is valid and has the same
+// resources and pending operations as the snapshot it was copied from.
+func verifyMigratedStack(ctx context.Context, migrated backend.Stack, expected *deploy.Snapshot) error {
+	deployment, err := migrated.ExportDeployment(ctx)
+	if err != nil {
+		return err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return err
+	}
+	if snap != nil {
+		if err = snap.VerifyIntegrity(); err != nil {
+			return err
+		}
+	}
+
+	actual, err := comparableSnapshot(snap)
+	if err != nil {
+		return err
+	}
+	original, err := comparableSnapshot(expected)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(actual, original) {
+		return errors.New("the copied checkpoint does not match the original")
+	}
+	return nil
+}
+
+// comparableSnapshot returns the resources and pending operations of a snapshot as JSON, with their secrets in
+// plaintext so that snapshots encrypted by different secrets managers may be compared.
+func comparableSnapshot(snap *deploy.Snapshot) ([]byte, error) {
+	if snap == nil {
+		return json.Marshal(nil)
+	}
+	deployment, err := stack.SerializeDeployment(snap, b64.NewBase64SecretsManager(), true /*showSecrets*/)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal([]interface{}{deployment.Resources, deployment.PendingOperations})
+}
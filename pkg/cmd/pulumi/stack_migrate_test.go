@@ -0,0 +1,139 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+//nolint:paralleltest // changes the working directory and mutates environment variables
+func TestMigrateStack(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pulumi.yaml"), []byte("name: proj\nruntime: go\n"), 0600))
+	chdir(t, dir)
+	t.Setenv("PULUMI_CONFIG_PASSPHRASE", "password")
+
+	source, err := filestate.New(cmdutil.Diag(), "file://"+filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	ref, err := source.ParseStackReference("dev")
+	require.NoError(t, err)
+	s, err := source.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+	require.NoError(t, source.UpdateStackTags(ctx, s, map[apitype.StackTagName]string{"team": "infra"}))
+	s, err = source.GetStack(ctx, ref)
+	require.NoError(t, err)
+
+	// Give the stack a secret in its configuration and in its checkpoint, and an update in its history.
+	require.NoError(t, createSecretsManager(source, ref, "passphrase", false))
+	sm, err := getStackSecretsManager(s)
+	require.NoError(t, err)
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue("hunter2")
+	require.NoError(t, err)
+	ps, err := loadProjectStack(s)
+	require.NoError(t, err)
+	key := config.MustMakeKey("proj", "password")
+	require.NoError(t, ps.Config.Set(key, config.NewSecureValue(ciphertext), false))
+	require.NoError(t, saveProjectStack(s, ps))
+
+	snap := deploy.NewSnapshot(deploy.Manifest{}, sm, []*resource.State{{
+		URN:     resource.NewURN("dev", "proj", "", "a:b:c", "res"),
+		Type:    "a:b:c",
+		Custom:  true,
+		ID:      "id",
+		Outputs: resource.PropertyMap{"secret": resource.MakeSecret(resource.NewStringProperty("hunter2"))},
+	}}, nil)
+	dep, err := serializeUntypedDeployment(snap, sm)
+	require.NoError(t, err)
+	require.NoError(t, s.ImportDeployment(ctx, dep))
+	update := backend.UpdateInfo{Kind: apitype.UpdateUpdate, StartTime: time.Now().Unix(), Version: 1}
+	require.NoError(t, source.ImportHistoryEntry(ctx, ref, update, dep))
+
+	target, err := filestate.New(cmdutil.Diag(), "file://"+filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	migrated, err := migrateStack(ctx, s, target, "", false)
+	require.NoError(t, err)
+
+	migrated, err = target.GetStack(ctx, migrated.Ref())
+	require.NoError(t, err)
+	assert.Equal(t, "infra", migrated.Tags()["team"])
+
+	history, err := target.GetHistory(ctx, migrated.Ref(), 0, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 1, history[0].Version)
+
+	decrypter, err := getStackDecrypter(migrated)
+	require.NoError(t, err)
+	ps, err = loadProjectStack(migrated)
+	require.NoError(t, err)
+	value, err := ps.Config[key].Value(decrypter)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	dep, err = migrated.ExportDeployment(ctx)
+	require.NoError(t, err)
+	migratedSnap, err := stack.DeserializeUntypedDeployment(dep, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	require.Len(t, migratedSnap.Resources, 1)
+	assert.Equal(t, "hunter2", migratedSnap.Resources[0].Outputs["secret"].SecretValue().Element.StringValue())
+
+	// Stacks are never overwritten.
+	_, err = migrateStack(ctx, s, target, "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	// The stack's configuration file is only re-encrypted for a different secrets provider when that is asked for,
+	// after it has been backed up.
+	configFile := filepath.Join(dir, "Pulumi.dev.yaml")
+	original, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	const secretsProvider = "base64key://smGbjm71Nxd1Ig5FS0wj9SlbzAIrnolCz9bQQ6uAhl4="
+	other, err := filestate.New(cmdutil.Diag(), "file://"+filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	_, err = migrateStack(ctx, s, other, secretsProvider, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be re-encrypted")
+	current, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(original), string(current))
+
+	_, err = migrateStack(ctx, s, other, secretsProvider, true)
+	require.NoError(t, err)
+	backup, err := os.ReadFile(configFile + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, string(original), string(backup))
+	ps, err = loadProjectStack(s)
+	require.NoError(t, err)
+	assert.Equal(t, secretsProvider, ps.SecretsProvider)
+}
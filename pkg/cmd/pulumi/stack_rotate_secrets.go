@@ -0,0 +1,289 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+	"github.com/pulumi/pulumi/pkg/v3/backend/httpstate"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/external"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+// rotateSecretsMessage is the message of the history entries that record the rotation of a stack's secrets key.
+const rotateSecretsMessage = "Rotate the key that encrypts the stack's secrets"
+
+func newStackRotateSecretsCmd() *cobra.Command {
+	var stackName string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "rotate-secrets",
+		Args:  cmdutil.NoArgs,
+		Short: "Rotate the key that encrypts the secrets of a stack",
+		Long: "Rotate the key that encrypts the secrets of a stack\n" +
+			"\n" +
+			"Generates a new key for the stack's secrets provider, and re-encrypts the secrets in the stack's\n" +
+			"configuration file and checkpoint with it. The passphrase provider prompts for a new passphrase.\n" +
+			"Cloud secrets providers generate a new data key, which is encrypted by the key management service\n" +
+			"and stored in the configuration file. Programs used by `exec://` providers manage their own keys,\n" +
+			"so the secrets of their stacks are re-encrypted with the program's current key. The keys of the\n" +
+			"Pulumi service's secrets provider are managed by the service.\n" +
+			"\n" +
+			"The checkpoints kept in the history and backups of stacks in self-managed backends are re-encrypted\n" +
+			"too. The Pulumi service keeps the checkpoints of past updates itself, so the keys of its stacks can\n" +
+			"only be rotated before their first update.\n" +
+			"\n" +
+			"The rotation is recorded in the stack's update history.",
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(stackName, false /*offerNew*/, opts, false /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			prompt := fmt.Sprintf("This will re-encrypt the secrets of the '%s' stack with a new key!", s.Ref())
+			if !yes && !confirmPrompt(prompt, s.Ref().String(), opts) {
+				fmt.Println("confirmation declined")
+				return result.Bail()
+			}
+
+			if err = rotateStackSecrets(commandContext(), s); err != nil {
+				return result.FromError(fmt.Errorf("rotating secrets: %w", err))
+			}
+			fmt.Printf("Rotated the secrets key of stack %s\n", s.Ref())
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false, "Skip confirmation prompts, and proceed with the rotation anyway")
+
+	return cmd
+}
+
+// rotateStackSecrets generates a new key for the secrets provider of a stack, re-encrypts the stack's configuration,
+// checkpoint, history checkpoints and backups with it, and records the rotation in the stack's history. If the stack's
+// checkpoint cannot be re-encrypted, the stack's configuration file is restored.
+func rotateStackSecrets(ctx context.Context, s backend.Stack) (err error) {
+	start := time.Now()
+
+	ps, err := loadProjectStack(s)
+	if err != nil {
+		return err
+	}
+	secretsProvider := ps.SecretsProvider
+	isDefaultSecretsProvider := secretsProvider == "" || secretsProvider == "default"
+	if _, ok := s.(httpstate.Stack); ok && isDefaultSecretsProvider && ps.EncryptionSalt == "" {
+		return errors.New("the keys of the Pulumi service's secrets provider are managed by the service")
+	}
+
+	// Checkpoints that are kept in the Pulumi service's history cannot be re-encrypted, and would be left encrypted with
+	// a key that is no longer the stack's.
+	lb, isFilestate := s.Backend().(filestate.Backend)
+	if !isFilestate && !external.IsExternalSecretsProviderURL(secretsProvider) {
+		history, err := s.Backend().GetHistory(ctx, s.Ref(), 1, 1)
+		if err != nil {
+			return err
+		}
+		if len(history) > 0 {
+			return errors.New("the Pulumi service keeps the checkpoints of the stack's past updates, which cannot be " +
+				"re-encrypted with a new key; `pulumi stack export --version` would no longer be able to decrypt them")
+		}
+	}
+
+	// Read everything that needs the current key before it is replaced. The secrets managers that decrypt the stack's
+	// checkpoints are kept, so that its history can still be decrypted once the key has been rotated.
+	currentConfig := ps.Config
+	var decrypter config.Decrypter = config.NewPanicCrypter()
+	if currentConfig.HasSecureValue() {
+		if decrypter, err = getStackDecrypter(s); err != nil {
+			return err
+		}
+	}
+	deployment, err := s.ExportDeployment(ctx)
+	if err != nil {
+		return err
+	}
+	provider := &cachingSecretsProvider{}
+	snap, err := stack.DeserializeUntypedDeployment(deployment, provider)
+	if err != nil {
+		return checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+	if isFilestate {
+		if _, err = decryptHistoryCheckpoints(ctx, lb, s, provider); err != nil {
+			return err
+		}
+	}
+
+	restoreConfig, err := backupProjectStack(s)
+	if err != nil {
+		return err
+	}
+	rotated := false
+	defer func() {
+		if err != nil && !rotated {
+			contract.IgnoreError(restoreConfig())
+		}
+	}()
+
+	switch {
+	case external.IsExternalSecretsProviderURL(secretsProvider):
+		// The program manages its own keys.
+	case !isDefaultSecretsProvider && secretsProvider != passphrase.Type:
+		// Dropping the data key makes the cloud secrets manager generate a new one.
+		ps.EncryptedKey = ""
+		if err = saveProjectStack(s, ps); err != nil {
+			return err
+		}
+		if _, err = newCloudSecretsManager(s.Ref().Name(), stackConfigFile, secretsProvider); err != nil {
+			return err
+		}
+	default:
+		if _, err = newPassphraseSecretsManager(s.Ref().Name(), stackConfigFile,
+			true /* rotatePassphraseSecretsProvider */); err != nil {
+			return err
+		}
+	}
+
+	sm, err := getStackSecretsManager(s)
+	if err != nil {
+		return err
+	}
+	if err = migrateOldConfigToNewSecretsProvider(s, currentConfig, decrypter, sm); err != nil {
+		return fmt.Errorf("re-encrypting configuration: %w", err)
+	}
+
+	dep := deployment
+	if snap != nil {
+		if dep, err = serializeUntypedDeployment(snap, sm); err != nil {
+			return err
+		}
+		if err = s.ImportDeployment(ctx, dep); err != nil {
+			return fmt.Errorf("re-encrypting checkpoint: %w", err)
+		}
+	}
+	// The configuration and checkpoint are encrypted with the new key from here on, so the configuration file must
+	// not be restored.
+	rotated = true
+	if !isFilestate {
+		// The Pulumi service records imported checkpoints in the stack's history itself.
+		return nil
+	}
+
+	// Importing the checkpoint backed up the one it replaced, so the history is read again.
+	history, err := decryptHistoryCheckpoints(ctx, lb, s, provider)
+	if err != nil {
+		return err
+	}
+	reencrypted := make(map[string]*apitype.UntypedDeployment, len(history))
+	for file, snap := range history {
+		if reencrypted[file], err = serializeUntypedDeployment(snap, sm); err != nil {
+			return err
+		}
+	}
+	if err = lb.ReplaceHistoryCheckpoints(ctx, s.Ref(), reencrypted); err != nil {
+		return fmt.Errorf("re-encrypting history: %w", err)
+	}
+
+	// The checkpoint has been re-encrypted by now, so failing to record that only warrants a warning.
+	update := backend.UpdateInfo{
+		Kind:      apitype.StackImportUpdate,
+		StartTime: start.Unix(),
+		Message:   rotateSecretsMessage,
+		Result:    backend.SucceededResult,
+		EndTime:   time.Now().Unix(),
+	}
+	if histErr := lb.ImportHistoryEntry(ctx, s.Ref(), update, dep); histErr != nil {
+		cmdutil.Diag().Warningf(diag.Message("", "could not record the rotation in the history of stack '%s': %v"),
+			s.Ref(), histErr)
+	}
+	return nil
+}
+
+// decryptHistoryCheckpoints decrypts the history checkpoints and checkpoint backups of a stack in a self-managed
+// backend, keyed by the files that hold them.
+func decryptHistoryCheckpoints(ctx context.Context, lb filestate.Backend, s backend.Stack,
+	provider stack.SecretsProvider) (map[string]*deploy.Snapshot, error) {
+
+	deployments, err := lb.HistoryCheckpoints(ctx, s.Ref())
+	if err != nil {
+		return nil, err
+	}
+	snaps := make(map[string]*deploy.Snapshot, len(deployments))
+	for file, dep := range deployments {
+		snap, err := stack.DeserializeUntypedDeployment(dep, provider)
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt %s, which must be re-encrypted with the new key: %w", file, err)
+		}
+		if snap != nil {
+			snaps[file] = snap
+		}
+	}
+	return snaps, nil
+}
+
+// cachingSecretsProvider creates secrets managers with stack.DefaultSecretsProvider, and reuses them for checkpoints
+// whose secrets providers have the same state. The checkpoints that were encrypted with a stack's key can then still
+// be decrypted once the key has been rotated, even if its passphrase is no longer at hand.
+type cachingSecretsProvider struct {
+	managers map[string]secrets.Manager
+}
+
+func (p *cachingSecretsProvider) OfType(ty string, state json.RawMessage) (secrets.Manager, error) {
+	var key bytes.Buffer
+	key.WriteString(ty + ":")
+	if err := json.Compact(&key, state); err != nil {
+		return nil, err
+	}
+	if sm, ok := p.managers[key.String()]; ok {
+		return sm, nil
+	}
+
+	sm, err := stack.DefaultSecretsProvider.OfType(ty, state)
+	if err != nil {
+		return nil, err
+	}
+	if p.managers == nil {
+		p.managers = make(map[string]secrets.Manager)
+	}
+	p.managers[key.String()] = sm
+	return sm, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "gocloud.dev/secrets/localsecrets" // support for base64key://
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+//nolint:paralleltest // changes the working directory
+func TestRotateStackSecrets(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Pulumi.yaml"), []byte("name: proj\nruntime: go\n"), 0600))
+	chdir(t, dir)
+
+	b, err := filestate.New(cmdutil.Diag(), "file://"+filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	ref, err := b.ParseStackReference("dev")
+	require.NoError(t, err)
+	s, err := b.CreateStack(ctx, ref, nil)
+	require.NoError(t, err)
+
+	// Use a cloud secrets provider with a local key, and give the stack a secret in its configuration and checkpoint.
+	const secretsProvider = "base64key://smGbjm71Nxd1Ig5FS0wj9SlbzAIrnolCz9bQQ6uAhl4="
+	sm, err := newCloudSecretsManager("dev", "", secretsProvider)
+	require.NoError(t, err)
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue("hunter2")
+	require.NoError(t, err)
+	ps, err := loadProjectStack(s)
+	require.NoError(t, err)
+	key := config.MustMakeKey("proj", "password")
+	require.NoError(t, ps.Config.Set(key, config.NewSecureValue(ciphertext), false))
+	require.NoError(t, saveProjectStack(s, ps))
+	dataKey := ps.EncryptedKey
+
+	snap := deploy.NewSnapshot(deploy.Manifest{}, sm, []*resource.State{{
+		URN:     resource.NewURN("dev", "proj", "", "a:b:c", "res"),
+		Type:    "a:b:c",
+		Custom:  true,
+		ID:      "id",
+		Outputs: resource.PropertyMap{"secret": resource.MakeSecret(resource.NewStringProperty("hunter2"))},
+	}}, nil)
+	dep, err := serializeUntypedDeployment(snap, sm)
+	require.NoError(t, err)
+	require.NoError(t, s.ImportDeployment(ctx, dep))
+	lb := b.(filestate.Backend)
+	require.NoError(t, lb.ImportHistoryEntry(ctx, ref, backend.UpdateInfo{Kind: apitype.UpdateUpdate}, dep))
+
+	require.NoError(t, rotateStackSecrets(ctx, s))
+
+	// The stack has a new data key, which its configuration and checkpoint are encrypted with.
+	ps, err = loadProjectStack(s)
+	require.NoError(t, err)
+	assert.Equal(t, secretsProvider, ps.SecretsProvider)
+	assert.NotEqual(t, dataKey, ps.EncryptedKey)
+	newCiphertext, err := ps.Config[key].Value(config.NopDecrypter)
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, newCiphertext)
+
+	decrypter, err := getStackDecrypter(s)
+	require.NoError(t, err)
+	value, err := ps.Config[key].Value(decrypter)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	dep, err = s.ExportDeployment(ctx)
+	require.NoError(t, err)
+	rotated, err := stack.DeserializeUntypedDeployment(dep, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	require.Len(t, rotated.Resources, 1)
+	assert.Equal(t, "hunter2", rotated.Resources[0].Outputs["secret"].SecretValue().Element.StringValue())
+	originalState, err := json.Marshal(sm.State())
+	require.NoError(t, err)
+	rotatedState, err := json.Marshal(rotated.SecretsManager.State())
+	require.NoError(t, err)
+	assert.NotEqual(t, string(originalState), string(rotatedState))
+
+	// The rotation is recorded in the stack's history.
+	history, err := b.GetHistory(ctx, ref, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, apitype.StackImportUpdate, history[0].Kind)
+	assert.Equal(t, rotateSecretsMessage, history[0].Message)
+
+	// The checkpoints in the stack's history are encrypted with the new key too.
+	checkpoints, err := lb.HistoryCheckpoints(ctx, ref)
+	require.NoError(t, err)
+	assert.Len(t, checkpoints, 2)
+	for file, dep := range checkpoints {
+		var latest apitype.DeploymentV3
+		require.NoError(t, json.Unmarshal(dep.Deployment, &latest))
+		require.NotNil(t, latest.SecretsProviders, file)
+		assert.JSONEq(t, string(rotatedState), string(latest.SecretsProviders.State), file)
+	}
+	dep, err = b.(backend.SpecificDeploymentExporter).ExportDeploymentForVersion(ctx, s, "1")
+	require.NoError(t, err)
+	old, err := stack.DeserializeUntypedDeployment(dep, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	require.Len(t, old.Resources, 1)
+	assert.Equal(t, "hunter2", old.Resources[0].Outputs["secret"].SecretValue().Element.StringValue())
+}
@@ -15,14 +15,17 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 
+	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -48,6 +51,10 @@ troubleshooting a stack or when performing specific edits that otherwise would r
 	cmd.AddCommand(newStateDeleteCommand())
 	cmd.AddCommand(newStateUnprotectCommand())
 	cmd.AddCommand(newStateRenameCommand())
+	cmd.AddCommand(newStateMoveCommand())
+	cmd.AddCommand(newStateEditCommand())
+	cmd.AddCommand(newStateUpgradeCommand())
+	cmd.AddCommand(newStateDowngradeCommand())
 	return cmd
 }
 
@@ -141,16 +148,7 @@ func runTotalStateEdit(
 	}
 
 	if showPrompt && cmdutil.Interactive() {
-		confirm := false
-		surveycore.DisableColor = true
-		surveycore.QuestionIcon = ""
-		surveycore.SelectFocusIcon = opts.Color.Colorize(colors.BrightGreen + ">" + colors.Reset)
-		prompt := opts.Color.Colorize(colors.Yellow + "warning" + colors.Reset + ": ")
-		prompt += "This command will edit your stack's state directly. Confirm?"
-		cmdutil.EndKeypadTransmitMode()
-		if err = survey.AskOne(&survey.Confirm{
-			Message: prompt,
-		}, &confirm, nil); err != nil || !confirm {
+		if !confirmStateEdit(opts, "This command will edit your stack's state directly. Confirm?") {
 			fmt.Println("confirmation declined")
 			return result.Bail()
 		}
@@ -169,19 +167,42 @@ func runTotalStateEdit(
 		contract.AssertNoErrorf(snap.VerifyIntegrity(), "state edit produced an invalid snapshot")
 	}
 
-	sdep, err := stack.SerializeDeployment(snap, snap.SecretsManager, false /* showSecrets */)
+	return result.WrapIfNonNil(saveSnapshot(commandContext(), s, snap, snap.SecretsManager))
+}
+
+// confirmStateEdit prompts the user to confirm a direct edit of stack state, returning true if they accepted.
+func confirmStateEdit(opts display.Options, message string) bool {
+	confirm := false
+	surveycore.DisableColor = true
+	surveycore.QuestionIcon = ""
+	surveycore.SelectFocusIcon = opts.Color.Colorize(colors.BrightGreen + ">" + colors.Reset)
+	prompt := opts.Color.Colorize(colors.Yellow + "warning" + colors.Reset + ": ")
+	prompt += message
+	cmdutil.EndKeypadTransmitMode()
+	if err := survey.AskOne(&survey.Confirm{
+		Message: prompt,
+	}, &confirm, nil); err != nil {
+		return false
+	}
+	return confirm
+}
+
+// saveSnapshot serializes the given snapshot using the given secrets manager and imports it into the given stack,
+// replacing the stack's current state.
+func saveSnapshot(ctx context.Context, s backend.Stack, snap *deploy.Snapshot, sm secrets.Manager) error {
+	sdep, err := stack.SerializeDeployment(snap, sm, false /* showSecrets */)
 	if err != nil {
-		return result.FromError(fmt.Errorf("serializing deployment: %w", err))
+		return fmt.Errorf("serializing deployment: %w", err)
 	}
 
 	// Once we've mutated the snapshot, import it back into the backend so that it can be persisted.
 	bytes, err := json.Marshal(sdep)
 	if err != nil {
-		return result.FromError(err)
+		return err
 	}
 	dep := apitype.UntypedDeployment{
 		Version:    apitype.DeploymentSchemaVersionCurrent,
 		Deployment: bytes,
 	}
-	return result.WrapIfNonNil(s.ImportDeployment(commandContext(), &dep))
+	return s.ImportDeployment(ctx, &dep)
 }
@@ -0,0 +1,198 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+func newStateEditCommand() *cobra.Command {
+	var stackName string
+
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the current stack's state in your EDITOR",
+		Long: `Edit the current stack's state in your EDITOR
+
+This command opens the stack's deployment in the editor named by the VISUAL or EDITOR environment variables.
+Secret values are shown in plaintext so that they can be edited. Once the editor exits, the edited deployment
+is checked for problems such as resources that appear before their dependencies, parents or providers. If the
+deployment is valid, a summary of the changes is shown and, once confirmed, the deployment replaces the stack's
+current state. If it isn't, the editor can be reopened to fix the problems.`,
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if !cmdutil.Interactive() {
+				return result.Error("pulumi state edit must be run interactively")
+			}
+
+			s, err := requireStack(stackName, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+			snap, err := s.Snapshot(ctx)
+			if err != nil {
+				return result.FromError(err)
+			}
+			if snap == nil {
+				return result.Errorf("stack '%s' has no state to edit", s.Ref())
+			}
+
+			dep, err := stack.SerializeDeployment(snap, snap.SecretsManager, true /* showSecrets */)
+			if err != nil {
+				return result.FromError(fmt.Errorf("serializing deployment: %w", err))
+			}
+			original, err := json.MarshalIndent(dep, "", "    ")
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			f, err := ioutil.TempFile("", "pulumi-state-*.json")
+			if err != nil {
+				return result.FromError(err)
+			}
+			defer os.Remove(f.Name())
+			_, err = f.Write(original)
+			contract.IgnoreClose(f)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			var edited *deploy.Snapshot
+			for {
+				if err = openInEditor(f.Name()); err != nil {
+					return result.FromError(err)
+				}
+				contents, err := ioutil.ReadFile(f.Name())
+				if err != nil {
+					return result.FromError(err)
+				}
+				if bytes.Equal(contents, original) {
+					fmt.Println("No changes were made to the stack's state.")
+					return nil
+				}
+
+				edited, err = readEditedDeployment(contents, s.Ref().Name())
+				if err == nil {
+					break
+				}
+				cmdutil.Diag().Errorf(diag.Message("", "the edited state is invalid: %v"), err)
+				if !confirmStateEdit(opts, "Reopen the editor to fix the problem?") {
+					fmt.Println("The stack's state was not changed.")
+					return result.Bail()
+				}
+			}
+
+			fmt.Println(summarizeStateEdit(deploy.DiffSnapshots(snap, edited)))
+			if !confirmStateEdit(opts, "This command will edit your stack's state directly. Confirm?") {
+				fmt.Println("confirmation declined")
+				return result.Bail()
+			}
+
+			return result.WrapIfNonNil(saveSnapshot(ctx, s, edited, snap.SecretsManager))
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	return cmd
+}
+
+// readEditedDeployment parses and validates a deployment edited by the user.
+func readEditedDeployment(contents []byte, stackName tokens.Name) (*deploy.Snapshot, error) {
+	var dep apitype.DeploymentV3
+	if err := json.Unmarshal(contents, &dep); err != nil {
+		return nil, err
+	}
+	snap, err := stack.DeserializeDeploymentV3(dep, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range snap.Resources {
+		if res.URN.Stack() != stackName.Q() {
+			return nil, fmt.Errorf("resource '%s' is from a different stack (%s != %s)",
+				res.URN, res.URN.Stack(), stackName)
+		}
+	}
+	if err = snap.VerifyIntegrity(); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// summarizeStateEdit describes the resources changed by a state edit.
+func summarizeStateEdit(diffs []deploy.ResourceDiff) string {
+	if len(diffs) == 0 {
+		return "The edit does not change any resources."
+	}
+
+	var b strings.Builder
+	b.WriteString("The edit changes the following resources:\n")
+	for _, d := range diffs {
+		fmt.Fprintf(&b, "  %s %s\n", d.Op.RawPrefix(), d.URN)
+	}
+	return b.String()
+}
+
+// openInEditor opens the given file in the user's editor and waits for the editor to exit.
+func openInEditor(file string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// Editors are often configured with arguments, e.g. `code --wait`.
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return errors.New("no editor configured; set the EDITOR environment variable")
+	}
+	cmd := exec.Command(args[0], append(args[1:], file)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestReadEditedDeployment(t *testing.T) {
+	t.Parallel()
+
+	const valid = `{
+    "manifest": {"time": "2022-01-01T00:00:00Z", "magic": "", "version": ""},
+    "secrets_providers": {"type": "b64"},
+    "resources": [
+        {"urn": "urn:pulumi:dev::proj::pulumi:pulumi:Stack::proj-dev", "custom": false, "type": "pulumi:pulumi:Stack"},
+        {
+            "urn": "urn:pulumi:dev::proj::pulumi:pulumi:Stack$pkg:index:typ::res",
+            "custom": false,
+            "type": "pkg:index:typ",
+            "parent": "urn:pulumi:dev::proj::pulumi:pulumi:Stack::proj-dev",
+            "inputs": {
+                "password": {"4dabf18193072939515e22adb298388d": "1b47061264138c4ac30d75fd1eb44270", "plaintext": "\"hunter2\""}
+            }
+        }
+    ]
+}`
+	snap, err := readEditedDeployment([]byte(valid), "dev")
+	if assert.NoError(t, err) && assert.Len(t, snap.Resources, 2) {
+		password := snap.Resources[1].Inputs["password"]
+		assert.True(t, password.IsSecret())
+		assert.Equal(t, resource.NewStringProperty("hunter2"), password.SecretValue().Element)
+	}
+
+	_, err = readEditedDeployment([]byte(valid), "prod")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "is from a different stack")
+	}
+
+	// Parents must precede their children.
+	const misordered = `{
+    "manifest": {"time": "2022-01-01T00:00:00Z", "magic": "", "version": ""},
+    "resources": [
+        {
+            "urn": "urn:pulumi:dev::proj::pulumi:pulumi:Stack$pkg:index:typ::res",
+            "custom": false,
+            "type": "pkg:index:typ",
+            "parent": "urn:pulumi:dev::proj::pulumi:pulumi:Stack::proj-dev"
+        },
+        {"urn": "urn:pulumi:dev::proj::pulumi:pulumi:Stack::proj-dev", "custom": false, "type": "pulumi:pulumi:Stack"}
+    ]
+}`
+	_, err = readEditedDeployment([]byte(misordered), "dev")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "comes after it")
+	}
+
+	_, err = readEditedDeployment([]byte(`{"resources": [`), "dev")
+	assert.Error(t, err)
+}
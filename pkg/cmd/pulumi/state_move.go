@@ -0,0 +1,168 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/pkg/v3/version"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+func newStateMoveCommand() *cobra.Command {
+	var source string
+	var dest string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "move <resource URN>...",
+		Short: "Moves resources from one stack to another",
+		Long: `Moves resources from one stack to another
+
+This command moves one or more resources from a source stack into a destination stack. Resources are specified
+by their Pulumi URNs (use ` + "`pulumi stack --show-urns`" + ` to get them).
+
+Every resource that depends on or is parented to a moved resource is moved along with it. Resources can't be moved
+if they depend on other resources that would be left behind in the source stack. The providers used by the moved
+resources are copied into the destination stack. URNs are rewritten to refer to the destination stack and project,
+and secret values are re-encrypted with the destination stack's secrets provider.
+
+Make sure that URNs are single-quoted to avoid having characters unexpectedly interpreted by the shell.
+
+Example:
+pulumi state move --source dev --dest networking 'urn:pulumi:dev::demo::aws:ec2/vpc:Vpc::main'
+`,
+		Args: cmdutil.MinimumNArgs(1),
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			ctx := commandContext()
+
+			if dest == "" {
+				return result.Error("a destination stack must be specified with --dest")
+			}
+
+			sourceStack, err := requireStack(source, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+			destStack, err := requireStack(dest, false, opts, false /*setCurrent*/)
+			if err != nil {
+				return result.FromError(err)
+			}
+			if sourceStack.Ref().String() == destStack.Ref().String() {
+				return result.Error("the source and destination stacks must be different")
+			}
+
+			sourceSnap, err := sourceStack.Snapshot(ctx)
+			if err != nil {
+				return result.FromError(err)
+			}
+			if sourceSnap == nil {
+				return result.Errorf("stack '%s' has no resources", sourceStack.Ref())
+			}
+
+			// The destination stack may never have been deployed to, in which case we start from an empty snapshot.
+			destSnap, err := destStack.Snapshot(ctx)
+			if err != nil {
+				return result.FromError(err)
+			}
+			if destSnap == nil {
+				destSnap = deploy.NewSnapshot(deploy.Manifest{
+					Time:    time.Now(),
+					Version: version.Version,
+				}, nil, nil, nil)
+				destSnap.Manifest.Magic = destSnap.Manifest.NewMagic()
+			}
+			if destSnap.SecretsManager == nil {
+				sm, err := getStackSecretsManager(destStack)
+				if err != nil {
+					return result.FromError(fmt.Errorf("getting secrets manager for stack '%s': %w", destStack.Ref(), err))
+				}
+				destSnap.SecretsManager = sm
+			}
+
+			urns := make([]resource.URN, len(args))
+			for i, arg := range args {
+				urns[i] = resource.URN(arg)
+			}
+
+			// Moved resources keep their project unless the destination stack already belongs to a different one.
+			destProject := urns[0].Project()
+			if len(destSnap.Resources) != 0 {
+				destProject = destSnap.Resources[0].URN.Project()
+			}
+
+			moved, err := edit.MoveResources(sourceSnap, destSnap, urns, tokens.QName(destStack.Ref().Name()), destProject)
+			if err != nil {
+				var depErr edit.ResourceHasDanglingDependenciesError
+				if errors.As(err, &depErr) {
+					message := fmt.Sprintf("The resource %q can't be moved because it depends on the following "+
+						"resources, which would be left behind:\n", depErr.Resource.URN)
+					for _, dep := range depErr.Dependencies {
+						message += fmt.Sprintf(" * %-15q (%s)\n", dep.Name(), dep)
+					}
+					message += "\nInclude those resources in the move as well."
+					return result.Error(message)
+				}
+				return result.FromError(err)
+			}
+
+			fmt.Printf("The following resources will be moved from %s to %s:\n", sourceStack.Ref(), destStack.Ref())
+			for _, res := range moved {
+				fmt.Printf("  - %s\n", res.URN)
+			}
+			fmt.Println()
+
+			if !yes && cmdutil.Interactive() {
+				if !confirmStateEdit(opts, "This command will edit the state of both stacks directly. Confirm?") {
+					fmt.Println("confirmation declined")
+					return result.Bail()
+				}
+			}
+
+			// Write the destination first, so that a failure leaves the resources in the source stack rather than in
+			// neither stack.
+			if err := saveSnapshot(ctx, destStack, destSnap, destSnap.SecretsManager); err != nil {
+				return result.FromError(fmt.Errorf("saving stack '%s': %w", destStack.Ref(), err))
+			}
+			if err := saveSnapshot(ctx, sourceStack, sourceSnap, sourceSnap.SecretsManager); err != nil {
+				return result.FromError(fmt.Errorf("saving stack '%s': %w", sourceStack.Ref(), err))
+			}
+
+			fmt.Printf("Moved %d resources successfully\n", len(moved))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&source, "source", "",
+		"The name of the stack to move resources from. Defaults to the current stack")
+	cmd.Flags().StringVar(&dest, "dest", "",
+		"The name of the stack to move resources to")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	return cmd
+}
@@ -0,0 +1,115 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+func newStateUpgradeCommand() *cobra.Command {
+	var projects map[string]string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Migrate a self-managed backend to the project-scoped stack layout",
+		Long: "Migrate a self-managed backend to the project-scoped stack layout\n" +
+			"\n" +
+			"In the project-scoped layout, stacks are stored by project and are referred to as\n" +
+			"organization/<project>/<stack>, so stacks of different projects may share a name.\n" +
+			"Older versions of the Pulumi CLI that share the backend no longer see its stacks once\n" +
+			"it is upgraded, so backends are only upgraded automatically when they are opened if\n" +
+			"PULUMI_FILESTATE_UPGRADE_LAYOUT is set.\n" +
+			"\n" +
+			"The project of each stack is taken from the resources in its checkpoint. The projects\n" +
+			"of stacks without resources must be given with --project <stack>=<project>.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			stackProjects := make(map[tokens.Name]tokens.Name, len(projects))
+			for stack, project := range projects {
+				if !tokens.IsName(stack) || !tokens.IsName(project) {
+					return result.Errorf("invalid --project %s=%s; expected <stack>=<project>", stack, project)
+				}
+				stackProjects[tokens.Name(stack)] = tokens.Name(project)
+			}
+
+			lb, err := currentFilestateBackend("state upgrade")
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			prompt := fmt.Sprintf("This will move the stacks of the backend at %s into the directories of their "+
+				"projects!\nOlder versions of the Pulumi CLI will no longer see them.", lb.URL())
+			if !yes && !confirmPrompt(prompt, "upgrade", opts) {
+				fmt.Println("confirmation declined")
+				return result.Bail()
+			}
+
+			moves, err := lb.Upgrade(commandContext(), stackProjects)
+			if err != nil {
+				return result.FromError(err)
+			}
+			for _, move := range moves {
+				fmt.Printf("Moved stack %s to organization/%s/%s\n", move.Stack, move.Project, move.Stack)
+			}
+			fmt.Printf("The backend at %s now uses the project-scoped layout\n", lb.URL())
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringToStringVar(
+		&projects, "project", nil, "The project of a stack without resources, as <stack>=<project>")
+	cmd.Flags().BoolVarP(
+		&yes, "yes", "y", false, "Skip confirmation prompts, and proceed with the upgrade anyway")
+
+	return cmd
+}
+
+func newStateDowngradeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "downgrade",
+		Short: "Migrate a self-managed backend back to the legacy stack layout",
+		Long: "Migrate a self-managed backend back to the legacy stack layout\n" +
+			"\n" +
+			"In the legacy layout, stacks are identified by name alone, as they are by older\n" +
+			"versions of the Pulumi CLI. The downgrade fails if stacks of different projects share\n" +
+			"a name. A downgraded backend is not upgraded automatically again; use\n" +
+			"`pulumi state upgrade` to move it back to the project-scoped layout.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			lb, err := currentFilestateBackend("state downgrade")
+			if err != nil {
+				return result.FromError(err)
+			}
+			if err = lb.Downgrade(commandContext()); err != nil {
+				return result.FromError(err)
+			}
+			fmt.Printf("The backend at %s now uses the legacy layout\n", lb.URL())
+			return nil
+		}),
+	}
+}
@@ -62,6 +62,10 @@ func newUpCmd() *cobra.Command {
 	var diffDisplay bool
 	var eventLogPath string
 	var parallel int
+	var parallelLimits []string
+	var retries int
+	var continueOnError bool
+	var auditSecrets bool
 	var refresh string
 	var showConfig bool
 	var showReplacementSteps bool
@@ -109,6 +113,9 @@ func newUpCmd() *cobra.Command {
 		if err != nil {
 			return result.FromError(fmt.Errorf("getting stack configuration: %w", err))
 		}
+		if err = applyProjectConfigSchema(proj, &cfg); err != nil {
+			return result.FromError(err)
+		}
 
 		targetURNs := []resource.URN{}
 		snap, err := s.Snapshot(commandContext())
@@ -144,9 +151,17 @@ func newUpCmd() *cobra.Command {
 		if err != nil {
 			return result.FromError(err)
 		}
+		limits, err := getParallelismLimits(proj, parallelLimits)
+		if err != nil {
+			return result.FromError(err)
+		}
 		opts.Engine = engine.UpdateOptions{
 			LocalPolicyPacks:          engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
 			Parallel:                  parallel,
+			ParallelismLimits:         limits,
+			Retries:                   retries,
+			ContinueOnError:           continueOnError,
+			AuditSecrets:              auditSecrets,
 			Debug:                     debug,
 			Refresh:                   refreshOption,
 			RefreshTargets:            targetURNs,
@@ -157,7 +172,7 @@ func newUpCmd() *cobra.Command {
 			DisableOutputValues:       disableOutputValues(),
 			UpdateTargets:             targetURNs,
 			TargetDependents:          targetDependents,
-			ExperimentalPlans:         hasExperimentalCommands() || planFilePath != "",
+			GeneratePlan:              hasExperimentalCommands() || planFilePath != "",
 		}
 
 		if planFilePath != "" {
@@ -325,18 +340,29 @@ func newUpCmd() *cobra.Command {
 		if err != nil {
 			return result.FromError(fmt.Errorf("getting stack configuration: %w", err))
 		}
+		if err = applyProjectConfigSchema(proj, &cfg); err != nil {
+			return result.FromError(err)
+		}
 
 		refreshOption, err := getRefreshOption(proj, refresh)
 		if err != nil {
 			return result.FromError(err)
 		}
+		limits, err := getParallelismLimits(proj, parallelLimits)
+		if err != nil {
+			return result.FromError(err)
+		}
 
 		opts.Engine = engine.UpdateOptions{
 			LocalPolicyPacks:  engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
 			Parallel:          parallel,
+			ParallelismLimits: limits,
+			Retries:           retries,
+			ContinueOnError:   continueOnError,
+			AuditSecrets:      auditSecrets,
 			Debug:             debug,
 			Refresh:           refreshOption,
-			ExperimentalPlans: hasExperimentalCommands() || planFilePath != "",
+			GeneratePlan:      hasExperimentalCommands() || planFilePath != "",
 		}
 
 		// TODO for the URL case:
@@ -390,7 +416,7 @@ func newUpCmd() *cobra.Command {
 				return result.FromError(errors.New("--yes must be passed in to proceed when running in non-interactive mode"))
 			}
 
-			opts, err := updateFlagsToOptions(interactive, skipPreview, yes)
+			opts, err := updateFlagsToOptions(interactive, skipPreview, yes, false /*previewOnly*/)
 			if err != nil {
 				return result.FromError(err)
 			}
@@ -465,7 +491,7 @@ func newUpCmd() *cobra.Command {
 		"Config keys contain a path to a property in a map or list to set")
 	cmd.PersistentFlags().StringVar(
 		&secretsProvider, "secrets-provider", "default", "The type of the provider that should be used to encrypt and "+
-			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault). Only"+
+			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault, exec). Only"+
 			"used when creating a new stack from an existing template")
 
 	cmd.PersistentFlags().StringVar(
@@ -509,6 +535,22 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism). Defaults to unbounded.")
+	cmd.PersistentFlags().StringArrayVar(
+		&parallelLimits, "parallel-limit", []string{},
+		"Limit how many resource operations of a package or resource type run in parallel at once, as "+
+			"`type=limit` (e.g. aws=10 or aws:route53/record:Record=2). May be specified multiple times")
+	cmd.PersistentFlags().IntVar(
+		&retries, "retries", 0,
+		"Retry resource operations that fail with an error the provider reports as transient up to N times, "+
+			"waiting longer between each attempt")
+	cmd.PersistentFlags().BoolVar(
+		&continueOnError, "continue-on-error", false,
+		"Continue updating resources that do not depend on a failed resource. Resources that depend on it are "+
+			"skipped, and no resources are deleted")
+	cmd.PersistentFlags().BoolVar(
+		&auditSecrets, "audit-secrets", false,
+		"Warn about plaintext values in the state of resources that look like secrets, such as credentials, "+
+			"high-entropy strings and the values of secret configuration keys")
 	cmd.PersistentFlags().StringVarP(
 		&refresh, "refresh", "r", "",
 		"Refresh the state of the stack's resources before this update")
@@ -543,12 +585,9 @@ func newUpCmd() *cobra.Command {
 
 	cmd.PersistentFlags().StringVar(
 		&planFilePath, "plan", "",
-		"[EXPERIMENTAL] Path to a plan file to use for the update. The update will not "+
-			"perform operations that exceed its plan (e.g. replacements instead of updates, or updates instead"+
-			"of sames).")
-	if !hasExperimentalCommands() {
-		contract.AssertNoError(cmd.PersistentFlags().MarkHidden("plan"))
-	}
+		"Path to a plan file to use for the update. The update will not "+
+			"perform operations that exceed its plan (e.g. replacements instead of updates, or updates instead "+
+			"of sames). Plan files are written by `pulumi preview --save-plan`")
 
 	if hasDebugCommands() {
 		cmd.PersistentFlags().StringVar(
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"os/exec"
@@ -44,6 +45,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/external"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
 	"github.com/pulumi/pulumi/pkg/v3/util/cancel"
 	"github.com/pulumi/pulumi/pkg/v3/util/tracing"
@@ -114,6 +116,9 @@ func currentBackend(opts display.Options) (backend.Backend, error) {
 		return backendInstance, nil
 	}
 
+	// Checkpoints are decrypted as they are read, which may run the stack's secrets provider program.
+	trustConfiguredSecretsProviders()
+
 	url, err := workspace.GetCurrentCloudURL()
 	if err != nil {
 		return nil, fmt.Errorf("could not get cloud url: %w", err)
@@ -125,6 +130,20 @@ func currentBackend(opts display.Options) (backend.Backend, error) {
 	return httpstate.Login(commandContext(), cmdutil.Diag(), url, opts)
 }
 
+// currentFilestateBackend returns the current backend for the given command, which only self-managed backends
+// support.
+func currentFilestateBackend(command string) (filestate.Backend, error) {
+	b, err := currentBackend(display.Options{Color: cmdutil.GetGlobalColorization()})
+	if err != nil {
+		return nil, err
+	}
+	lb, ok := b.(filestate.Backend)
+	if !ok {
+		return nil, fmt.Errorf("the current backend (%s) does not support `pulumi %s`", b.Name(), command)
+	}
+	return lb, nil
+}
+
 // This is used to control the contents of the tracing header.
 var tracingHeader = os.Getenv("PULUMI_TRACING_HEADER")
 
@@ -179,6 +198,10 @@ func createSecretsManager(b backend.Backend, stackRef backend.StackReference, se
 			rotatePassphraseSecretsProvider); pharseErr != nil {
 			return pharseErr
 		}
+	} else if external.IsExternalSecretsProviderURL(secretsProvider) {
+		if _, secretsErr := newExternalSecretsManager(stackRef.Name(), stackConfigFile, secretsProvider); secretsErr != nil {
+			return secretsErr
+		}
 	} else if !isDefaultSecretsProvider {
 		// All other non-default secrets providers are handled by the cloud secrets provider which
 		// uses a URL schema to identify the provider
@@ -822,8 +845,12 @@ func printJSON(v interface{}) error {
 
 // updateFlagsToOptions ensures that the given update flags represent a valid combination.  If so, an UpdateOptions
 // is returned with a nil-error; otherwise, the non-nil error contains information about why the combination is invalid.
-func updateFlagsToOptions(interactive, skipPreview, yes bool) (backend.UpdateOptions, error) {
-	if !interactive && !yes {
+func updateFlagsToOptions(interactive, skipPreview, yes, previewOnly bool) (backend.UpdateOptions, error) {
+	if skipPreview && previewOnly {
+		return backend.UpdateOptions{},
+			errors.New("--skip-preview and --preview-only cannot be used together")
+	}
+	if !interactive && !yes && !previewOnly {
 		return backend.UpdateOptions{},
 			errors.New("--yes must be passed in non-interactive mode")
 	}
@@ -831,6 +858,7 @@ func updateFlagsToOptions(interactive, skipPreview, yes bool) (backend.UpdateOpt
 	return backend.UpdateOptions{
 		AutoApprove: yes,
 		SkipPreview: skipPreview,
+		PreviewOnly: previewOnly,
 	}, nil
 }
 
@@ -869,6 +897,35 @@ func getRefreshOption(proj *workspace.Project, refresh string) (bool, error) {
 	return false, nil
 }
 
+// getParallelismLimits returns the per-package and per-resource-type parallelism limits set in the project's options,
+// overridden by any passed on the command line as `<package or type>=<limit>`.
+func getParallelismLimits(proj *workspace.Project, flags []string) (map[string]int, error) {
+	limits := make(map[string]int)
+	if proj.Options != nil {
+		for key, limit := range proj.Options.Parallelism {
+			limits[key] = limit
+		}
+	}
+
+	for _, flag := range flags {
+		// Resource types contain colons, so split on the last equals sign.
+		idx := strings.LastIndex(flag, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid --parallel-limit %q: expected <package or type>=<limit>", flag)
+		}
+		limit, err := strconv.Atoi(flag[idx+1:])
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid --parallel-limit %q: the limit must be a positive integer", flag)
+		}
+		limits[flag[:idx]] = limit
+	}
+
+	if len(limits) == 0 {
+		return nil, nil
+	}
+	return limits, nil
+}
+
 func writePlan(path string, plan *deploy.Plan, enc config.Encrypter, showSecrets bool) error {
 	f, err := os.Create(path)
 	if err != nil {
@@ -876,7 +933,7 @@ func writePlan(path string, plan *deploy.Plan, enc config.Encrypter, showSecrets
 	}
 	defer contract.IgnoreClose(f)
 
-	deploymentPlan, err := stack.SerializePlan(plan, enc, showSecrets)
+	deploymentPlan, err := stack.SerializeUntypedPlan(plan, enc, showSecrets)
 	if err != nil {
 		return err
 	}
@@ -886,17 +943,27 @@ func writePlan(path string, plan *deploy.Plan, enc config.Encrypter, showSecrets
 }
 
 func readPlan(path string, dec config.Decrypter, enc config.Encrypter) (*deploy.Plan, error) {
-	f, err := os.Open(path)
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer contract.IgnoreClose(f)
 
-	var deploymentPlan apitype.DeploymentPlanV1
-	if err := json.NewDecoder(f).Decode(&deploymentPlan); err != nil {
+	var deploymentPlan apitype.VersionedDeploymentPlan
+	if err := json.Unmarshal(data, &deploymentPlan); err != nil {
 		return nil, err
 	}
-	return stack.DeserializePlan(deploymentPlan, dec, enc)
+
+	// Plan files written before plans were versioned hold an unwrapped version 1 plan.
+	if deploymentPlan.Version == 0 && deploymentPlan.Plan == nil {
+		deploymentPlan = apitype.VersionedDeploymentPlan{Version: 1, Plan: data}
+	}
+
+	plan, err := stack.DeserializeUntypedPlan(&deploymentPlan, dec, enc)
+	if err == stack.ErrPlanSchemaVersionTooNew {
+		return nil, fmt.Errorf("the plan file '%s' was written by a newer version of the Pulumi CLI; "+
+			"please update the CLI to use it", path)
+	}
+	return plan, err
 }
 
 func buildStackName(stackName string) (string, error) {
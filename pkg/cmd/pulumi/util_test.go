@@ -344,3 +344,32 @@ func TestGetRefreshOption(t *testing.T) {
 		})
 	}
 }
+
+func TestGetParallelismLimits(t *testing.T) {
+	t.Parallel()
+
+	proj := &workspace.Project{
+		Name: "limits",
+		Options: &workspace.ProjectOptions{
+			Parallelism: map[string]int{"aws": 10, "aws:route53/record:Record": 2},
+		},
+	}
+
+	limits, err := getParallelismLimits(&workspace.Project{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, limits)
+
+	limits, err = getParallelismLimits(proj, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"aws": 10, "aws:route53/record:Record": 2}, limits)
+
+	// Flags override the project's limits.
+	limits, err = getParallelismLimits(proj, []string{"aws:route53/record:Record=1", "gcp=4"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"aws": 10, "aws:route53/record:Record": 1, "gcp": 4}, limits)
+
+	for _, flag := range []string{"aws", "=3", "aws=", "aws=0", "aws=many"} {
+		_, err = getParallelismLimits(proj, []string{flag})
+		assert.Error(t, err, flag)
+	}
+}
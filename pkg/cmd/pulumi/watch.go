@@ -64,7 +64,8 @@ func newWatchCmd() *cobra.Command {
 		Args: cmdutil.MaximumNArgs(1),
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
 
-			opts, err := updateFlagsToOptions(false /* interactive */, true /* skippreview*/, true /* autoapprove*/)
+			opts, err := updateFlagsToOptions(false /* interactive */, true /* skippreview*/, true /* autoapprove*/,
+				false /* previewOnly */)
 			if err != nil {
 				return result.FromError(err)
 			}
@@ -114,6 +115,9 @@ func newWatchCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(fmt.Errorf("getting stack configuration: %w", err))
 			}
+			if err = applyProjectConfigSchema(proj, &cfg); err != nil {
+				return result.FromError(err)
+			}
 
 			opts.Engine = engine.UpdateOptions{
 				LocalPolicyPacks:          engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
@@ -168,7 +172,7 @@ func newWatchCmd() *cobra.Command {
 		"Config keys contain a path to a property in a map or list to set")
 	cmd.PersistentFlags().StringVar(
 		&secretsProvider, "secrets-provider", "default", "The type of the provider that should be used to encrypt and "+
-			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault). Only"+
+			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault, exec). Only"+
 			"used when creating a new stack from an existing template")
 
 	cmd.PersistentFlags().StringVarP(
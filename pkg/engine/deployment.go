@@ -218,6 +218,7 @@ type runActions interface {
 	deploy.Events
 
 	Changes() ResourceChanges
+	Failures() int
 	MaybeCorrupt() bool
 }
 
@@ -253,6 +254,9 @@ func (deployment *deployment) run(cancelCtx *Context, actions runActions, policy
 		opts := deploy.Options{
 			Events:                    actions,
 			Parallel:                  deployment.Options.Parallel,
+			ParallelismLimits:         deployment.Options.ParallelismLimits,
+			Retries:                   deployment.Options.Retries,
+			ContinueOnError:           deployment.Options.ContinueOnError,
 			Refresh:                   deployment.Options.Refresh,
 			RefreshOnly:               deployment.Options.isRefresh,
 			RefreshTargets:            deployment.Options.RefreshTargets,
@@ -264,7 +268,7 @@ func (deployment *deployment) run(cancelCtx *Context, actions runActions, policy
 			UseLegacyDiff:             deployment.Options.UseLegacyDiff,
 			DisableResourceReferences: deployment.Options.DisableResourceReferences,
 			DisableOutputValues:       deployment.Options.DisableOutputValues,
-			ExperimentalPlans:         deployment.Options.UpdateOptions.ExperimentalPlans,
+			GeneratePlan:              deployment.Options.UpdateOptions.GeneratePlan,
 		}
 		newPlan, walkResult = deployment.Deployment.Execute(ctx, opts, preview)
 		close(done)
@@ -295,7 +299,8 @@ func (deployment *deployment) run(cancelCtx *Context, actions runActions, policy
 	changes := actions.Changes()
 
 	// Emit a summary event.
-	deployment.Options.Events.summaryEvent(preview, actions.MaybeCorrupt(), duration, changes, policyPacks)
+	deployment.Options.Events.summaryEvent(preview, actions.MaybeCorrupt(), duration, changes, actions.Failures(),
+		policyPacks)
 
 	return newPlan, changes, res
 }
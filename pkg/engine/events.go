@@ -131,6 +131,7 @@ type SummaryEventPayload struct {
 	MaybeCorrupt    bool              // true if one or more resources may be corrupt
 	Duration        time.Duration     // the duration of the entire update operation (zero values for previews)
 	ResourceChanges ResourceChanges   // count of changed resources, useful for reporting
+	Failures        int               // count of resource operations that failed
 	PolicyPacks     map[string]string // {policy-pack: version} for each policy pack applied
 }
 
@@ -518,7 +519,7 @@ func (e *eventEmitter) preludeEvent(isPreview bool, cfg config.Map) {
 }
 
 func (e *eventEmitter) summaryEvent(preview, maybeCorrupt bool, duration time.Duration, resourceChanges ResourceChanges,
-	policyPacks map[string]string) {
+	failures int, policyPacks map[string]string) {
 
 	contract.Requiref(e != nil, "e", "!= nil")
 
@@ -527,6 +528,7 @@ func (e *eventEmitter) summaryEvent(preview, maybeCorrupt bool, duration time.Du
 		MaybeCorrupt:    maybeCorrupt,
 		Duration:        duration,
 		ResourceChanges: resourceChanges,
+		Failures:        failures,
 		PolicyPacks:     policyPacks,
 	})
 }
@@ -916,3 +916,70 @@ func TestRefreshUpdateWithDeletedResource(t *testing.T) {
 	snap := p.Run(t, old)
 	assert.Equal(t, 0, len(snap.Resources))
 }
+
+// Tests that a refresh preview records the resources whose live state differs from the snapshot without changing it.
+func TestRefreshDriftReport(t *testing.T) {
+	t.Parallel()
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return "created-id", resource.PropertyMap{"size": resource.NewNumberProperty(1)}, resource.StatusOK, nil
+				},
+				ReadF: func(urn resource.URN, id resource.ID,
+					inputs, state resource.PropertyMap) (plugin.ReadResult, resource.Status, error) {
+					switch urn.Name() {
+					case "resA":
+						// resA has been resized outside of Pulumi.
+						outputs := resource.PropertyMap{"size": resource.NewNumberProperty(2)}
+						return plugin.ReadResult{Inputs: inputs, Outputs: outputs}, resource.StatusOK, nil
+					case "resB":
+						// resB has been deleted outside of Pulumi.
+						return plugin.ReadResult{}, resource.StatusOK, nil
+					default:
+						return plugin.ReadResult{Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+					}
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB", "resC"} {
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+	p := &TestPlan{Options: UpdateOptions{Host: host}}
+
+	p.Steps = []TestStep{{Op: Update}}
+	snap := p.Run(t, nil)
+
+	report := NewDriftReport()
+	opts := p.Options
+	opts.DriftReport = report
+	_, res := TestOp(Refresh).Run(p.GetProject(), p.GetTarget(t, snap), opts, true /*dryRun*/, p.BackendClient, nil)
+	assert.Nil(t, res)
+
+	assert.True(t, report.HasDrift())
+	drift := report.Resources()
+	if assert.Len(t, drift, 2) {
+		assert.Equal(t, p.NewURN("pkgA:m:typA", "resA", ""), drift[0].URN)
+		assert.Equal(t, deploy.OpUpdate, drift[0].Op)
+		if assert.NotNil(t, drift[0].Outputs) {
+			assert.Equal(t, resource.NewNumberProperty(2), drift[0].Outputs.Updates["size"].New)
+		}
+
+		assert.Equal(t, p.NewURN("pkgA:m:typA", "resB", ""), drift[1].URN)
+		assert.Equal(t, deploy.OpDelete, drift[1].Op)
+		assert.Nil(t, drift[1].New)
+	}
+
+	// The snapshot itself is left alone.
+	assert.Len(t, snap.Resources, 4)
+}
@@ -15,8 +15,13 @@
 package engine
 
 import (
+	"sort"
+	"sync"
+
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
@@ -81,3 +86,80 @@ func newRefreshSource(client deploy.BackendClient, opts deploymentOptions, proj
 	// Just return an error source. Refresh doesn't use its source.
 	return deploy.NewErrorSource(proj.Name), nil
 }
+
+// ResourceDrift describes how the live state of a resource, as read by a refresh, differs from the state recorded in
+// its stack.
+type ResourceDrift struct {
+	URN  resource.URN
+	Type tokens.Type
+	// Op is OpUpdate if the resource's outputs have changed, or OpDelete if the resource no longer exists.
+	Op deploy.StepOp
+	// Old is the recorded state of the resource.
+	Old *resource.State
+	// New is the live state of the resource, or nil if it no longer exists.
+	New *resource.State
+	// Outputs is the difference between the recorded and live outputs of a resource that has changed.
+	Outputs *resource.ObjectDiff
+}
+
+// DriftReport collects the resources whose live state differs from the state recorded in their stack. Pass a report
+// in UpdateOptions.DriftReport to have a refresh fill it in; refreshing with a dry run detects drift without changing
+// the stack's state.
+type DriftReport struct {
+	m         sync.Mutex
+	resources map[resource.URN]ResourceDrift
+}
+
+// NewDriftReport creates an empty drift report.
+func NewDriftReport() *DriftReport {
+	return &DriftReport{resources: make(map[resource.URN]ResourceDrift)}
+}
+
+// Resources returns the resources that have drifted, sorted by URN.
+func (r *DriftReport) Resources() []ResourceDrift {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	resources := make([]ResourceDrift, 0, len(r.resources))
+	for _, drift := range r.resources {
+		resources = append(resources, drift)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].URN < resources[j].URN })
+	return resources
+}
+
+// HasDrift returns true if any resource has drifted.
+func (r *DriftReport) HasDrift() bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return len(r.resources) > 0
+}
+
+// recordRefresh records the outcome of a successful refresh step. The report may be nil, in which case nothing is
+// recorded.
+func (r *DriftReport) recordRefresh(step *deploy.RefreshStep) {
+	if r == nil {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// A refresh that runs after its preview reads each resource again; the latest read wins.
+	old, new := step.Old(), step.New()
+	switch op := step.ResultOp(); op {
+	case deploy.OpSame:
+		delete(r.resources, old.URN)
+	case deploy.OpDelete:
+		r.resources[old.URN] = ResourceDrift{URN: old.URN, Type: old.Type, Op: op, Old: old}
+	default:
+		r.resources[old.URN] = ResourceDrift{
+			URN:     old.URN,
+			Type:    old.Type,
+			Op:      op,
+			Old:     old,
+			New:     new,
+			Outputs: old.Outputs.Diff(new.Outputs),
+		}
+	}
+}
@@ -26,8 +26,10 @@ import (
 
 	resourceanalyzer "github.com/pulumi/pulumi/pkg/v3/resource/analyzer"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/secretscan"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -105,6 +107,18 @@ type UpdateOptions struct {
 	// the degree of parallelism for resource operations (<=1 for serial).
 	Parallel int
 
+	// the degree of parallelism for resource operations of particular packages or resource types.
+	ParallelismLimits map[string]int
+
+	// the number of times to retry resource operations that fail with a retryable error.
+	Retries int
+
+	// true if the deployment should keep applying resources that do not depend on a failed resource.
+	ContinueOnError bool
+
+	// true if the engine should warn about plaintext values in the state of resources that look like secrets.
+	AuditSecrets bool
+
 	// true if debugging output it enabled
 	Debug bool
 
@@ -148,8 +162,11 @@ type UpdateOptions struct {
 	// The plan to use for the update, if any.
 	Plan *deploy.Plan
 
-	// true if experimental plans should be generated.
-	ExperimentalPlans bool
+	// true if an update plan should be generated.
+	GeneratePlan bool
+
+	// The report to record drifted resources in during a refresh, if any.
+	DriftReport *DriftReport
 }
 
 // ResourceChanges contains the aggregate resource changes by operation type.
@@ -494,16 +511,74 @@ type updateActions struct {
 	Opts    deploymentOptions
 
 	maybeCorrupt bool
+	failures     int
+
+	// secretScanner finds plaintext values that look like secrets when AuditSecrets is set, and auditedSecrets
+	// records the values it has warned about.
+	secretScanner  *secretscan.Scanner
+	auditedSecrets map[string]bool
 }
 
 func newUpdateActions(context *Context, u UpdateInfo, opts deploymentOptions) *updateActions {
-	return &updateActions{
+	acts := &updateActions{
 		Context: context,
 		Ops:     make(map[deploy.StepOp]int),
 		Seen:    make(map[resource.URN]deploy.Step),
 		Update:  u,
 		Opts:    opts,
 	}
+	if opts.AuditSecrets {
+		acts.secretScanner = newSecretScanner(u.GetTarget())
+		acts.auditedSecrets = make(map[string]bool)
+	}
+	return acts
+}
+
+// newSecretScanner creates a scanner for plaintext values that look like secrets, including the values of the
+// target's secret configuration keys.
+func newSecretScanner(target *deploy.Target) *secretscan.Scanner {
+	secretConfig := make(map[config.Key]string)
+	if target != nil && target.Decrypter != nil {
+		for k, v := range target.Config {
+			if !v.Secure() {
+				continue
+			}
+			plaintext, err := v.Value(target.Decrypter)
+			if err != nil {
+				logging.V(7).Infof("could not decrypt %s to audit secrets: %v", k, err)
+				continue
+			}
+			secretConfig[k] = plaintext
+		}
+	}
+	return secretscan.NewScanner(secretConfig)
+}
+
+// auditSecrets warns about the plaintext values in the new state of a resource that look like secrets.
+func (acts *updateActions) auditSecrets(step deploy.Step) {
+	if acts.secretScanner == nil || step.New() == nil {
+		return
+	}
+
+	for _, finding := range acts.secretScanner.ScanResource(step.New()) {
+		kind := "input"
+		if finding.Output {
+			kind = "output"
+		}
+
+		key := fmt.Sprintf("%s %s %s", finding.URN, kind, finding.Property)
+		acts.MapLock.Lock()
+		audited := acts.auditedSecrets[key]
+		acts.auditedSecrets[key] = true
+		acts.MapLock.Unlock()
+		if audited {
+			continue
+		}
+
+		acts.Opts.Diag.Warningf(diag.Message(finding.URN, "plaintext %s %s %s; "+
+			"consider marking it as a secret so that it is encrypted in the stack's state"),
+			kind, finding.Property, finding.Reason)
+	}
 }
 
 func (acts *updateActions) OnResourceStepPre(step deploy.Step) (interface{}, error) {
@@ -548,6 +623,10 @@ func (acts *updateActions) OnResourceStepPost(
 			errorURN = step.URN()
 		}
 
+		acts.MapLock.Lock()
+		acts.failures++
+		acts.MapLock.Unlock()
+
 		// Issue a true, bonafide error.
 		acts.Opts.Diag.Errorf(diag.GetResourceOperationFailedError(errorURN), err)
 		if reportStep {
@@ -558,6 +637,7 @@ func (acts *updateActions) OnResourceStepPost(
 		if acts.Opts.isRefresh && op == deploy.OpRefresh {
 			// Refreshes are handled specially.
 			op, record = step.(*deploy.RefreshStep).ResultOp(), true
+			acts.Opts.DriftReport.recordRefresh(step.(*deploy.RefreshStep))
 		}
 
 		if step.Op() == deploy.OpRead {
@@ -579,6 +659,9 @@ func (acts *updateActions) OnResourceStepPost(
 			acts.Opts.Events.resourceOutputsEvent(op, step, false /*planning*/, acts.Opts.Debug)
 		}
 	}
+	if err == nil {
+		acts.auditSecrets(step)
+	}
 
 	// See pulumi/pulumi#2011 for details. Terraform always returns the existing state with the diff applied to it in
 	// the event of an update failure. It's appropriate that we save this new state in the output of the resource, but
@@ -620,6 +703,7 @@ func (acts *updateActions) OnResourceOutputs(step deploy.Step) error {
 	if shouldReportStep(step, acts.Opts) {
 		acts.Opts.Events.resourceOutputsEvent(step.Op(), step, false /*planning*/, acts.Opts.Debug)
 	}
+	acts.auditSecrets(step)
 
 	// There's a chance there are new outputs that weren't written out last time.
 	// We need to perform another snapshot write to ensure they get written out.
@@ -638,11 +722,17 @@ func (acts *updateActions) Changes() ResourceChanges {
 	return ResourceChanges(acts.Ops)
 }
 
+func (acts *updateActions) Failures() int {
+	return acts.failures
+}
+
 type previewActions struct {
 	Ops     map[deploy.StepOp]int
 	Opts    deploymentOptions
 	Seen    map[resource.URN]deploy.Step
 	MapLock sync.Mutex
+
+	failures int
 }
 
 func shouldReportStep(step deploy.Step, opts deploymentOptions) bool {
@@ -702,12 +792,17 @@ func (acts *previewActions) OnResourceStepPost(ctx interface{},
 			reportedURN = step.URN()
 		}
 
+		acts.MapLock.Lock()
+		acts.failures++
+		acts.MapLock.Unlock()
+
 		acts.Opts.Diag.Errorf(diag.GetPreviewFailedError(reportedURN), err)
 	} else if reportStep {
 		op, record := step.Op(), step.Logical()
 		if acts.Opts.isRefresh && op == deploy.OpRefresh {
 			// Refreshes are handled specially.
 			op, record = step.(*deploy.RefreshStep).ResultOp(), true
+			acts.Opts.DriftReport.recordRefresh(step.(*deploy.RefreshStep))
 		}
 
 		if step.Op() == deploy.OpRead {
@@ -754,3 +849,7 @@ func (acts *previewActions) MaybeCorrupt() bool {
 func (acts *previewActions) Changes() ResourceChanges {
 	return ResourceChanges(acts.Ops)
 }
+
+func (acts *previewActions) Failures() int {
+	return acts.failures
+}
@@ -0,0 +1,147 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphmlconv converts a resource graph into a GraphML document.  GraphML is understood by graph editors and
+// analysis tools such as yEd, Gephi and NetworkX.  Please see http://graphml.graphdrawing.org/specification.html for
+// the specification of the format.
+package graphmlconv
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph"
+)
+
+const namespace = "http://graphml.graphdrawing.org/xmlns"
+
+// The keys of the data attached to the document's nodes and edges.
+const (
+	labelKey     = "label"
+	edgeLabelKey = "edgeLabel"
+	colorKey     = "color"
+)
+
+type graphML struct {
+	XMLName xml.Name   `xml:"graphml"`
+	XMLNS   string     `xml:"xmlns,attr"`
+	Keys    []key      `xml:"key"`
+	Graph   graphMLDoc `xml:"graph"`
+}
+
+type key struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLDoc struct {
+	ID          string `xml:"id,attr"`
+	EdgeDefault string `xml:"edgedefault,attr"`
+	Nodes       []node `xml:"node"`
+	Edges       []edge `xml:"edge"`
+}
+
+type node struct {
+	ID   string `xml:"id,attr"`
+	Data []data `xml:"data"`
+}
+
+type edge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []data `xml:"data"`
+}
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Print prints a resource graph.
+func Print(g graph.Graph, w io.Writer) error {
+	doc := graphML{
+		XMLNS: namespace,
+		Keys: []key{
+			{ID: labelKey, For: "node", AttrName: "label", AttrType: "string"},
+			{ID: edgeLabelKey, For: "edge", AttrName: "label", AttrType: "string"},
+			{ID: colorKey, For: "edge", AttrName: "color", AttrType: "string"},
+		},
+		Graph: graphMLDoc{ID: "G", EdgeDefault: "directed"},
+	}
+
+	// Initialize the frontier with unvisited graph vertices.
+	queued := make(map[graph.Vertex]bool)
+	frontier := make([]graph.Vertex, 0, len(g.Roots()))
+	for _, root := range g.Roots() {
+		to := root.To()
+		if !queued[to] {
+			queued[to] = true
+			frontier = append(frontier, to)
+		}
+	}
+
+	c := 0
+	ids := make(map[graph.Vertex]string)
+	getID := func(v graph.Vertex) string {
+		if id, has := ids[v]; has {
+			return id
+		}
+		id := "Resource" + strconv.Itoa(c)
+		c++
+		ids[v] = id
+		return id
+	}
+
+	for len(frontier) > 0 {
+		v := frontier[0]
+		frontier = frontier[1:]
+
+		n := node{ID: getID(v)}
+		if label := v.Label(); label != "" {
+			n.Data = append(n.Data, data{Key: labelKey, Value: label})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+
+		for _, out := range v.Outs() {
+			to := out.To()
+			e := edge{Source: n.ID, Target: getID(to)}
+			if label := out.Label(); label != "" {
+				e.Data = append(e.Data, data{Key: edgeLabelKey, Value: label})
+			}
+			if color := out.Color(); color != "" {
+				e.Data = append(e.Data, data{Key: colorKey, Value: color})
+			}
+			doc.Graph.Edges = append(doc.Graph.Edges, e)
+
+			if !queued[to] {
+				queued[to] = true
+				frontier = append(frontier, to)
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
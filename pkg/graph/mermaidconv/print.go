@@ -0,0 +1,111 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mermaidconv converts a resource graph into a Mermaid flowchart.  Mermaid diagrams are rendered by many
+// Markdown viewers, including GitHub's, which makes them handy for embedding in pull requests and documentation.
+// Please see https://mermaid-js.github.io/mermaid/#/flowchart for a description of the syntax.
+package mermaidconv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// Print prints a resource graph.
+func Print(g graph.Graph, w io.Writer) error {
+	// As with dotconv, we ignore write errors to the buffered writer and return the result of flushing it instead.
+	b := bufio.NewWriter(w)
+	if _, err := b.WriteString("flowchart TD\n"); err != nil {
+		return err
+	}
+
+	// Initialize the frontier with unvisited graph vertices.
+	queued := make(map[graph.Vertex]bool)
+	frontier := make([]graph.Vertex, 0, len(g.Roots()))
+	for _, root := range g.Roots() {
+		to := root.To()
+		if !queued[to] {
+			queued[to] = true
+			frontier = append(frontier, to)
+		}
+	}
+
+	c := 0
+	ids := make(map[graph.Vertex]string)
+	getID := func(v graph.Vertex) string {
+		if id, has := ids[v]; has {
+			return id
+		}
+		id := "Resource" + strconv.Itoa(c)
+		c++
+		ids[v] = id
+		return id
+	}
+
+	// Mermaid styles links by their index in the order they are declared, so remember the colors of each link and
+	// emit their styles once all links have been printed.
+	indent := "    "
+	var linkStyles []string
+	links := 0
+	emitted := make(map[graph.Vertex]bool)
+	for len(frontier) > 0 {
+		v := frontier[0]
+		frontier = frontier[1:]
+		contract.Assert(!emitted[v])
+		emitted[v] = true
+
+		id := getID(v)
+		if label := v.Label(); label != "" {
+			fmt.Fprintf(b, "%s%s[\"%s\"]\n", indent, id, escape(label))
+		} else {
+			fmt.Fprintf(b, "%s%s\n", indent, id)
+		}
+
+		for _, out := range v.Outs() {
+			to := out.To()
+			if label := out.Label(); label != "" {
+				fmt.Fprintf(b, "%s%s -->|\"%s\"| %s\n", indent, id, escape(label), getID(to))
+			} else {
+				fmt.Fprintf(b, "%s%s --> %s\n", indent, id, getID(to))
+			}
+			if color := out.Color(); color != "" {
+				linkStyles = append(linkStyles, fmt.Sprintf("%slinkStyle %d stroke:%s\n", indent, links, color))
+			}
+			links++
+
+			if !queued[to] {
+				queued[to] = true
+				frontier = append(frontier, to)
+			}
+		}
+	}
+
+	for _, style := range linkStyles {
+		if _, err := b.WriteString(style); err != nil {
+			return err
+		}
+	}
+	return b.Flush()
+}
+
+// escape replaces the characters that cannot appear within a quoted Mermaid label with their entity codes.
+func escape(label string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(label)
+}
@@ -63,7 +63,17 @@ type Options struct {
 	UseLegacyDiff             bool           // whether or not to use legacy diffing behavior.
 	DisableResourceReferences bool           // true to disable resource reference support.
 	DisableOutputValues       bool           // true to disable output value support.
-	ExperimentalPlans         bool           // true to enable experimental plan support.
+	GeneratePlan              bool           // true to generate an update plan.
+	// ParallelismLimits caps the number of resource operations that may run at once for particular packages (e.g.
+	// "aws") or resource types (e.g. "aws:route53/record:Record"). A resource type's limit takes precedence over its
+	// package's.
+	ParallelismLimits map[string]int
+	// Retries is the number of times to retry a resource operation that fails with an error the provider reports as
+	// retryable. Resources may override this with their own retry count.
+	Retries int
+	// ContinueOnError keeps the deployment going after a resource operation fails. Resources that depend on the failed
+	// resource are skipped, and no resources are deleted.
+	ContinueOnError bool
 }
 
 // DegreeOfParallelism returns the degree of parallelism that should be used during the
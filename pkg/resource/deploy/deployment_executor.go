@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
@@ -187,7 +188,7 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 	ctx, cancel := context.WithCancel(callerCtx)
 
 	// Set up a step generator and executor for this deployment.
-	ex.stepExec = newStepExecutor(ctx, cancel, ex.deployment, opts, preview, false)
+	ex.stepExec = newStepExecutor(ctx, cancel, ex.deployment, opts, preview, opts.ContinueOnError)
 
 	// We iterate the source in its own goroutine because iteration is blocking and we want the main loop to be able to
 	// respond to cancellation requests promptly.
@@ -238,6 +239,15 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 				}
 
 				if event.Event == nil {
+					// If some resources failed or were skipped, we cannot tell the resources the program no longer
+					// registers apart from the ones that it failed to register, so we leave them all in place.
+					if opts.ContinueOnError && ex.stepExec.Errored() {
+						ex.deployment.Diag().Warningf(diag.RawMessage("",
+							"skipping resource deletions because some resources failed to update"))
+						ex.stepExec.SignalCompletion()
+						return false, nil
+					}
+
 					res := ex.performDeletes(ctx, updateTargetsOpt, destroyTargetsOpt)
 					if res != nil {
 						if resErr := res.Error(); resErr != nil {
@@ -407,6 +417,10 @@ func (ex *deploymentExecutor) performDeletes(
 func (ex *deploymentExecutor) handleSingleEvent(event SourceEvent) result.Result {
 	contract.Require(event != nil, "event != nil")
 
+	if ex.stepExec.continueOnError {
+		return ex.handleSingleEventContinueOnError(event)
+	}
+
 	var steps []Step
 	var res result.Result
 	switch e := event.(type) {
@@ -429,6 +443,92 @@ func (ex *deploymentExecutor) handleSingleEvent(event SourceEvent) result.Result
 	return nil
 }
 
+// handleSingleEventContinueOnError handles a single source event for a deployment that continues after errors. Rather
+// than failing the deployment, a resource that cannot be registered or read is reported and recorded as failed, and
+// the program is told that its registration failed. Resources that depend on a failed resource are skipped.
+func (ex *deploymentExecutor) handleSingleEventContinueOnError(event SourceEvent) result.Result {
+	var steps []Step
+	var res result.Result
+	var fail func(err error)
+	var failedDep resource.URN
+	var hasFailedDep bool
+	switch e := event.(type) {
+	case RegisterResourceEvent:
+		logging.V(4).Infof("deploymentExecutor.handleSingleEvent(...): received RegisterResourceEvent")
+		reg := &onceRegisterResourceEvent{RegisterResourceEvent: e}
+		fail = func(err error) { reg.Done(&RegisterResult{Err: err}) }
+
+		goal := e.Goal()
+		var deps []resource.URN
+		deps = append(deps, goal.Dependencies...)
+		for _, propDeps := range goal.PropertyDependencies {
+			deps = append(deps, propDeps...)
+		}
+		failedDep, hasFailedDep = ex.stepExec.failedDependency(goal.Parent, goal.Provider, deps)
+		if !hasFailedDep {
+			steps, res = ex.stepGen.GenerateSteps(reg)
+		}
+	case ReadResourceEvent:
+		logging.V(4).Infof("deploymentExecutor.handleSingleEvent(...): received ReadResourceEvent")
+		read := &onceReadResourceEvent{ReadResourceEvent: e}
+		fail = func(err error) { read.Done(&ReadResult{Err: err}) }
+
+		failedDep, hasFailedDep = ex.stepExec.failedDependency(e.Parent(), e.Provider(), e.Dependencies())
+		if !hasFailedDep {
+			steps, res = ex.stepGen.GenerateReadSteps(read)
+		}
+	case RegisterResourceOutputsEvent:
+		logging.V(4).Infof("deploymentExecutor.handleSingleEvent(...): received register resource outputs")
+		return ex.stepExec.ExecuteRegisterResourceOutputs(e)
+	}
+
+	urn := ex.deployment.generateEventURN(event)
+	if hasFailedDep {
+		err := fmt.Errorf("skipped because its dependency %s failed", failedDep)
+		ex.reportError(urn, err)
+		ex.stepExec.markFailed(urn)
+		fail(err)
+		return nil
+	}
+	if res != nil {
+		if res.IsBail() {
+			return res
+		}
+		ex.reportError(urn, res.Error())
+		ex.stepExec.markFailed(urn)
+		fail(res.Error())
+		return nil
+	}
+
+	ex.stepExec.executeSerial(steps, func() {
+		fail(fmt.Errorf("resource %s failed", urn))
+	})
+	return nil
+}
+
+// onceRegisterResourceEvent wraps a RegisterResourceEvent so that it is completed at most once. A step that fails
+// partway through completes its event with the resource's partial state before the failure is recorded.
+type onceRegisterResourceEvent struct {
+	RegisterResourceEvent
+
+	once sync.Once
+}
+
+func (e *onceRegisterResourceEvent) Done(result *RegisterResult) {
+	e.once.Do(func() { e.RegisterResourceEvent.Done(result) })
+}
+
+// onceReadResourceEvent wraps a ReadResourceEvent so that it is completed at most once.
+type onceReadResourceEvent struct {
+	ReadResourceEvent
+
+	once sync.Once
+}
+
+func (e *onceReadResourceEvent) Done(result *ReadResult) {
+	e.once.Do(func() { e.ReadResourceEvent.Done(result) })
+}
+
 // retirePendingDeletes deletes all resources that are pending deletion. Run before the start of a deployment, this pass
 // ensures that the engine never sees any resources that are pending deletion from a previous deployment.
 //
@@ -100,6 +100,7 @@ type ResourceOptions struct {
 	ImportID              resource.ID
 	CustomTimeouts        *resource.CustomTimeouts
 	RetainOnDelete        bool
+	Retries               int
 	SupportsPartialValues *bool
 	Remote                bool
 	Providers             map[string]string
@@ -192,6 +193,7 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		Providers:                  opts.Providers,
 		PluginDownloadURL:          opts.PluginDownloadURL,
 		RetainOnDelete:             opts.RetainOnDelete,
+		Retries:                    int32(opts.Retries),
 	}
 
 	// submit request
@@ -0,0 +1,95 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ResourceDiff describes how a single resource differs between two snapshots.
+type ResourceDiff struct {
+	// Op is the operation that takes the resource from its old state to its new state: OpCreate if the resource only
+	// exists in the new snapshot, OpDelete if it only exists in the old snapshot, OpReplace if its ID changed, and
+	// OpUpdate otherwise.
+	Op StepOp
+	// URN is the URN of the resource.
+	URN resource.URN
+	// Old is the state of the resource in the old snapshot, or nil if it was created.
+	Old *resource.State
+	// New is the state of the resource in the new snapshot, or nil if it was deleted.
+	New *resource.State
+	// Inputs is the diff between the old and new inputs, or nil if they are identical.
+	Inputs *resource.ObjectDiff
+	// Outputs is the diff between the old and new outputs, or nil if they are identical.
+	Outputs *resource.ObjectDiff
+}
+
+// DiffSnapshots compares the resources recorded in two snapshots and returns the resources that differ. Resources are
+// matched by URN; resources that are pending deletion are ignored. Resources present in the new snapshot are returned
+// in the new snapshot's order, followed by the resources that were deleted in the old snapshot's order. Either snapshot
+// may be nil, in which case it is treated as empty.
+func DiffSnapshots(old, new *Snapshot) []ResourceDiff {
+	olds := liveResources(old)
+	news := liveResources(new)
+
+	var diffs []ResourceDiff
+	for _, n := range news.ordered {
+		o, has := olds.byURN[n.URN]
+		if !has {
+			diffs = append(diffs, ResourceDiff{Op: OpCreate, URN: n.URN, New: n})
+			continue
+		}
+
+		inputs, outputs := o.Inputs.Diff(n.Inputs), o.Outputs.Diff(n.Outputs)
+		var op StepOp
+		switch {
+		case o.Custom && n.Custom && o.ID != n.ID:
+			op = OpReplace
+		case inputs != nil || outputs != nil || o.Protect != n.Protect || o.Parent != n.Parent ||
+			o.Provider != n.Provider || o.Type != n.Type:
+			op = OpUpdate
+		default:
+			continue
+		}
+		diffs = append(diffs, ResourceDiff{Op: op, URN: n.URN, Old: o, New: n, Inputs: inputs, Outputs: outputs})
+	}
+	for _, o := range olds.ordered {
+		if _, has := news.byURN[o.URN]; !has {
+			diffs = append(diffs, ResourceDiff{Op: OpDelete, URN: o.URN, Old: o})
+		}
+	}
+	return diffs
+}
+
+type liveResourceSet struct {
+	ordered []*resource.State
+	byURN   map[resource.URN]*resource.State
+}
+
+// liveResources returns the resources in the given snapshot that are not pending deletion.
+func liveResources(snap *Snapshot) liveResourceSet {
+	set := liveResourceSet{byURN: make(map[resource.URN]*resource.State)}
+	if snap == nil {
+		return set
+	}
+	for _, res := range snap.Resources {
+		if res.Delete {
+			continue
+		}
+		set.ordered = append(set.ordered, res)
+		set.byURN[res.URN] = res
+	}
+	return set
+}
@@ -0,0 +1,103 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	t.Parallel()
+
+	urn := func(name string) resource.URN {
+		return resource.NewURN("stack", "test", "", "pkg:index:typ", tokens.QName(name))
+	}
+	state := func(name string, id resource.ID, props resource.PropertyMap) *resource.State {
+		return &resource.State{
+			Type:    "pkg:index:typ",
+			URN:     urn(name),
+			Custom:  true,
+			ID:      id,
+			Inputs:  props,
+			Outputs: props,
+		}
+	}
+
+	old := &Snapshot{Resources: []*resource.State{
+		state("same", "id1", resource.PropertyMap{"a": resource.NewStringProperty("x")}),
+		state("updated", "id2", resource.PropertyMap{"a": resource.NewStringProperty("x")}),
+		state("replaced", "id3", nil),
+		state("deleted", "id4", nil),
+	}}
+	pendingDelete := state("created", "id0", nil)
+	pendingDelete.Delete = true
+	new := &Snapshot{Resources: []*resource.State{
+		pendingDelete,
+		state("same", "id1", resource.PropertyMap{"a": resource.NewStringProperty("x")}),
+		state("updated", "id2", resource.PropertyMap{"a": resource.NewStringProperty("y")}),
+		state("replaced", "id5", nil),
+		state("created", "id6", nil),
+	}}
+
+	diffs := DiffSnapshots(old, new)
+	if !assert.Len(t, diffs, 4) {
+		return
+	}
+
+	assert.Equal(t, OpUpdate, diffs[0].Op)
+	assert.Equal(t, urn("updated"), diffs[0].URN)
+	if assert.NotNil(t, diffs[0].Inputs) {
+		assert.Equal(t, []resource.PropertyKey{"a"}, diffs[0].Inputs.ChangedKeys())
+	}
+	assert.NotNil(t, diffs[0].Outputs)
+
+	assert.Equal(t, OpReplace, diffs[1].Op)
+	assert.Equal(t, urn("replaced"), diffs[1].URN)
+	assert.Nil(t, diffs[1].Inputs)
+
+	assert.Equal(t, OpCreate, diffs[2].Op)
+	assert.Equal(t, urn("created"), diffs[2].URN)
+	assert.Nil(t, diffs[2].Old)
+
+	assert.Equal(t, OpDelete, diffs[3].Op)
+	assert.Equal(t, urn("deleted"), diffs[3].URN)
+	assert.Nil(t, diffs[3].New)
+}
+
+func TestDiffSnapshotsEmpty(t *testing.T) {
+	t.Parallel()
+
+	snap := &Snapshot{Resources: []*resource.State{{
+		Type: resource.RootStackType,
+		URN:  resource.NewURN("stack", "test", "", resource.RootStackType, "test-stack"),
+	}}}
+
+	assert.Empty(t, DiffSnapshots(snap, snap))
+
+	diffs := DiffSnapshots(nil, snap)
+	if assert.Len(t, diffs, 1) {
+		assert.Equal(t, OpCreate, diffs[0].Op)
+	}
+
+	diffs = DiffSnapshots(snap, nil)
+	if assert.Len(t, diffs, 1) {
+		assert.Equal(t, OpDelete, diffs[0].Op)
+	}
+}
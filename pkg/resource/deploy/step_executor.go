@@ -20,9 +20,12 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
@@ -35,6 +38,11 @@ const (
 
 	// Utility constant for easy debugging.
 	stepExecutorLogLevel = 4
+
+	// The delay before the first retry of a step that failed with a retryable error, and the most we will wait
+	// between retries. The delay doubles with each attempt unless the provider suggests its own.
+	initialRetryDelay = time.Second
+	maxRetryDelay     = 30 * time.Second
 )
 
 var (
@@ -76,6 +84,7 @@ func (c completionToken) Wait(ctx context.Context) {
 type incomingChain struct {
 	Chain          chain     // The chain we intend to execute
 	CompletionChan chan bool // A completion channel to be closed when the chain has completed execution
+	OnFailure      func()    // A callback to run if the chain fails and the deployment continues on error, if any
 }
 
 // stepExecutor is the component of the engine responsible for taking steps and executing
@@ -91,6 +100,7 @@ type stepExecutor struct {
 	preview         bool        // Whether or not we are doing a preview.
 	pendingNews     sync.Map    // Resources that have been created but are pending a RegisterResourceOutputs.
 	continueOnError bool        // True if we want to continue the deployment after a step error.
+	failed          sync.Map    // Resources whose steps failed or were skipped, if continuing after errors.
 
 	workers        sync.WaitGroup     // WaitGroup tracking the worker goroutines that are owned by this step executor.
 	incomingChains chan incomingChain // Incoming chains that we are to execute
@@ -98,6 +108,8 @@ type stepExecutor struct {
 	ctx      context.Context    // cancellation context for the current deployment.
 	cancel   context.CancelFunc // CancelFunc that cancels the above context.
 	sawError atomic.Value       // atomic boolean indicating whether or not the step excecutor saw that there was an error.
+
+	limits *stepLimits // The limits on the number of steps that may execute at once, if any.
 }
 
 //
@@ -109,12 +121,18 @@ type stepExecutor struct {
 // Execute submits a Chain for asynchronous execution. The execution of the chain will begin as soon as there
 // is a worker available to execute it.
 func (se *stepExecutor) ExecuteSerial(chain chain) completionToken {
+	return se.executeSerial(chain, nil)
+}
+
+// executeSerial submits a chain for asynchronous execution. If the chain fails and the deployment continues on error,
+// onFailure is called once the failure has been recorded.
+func (se *stepExecutor) executeSerial(chain chain, onFailure func()) completionToken {
 	// The select here is to avoid blocking on a send to se.incomingChains if a cancellation is pending.
 	// If one is pending, we should exit early - we will shortly be tearing down the engine and exiting.
 
 	completion := make(chan bool)
 	select {
-	case se.incomingChains <- incomingChain{Chain: chain, CompletionChan: completion}:
+	case se.incomingChains <- incomingChain{Chain: chain, CompletionChan: completion, OnFailure: onFailure}:
 	case <-se.ctx.Done():
 		close(completion)
 	}
@@ -152,6 +170,13 @@ func (se *stepExecutor) ExecuteRegisterResourceOutputs(e RegisterResourceOutputs
 	// Look up the final state in the pending registration list.
 	urn := e.URN()
 	value, has := se.pendingNews.Load(urn)
+	if !has && se.continueOnError {
+		// The resource's registration failed or was skipped, so there are no outputs to record.
+		if _, failed := se.failed.Load(urn); failed {
+			e.Done()
+			return nil
+		}
+	}
 	contract.Assertf(has, "cannot complete a resource '%v' whose registration isn't pending", urn)
 	reg := value.(Step)
 	contract.Assertf(reg != nil, "expected a non-nil resource step ('%v')", urn)
@@ -177,12 +202,12 @@ func (se *stepExecutor) ExecuteRegisterResourceOutputs(e RegisterResourceOutputs
 		}
 
 		if err := resourcePlan.checkOutputs(oldOuts, outs); err != nil {
-			return result.FromError(fmt.Errorf("resource violates plan: %w", err))
+			return result.FromError(fmt.Errorf("resource %s violates plan: %w", urn, err))
 		}
 	}
 
-	// If we're in experimental mode save these new outputs to the plan
-	if se.opts.ExperimentalPlans {
+	// If we're generating a plan save these new outputs to the plan
+	if se.opts.GeneratePlan {
 		if resourcePlan, ok := se.deployment.newPlans.get(urn); ok {
 			resourcePlan.Goal.OutputDiff = NewPlanDiff(oldOuts.Diff(outs))
 			resourcePlan.Outputs = outs
@@ -238,9 +263,38 @@ func (se *stepExecutor) WaitForCompletion() {
 // the next few functions.
 //
 
+// markFailed records that the resource with the given URN failed or was skipped, so that resources that depend on it
+// are skipped as well.
+func (se *stepExecutor) markFailed(urn resource.URN) {
+	se.failed.Store(urn, true)
+	se.sawError.Store(true)
+}
+
+// failedDependency returns the first of the given parent, provider reference and dependencies whose resource failed or
+// was skipped, if any.
+func (se *stepExecutor) failedDependency(parent resource.URN, provider string,
+	deps []resource.URN) (resource.URN, bool) {
+
+	if provider != "" {
+		if ref, err := providers.ParseReference(provider); err == nil {
+			deps = append([]resource.URN{ref.URN()}, deps...)
+		}
+	}
+	if parent != "" {
+		deps = append([]resource.URN{parent}, deps...)
+	}
+	for _, dep := range deps {
+		if _, failed := se.failed.Load(dep); failed {
+			return dep, true
+		}
+	}
+	return "", false
+}
+
 // executeChain executes a chain, one step at a time. If any step in the chain fails to execute, or if the
-// context is canceled, the chain stops execution.
-func (se *stepExecutor) executeChain(workerID int, chain chain) {
+// context is canceled, the chain stops execution. If the deployment continues on error, the failed step's resource is
+// recorded as failed and onFailure, if any, is called.
+func (se *stepExecutor) executeChain(workerID int, chain chain, onFailure func()) {
 	for _, step := range chain {
 		select {
 		case <-se.ctx.Done():
@@ -249,7 +303,15 @@ func (se *stepExecutor) executeChain(workerID int, chain chain) {
 		default:
 		}
 
-		if err := se.executeStep(workerID, step); err != nil {
+		release, ok := se.limits.acquire(se.ctx, step)
+		if !ok {
+			se.log(workerID, "step %v on %v canceled", step.Op(), step.URN())
+			return
+		}
+		err := se.executeStep(workerID, step)
+		release()
+
+		if err != nil {
 			se.log(workerID, "step %v on %v failed, signalling cancellation", step.Op(), step.URN())
 			se.cancelDueToError()
 			if err != errStepApplyFailed {
@@ -261,6 +323,12 @@ func (se *stepExecutor) executeChain(workerID int, chain chain) {
 				diagMsg := diag.RawMessage(step.URN(), err.Error())
 				se.deployment.Diag().Errorf(diagMsg)
 			}
+			if se.continueOnError {
+				se.markFailed(step.URN())
+				if onFailure != nil {
+					onFailure()
+				}
+			}
 			return
 		}
 	}
@@ -299,7 +367,7 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	}
 
 	se.log(workerID, "applying step %v on %v (preview %v)", step.Op(), step.URN(), se.preview)
-	status, stepComplete, err := step.Apply(se.preview)
+	status, stepComplete, err := se.applyStep(workerID, step)
 
 	if err == nil {
 		// If we have a state object, and this is a create or update, remember it, as we may need to update it later.
@@ -327,8 +395,8 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 			se.deployment.news.set(newState.URN, newState)
 		}
 
-		// If we're in experimental mode update the resource's outputs in the generated plan.
-		if se.opts.ExperimentalPlans {
+		// If we're generating a plan update the resource's outputs in the generated plan.
+		if se.opts.GeneratePlan {
 			if resourcePlan, ok := se.deployment.newPlans.get(newState.URN); ok {
 				resourcePlan.Outputs = newState.Outputs
 			}
@@ -357,6 +425,54 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	return nil
 }
 
+// applyStep applies a step, retrying it if it fails with an error that the provider reports as retryable and the
+// resource or the deployment allows retries.
+func (se *stepExecutor) applyStep(workerID int, step Step) (resource.Status, StepCompleteFunc, error) {
+	retries := se.opts.Retries
+	if goal, has := se.deployment.goals.get(step.URN()); has && goal.Retries > 0 {
+		retries = goal.Retries
+	}
+
+	for attempt := 1; ; attempt++ {
+		status, stepComplete, err := step.Apply(se.preview)
+
+		// Only retry operations that did not change anything, i.e. that failed with a known status.
+		var retryable *plugin.RetryableError
+		if err == nil || status != resource.StatusOK || attempt > retries || !errors.As(err, &retryable) {
+			return status, stepComplete, err
+		}
+
+		delay := retryDelay(attempt, retryable.RetryAfter)
+		se.log(workerID, "step %v on %v failed with a retryable error, retrying in %v: %v",
+			step.Op(), step.URN(), delay, err)
+		se.deployment.Diag().Warningf(diag.RawMessage(step.URN(), fmt.Sprintf(
+			"%s failed with a retryable error; retrying in %v (retry %d of %d): %v",
+			step.Op(), delay, attempt, retries, err)))
+
+		select {
+		case <-time.After(delay):
+		case <-se.ctx.Done():
+			return status, stepComplete, err
+		}
+	}
+}
+
+// retryDelay returns how long to wait before the given retry attempt. The provider's suggested delay is used if it
+// has one; otherwise the delay grows exponentially with each attempt.
+func retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	delay := initialRetryDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
 // log is a simple logging helper for the step executor.
 func (se *stepExecutor) log(workerID int, msg string, args ...interface{}) {
 	if logging.V(stepExecutorLogLevel) {
@@ -399,7 +515,7 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 
 			se.log(workerID, "worker received chain for execution")
 			if !launchAsync {
-				se.executeChain(workerID, request.Chain)
+				se.executeChain(workerID, request.Chain, request.OnFailure)
 				close(request.CompletionChan)
 				continue
 			}
@@ -411,7 +527,7 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 			go func() {
 				defer se.workers.Done()
 				se.log(newWorkerID, "launching oneshot worker")
-				se.executeChain(newWorkerID, request.Chain)
+				se.executeChain(newWorkerID, request.Chain, request.OnFailure)
 				close(request.CompletionChan)
 			}()
 
@@ -437,6 +553,17 @@ func newStepExecutor(ctx context.Context, cancel context.CancelFunc, deployment
 
 	exec.sawError.Store(false)
 
+	// If some packages or resource types have their own parallelism limits, spawn a single worker that launches chain
+	// executions asynchronously and enforce the overall limit on a per-step basis instead. Steps waiting for a limited
+	// package or resource type must not occupy one of a fixed number of workers, or they would hold up the rest of
+	// the deployment.
+	if len(opts.ParallelismLimits) > 0 {
+		exec.limits = newStepLimits(opts)
+		exec.workers.Add(1)
+		go exec.worker(infiniteWorkerID, true /*launchAsync*/)
+		return exec
+	}
+
 	// If we're being asked to run as parallel as possible, spawn a single worker that launches chain executions
 	// asynchronously.
 	if opts.InfiniteParallelism() {
@@ -454,3 +581,67 @@ func newStepExecutor(ctx context.Context, cancel context.CancelFunc, deployment
 
 	return exec
 }
+
+// stepLimits bounds the number of steps that may execute at once for particular packages or resource types, as well
+// as the number of steps that may execute at once overall.
+type stepLimits struct {
+	overall chan struct{}            // slots shared by all steps, or nil if overall parallelism is unbounded.
+	byKey   map[string]chan struct{} // slots for each limited package or resource type.
+}
+
+func newStepLimits(opts Options) *stepLimits {
+	limits := &stepLimits{byKey: make(map[string]chan struct{})}
+	if !opts.InfiniteParallelism() {
+		limits.overall = make(chan struct{}, opts.DegreeOfParallelism())
+	}
+	for key, limit := range opts.ParallelismLimits {
+		if limit < 1 {
+			limit = 1
+		}
+		limits.byKey[key] = make(chan struct{}, limit)
+	}
+	return limits
+}
+
+// slotsFor returns the slots that limit the given step: those of its resource type, if it has a limit, or otherwise
+// those of its package. Steps that leave their resource unchanged do not call their provider and are never limited.
+func (l *stepLimits) slotsFor(step Step) chan struct{} {
+	if step.Op() == OpSame {
+		return nil
+	}
+	if slots, has := l.byKey[string(step.Type())]; has {
+		return slots
+	}
+	return l.byKey[string(step.Type().Package())]
+}
+
+// acquire blocks until the given step may execute and returns a function that releases the step's slots once it has
+// finished. If the context is canceled first, acquire returns false. A nil stepLimits never blocks.
+func (l *stepLimits) acquire(ctx context.Context, step Step) (func(), bool) {
+	if l == nil {
+		return func() {}, true
+	}
+
+	var held []chan struct{}
+	release := func() {
+		for _, slots := range held {
+			<-slots
+		}
+	}
+
+	// Always take the step's own slot before an overall slot so that steps waiting for a limited package or resource
+	// type do not keep other steps from running.
+	for _, slots := range []chan struct{}{l.slotsFor(step), l.overall} {
+		if slots == nil {
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+			held = append(held, slots)
+		case <-ctx.Done():
+			release()
+			return nil, false
+		}
+	}
+	return release, true
+}
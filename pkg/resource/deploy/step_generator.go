@@ -198,23 +198,29 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, res
 		if sg.deployment.plan != nil {
 			if resourcePlan, ok := sg.deployment.plan.ResourcePlans[s.URN()]; ok {
 				if len(resourcePlan.Ops) == 0 {
-					return nil, result.Errorf("%v is not allowed by the plan: no more steps were expected for this resource", s.Op())
+					return nil, result.Errorf(
+						"resource %s violates plan: %v is not allowed: no more steps were expected for this resource",
+						s.URN(), s.Op())
 				}
 
 				constraint := resourcePlan.Ops[0]
 				if !s.Op().ConstrainedTo(constraint) {
-					return nil, result.Errorf("%v is not allowed by the plan: this resource is constrained to %v", s.Op(), constraint)
+					return nil, result.Errorf(
+						"resource %s violates plan: %v is not allowed: this resource is constrained to %v",
+						s.URN(), s.Op(), constraint)
 				}
 				resourcePlan.Ops = resourcePlan.Ops[1:]
 			} else {
 				if !s.Op().ConstrainedTo(OpSame) {
-					return nil, result.Errorf("%v is not allowed by the plan: no steps were expected for this resource", s.Op())
+					return nil, result.Errorf(
+						"resource %s violates plan: %v is not allowed: no steps were expected for this resource",
+						s.URN(), s.Op())
 				}
 			}
 		}
 
-		// If we're in experimental mode add the operation to the plan being generated
-		if sg.opts.ExperimentalPlans {
+		// If we're generating a plan add the operation to the plan being generated
+		if sg.opts.GeneratePlan {
 			// Resource plan might be aliased
 			urn, isAliased := sg.aliased[s.URN()]
 			if !isAliased {
@@ -372,8 +378,8 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, res
 		new.ID = goal.ID
 		new.ImportID = goal.ID
 
-		// If we're in experimental mode create a plan, Imports have no diff, just a goal state
-		if sg.opts.ExperimentalPlans {
+		// If we're generating a plan create a plan, Imports have no diff, just a goal state
+		if sg.opts.GeneratePlan {
 			newResourcePlan := &ResourcePlan{Goal: NewGoalPlan(nil, nil, goal)}
 			sg.deployment.newPlans.set(urn, newResourcePlan)
 		}
@@ -447,8 +453,8 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, res
 		new.Inputs = inputs
 	}
 
-	// If the resource is valid and we're in experimental mode generate a plan
-	if !invalid && sg.opts.ExperimentalPlans {
+	// If the resource is valid and we're generating a plan add its goal to it
+	if !invalid && sg.opts.GeneratePlan {
 		if recreating || wasExternal || sg.isTargetedReplace(urn) || !hasOld {
 			oldInputs = nil
 		}
@@ -802,8 +808,8 @@ func (sg *stepGenerator) generateStepsFromDiff(
 							continue
 						}
 
-						// If we're in experimental mode create a plan for this delete
-						if sg.opts.ExperimentalPlans {
+						// If we're generating a plan create a plan for this delete
+						if sg.opts.GeneratePlan {
 							if _, ok := sg.deployment.newPlans.get(dependentResource.URN); !ok {
 								// We haven't see this resource before, create a new
 								// resource plan for it with no goal (because it's going to be a delete)
@@ -922,7 +928,9 @@ func (sg *stepGenerator) GenerateDeletes(targetsOpt map[resource.URN]bool) ([]St
 		if sg.deployment.plan != nil {
 			if resourcePlan, ok := sg.deployment.plan.ResourcePlans[s.URN()]; ok {
 				if len(resourcePlan.Ops) == 0 {
-					return nil, result.Errorf("%v is not allowed by the plan: no more steps were expected for this resource", s.Op())
+					return nil, result.Errorf(
+						"resource %s violates plan: %v is not allowed: no more steps were expected for this resource",
+						s.URN(), s.Op())
 				}
 
 				constraint := resourcePlan.Ops[0]
@@ -932,17 +940,21 @@ func (sg *stepGenerator) GenerateDeletes(targetsOpt map[resource.URN]bool) ([]St
 				resourcePlan.Ops = resourcePlan.Ops[1:]
 
 				if !s.Op().ConstrainedTo(constraint) {
-					return nil, result.Errorf("%v is not allowed by the plan: this resource is constrained to %v", s.Op(), constraint)
+					return nil, result.Errorf(
+						"resource %s violates plan: %v is not allowed: this resource is constrained to %v",
+						s.URN(), s.Op(), constraint)
 				}
 			} else {
 				if !s.Op().ConstrainedTo(OpSame) {
-					return nil, result.Errorf("%v is not allowed by the plan: no steps were expected for this resource", s.Op())
+					return nil, result.Errorf(
+						"resource %s violates plan: %v is not allowed: no steps were expected for this resource",
+						s.URN(), s.Op())
 				}
 			}
 		}
 
-		// If we're in experimental mode add a delete op to the plan for this resource
-		if sg.opts.ExperimentalPlans {
+		// If we're generating a plan add a delete op to the plan for this resource
+		if sg.opts.GeneratePlan {
 			resourcePlan, ok := sg.deployment.newPlans.get(s.URN())
 			if !ok {
 				// TODO(pdg-plan): using the program inputs means that non-determinism could sneak in as part of default
@@ -39,3 +39,24 @@ type ResourceProtectedError struct {
 func (ResourceProtectedError) Error() string {
 	return "Can't delete protected resource"
 }
+
+// ResourceHasDanglingDependenciesError is returned by MoveResources if a resource can't be moved because it depends on
+// resources that would be left behind in the source stack.
+type ResourceHasDanglingDependenciesError struct {
+	Resource     *resource.State
+	Dependencies []resource.URN
+}
+
+func (r ResourceHasDanglingDependenciesError) Error() string {
+	return fmt.Sprintf("Can't move resource %q without also moving the resources it depends on", r.Resource.URN)
+}
+
+// ResourceAlreadyExistsError is returned by MoveResources if a resource with the same URN already exists in the
+// destination stack.
+type ResourceAlreadyExistsError struct {
+	URN resource.URN
+}
+
+func (r ResourceAlreadyExistsError) Error() string {
+	return fmt.Sprintf("A resource named %q already exists in the destination stack", r.URN)
+}
@@ -160,3 +160,247 @@ func RenameStack(snap *deploy.Snapshot, newName tokens.Name, newProject tokens.P
 
 	return nil
 }
+
+// MoveResources moves the resources with the given URNs from the source snapshot into the destination snapshot,
+// rewriting their URNs so that they belong to the given destination stack and project. Every resource that depends
+// on, is parented to, or uses as its provider one of the requested resources is moved along with it, so that nothing
+// left behind in the source snapshot refers to a resource that no longer exists there.
+//
+// If a moved resource depends on a resource that is not being moved, MoveResources refuses to perform the move and
+// returns an error instance of `ResourceHasDanglingDependenciesError`. Providers are the exception: a provider that
+// is used but not moved is copied into the destination snapshot, unless an identical provider already exists there.
+// Resources parented to the source stack's root resource are reparented to the destination stack's root resource.
+//
+// Both snapshots are edited in-place. The returned slice contains the moved resources as they appear in the
+// destination snapshot.
+func MoveResources(source, dest *deploy.Snapshot, urns []resource.URN,
+	destStack tokens.QName, destProject tokens.PackageName) ([]*resource.State, error) {
+	contract.Require(source != nil, "source")
+	contract.Require(dest != nil, "dest")
+
+	if err := source.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("source checkpoint is invalid: %w", err)
+	}
+	if err := dest.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("destination checkpoint is invalid: %w", err)
+	}
+
+	requested := make(map[resource.URN]bool)
+	for _, urn := range urns {
+		candidates := LocateResource(source, urn)
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("no such resource %q exists in the source stack", urn)
+		}
+		if candidates[0].Type == resource.RootStackType {
+			return nil, fmt.Errorf("the root stack resource %q can't be moved", urn)
+		}
+		requested[urn] = true
+	}
+
+	// Compute the set of resources to move. Because snapshots are stored in topological order, a single pass is
+	// sufficient to pick up all resources that transitively depend on, or descend from, a requested resource.
+	moving := make(map[resource.URN]bool)
+	var moved []*resource.State
+	for _, res := range source.Resources {
+		if requested[res.URN] || moving[res.Parent] || dependsOnAny(res, moving) {
+			moving[res.URN] = true
+			moved = append(moved, res)
+		}
+	}
+
+	for _, op := range source.PendingOperations {
+		if moving[op.Resource.URN] {
+			return nil, fmt.Errorf("resource %q has a pending %s operation; run `pulumi refresh` before moving it",
+				op.Resource.URN, op.Type)
+		}
+	}
+
+	var sourceRoot, destRoot *resource.State
+	for _, res := range source.Resources {
+		if res.Type == resource.RootStackType && res.Parent == "" {
+			sourceRoot = res
+			break
+		}
+	}
+	for _, res := range dest.Resources {
+		if res.Type == resource.RootStackType && res.Parent == "" {
+			destRoot = res
+			break
+		}
+	}
+
+	rewriteURN := func(u resource.URN) resource.URN {
+		return resource.NewURN(destStack, destProject, "", u.QualifiedType(), u.Name())
+	}
+
+	// Work out which providers need to travel with the moved resources, and make sure that nothing else would be
+	// left dangling.
+	sourceByURN := make(map[resource.URN]*resource.State)
+	for _, res := range source.Resources {
+		if !res.Delete {
+			sourceByURN[res.URN] = res
+		}
+	}
+	carried := make(map[resource.URN]bool)
+	var providerRefs []resource.URN
+	checkDependencies := func(res *resource.State) error {
+		var dangling []resource.URN
+		for _, dep := range res.Dependencies {
+			if !moving[dep] && !carried[dep] {
+				dangling = append(dangling, dep)
+			}
+		}
+		if res.Parent != "" && !moving[res.Parent] && (sourceRoot == nil || res.Parent != sourceRoot.URN) {
+			dangling = append(dangling, res.Parent)
+		}
+		if len(dangling) != 0 {
+			return ResourceHasDanglingDependenciesError{Resource: res, Dependencies: dangling}
+		}
+		return nil
+	}
+	for _, res := range moved {
+		if res.Provider == "" {
+			continue
+		}
+		ref, err := providers.ParseReference(res.Provider)
+		contract.AssertNoErrorf(err, "failed to parse provider reference from validated checkpoint")
+		if !moving[ref.URN()] && !carried[ref.URN()] {
+			carried[ref.URN()] = true
+			providerRefs = append(providerRefs, ref.URN())
+		}
+	}
+	for _, res := range moved {
+		if err := checkDependencies(res); err != nil {
+			return nil, err
+		}
+	}
+
+	destURNs := make(map[resource.URN]*resource.State)
+	for _, res := range dest.Resources {
+		if !res.Delete {
+			destURNs[res.URN] = res
+		}
+	}
+
+	var newResources []*resource.State
+	if destRoot == nil && sourceRoot != nil {
+		destRoot = &resource.State{
+			Type:    resource.RootStackType,
+			URN:     resource.DefaultRootStackURN(destStack, destProject),
+			Inputs:  resource.PropertyMap{},
+			Outputs: resource.PropertyMap{},
+		}
+		newResources = append(newResources, destRoot)
+	}
+
+	rewriteState := func(res *resource.State) *resource.State {
+		contract.Assert(res != nil)
+
+		copied := *res
+		copied.URN = rewriteURN(res.URN)
+
+		switch {
+		case res.Parent == "":
+		case sourceRoot != nil && res.Parent == sourceRoot.URN:
+			copied.Parent = destRoot.URN
+		default:
+			copied.Parent = rewriteURN(res.Parent)
+		}
+
+		copied.Dependencies = make([]resource.URN, len(res.Dependencies))
+		for depIdx, dep := range res.Dependencies {
+			copied.Dependencies[depIdx] = rewriteURN(dep)
+		}
+
+		if res.PropertyDependencies != nil {
+			copied.PropertyDependencies = make(map[resource.PropertyKey][]resource.URN)
+			for key, propDeps := range res.PropertyDependencies {
+				var deps []resource.URN
+				for _, dep := range propDeps {
+					if moving[dep] || carried[dep] {
+						deps = append(deps, rewriteURN(dep))
+					}
+				}
+				copied.PropertyDependencies[key] = deps
+			}
+		}
+
+		// Aliases refer to the resource's previous names in the source stack, which are meaningless in the
+		// destination.
+		copied.Aliases = nil
+
+		if res.Provider != "" {
+			providerRef, err := providers.ParseReference(res.Provider)
+			contract.AssertNoErrorf(err, "failed to parse provider reference from validated checkpoint")
+
+			providerRef, err = providers.NewReference(rewriteURN(providerRef.URN()), providerRef.ID())
+			contract.AssertNoErrorf(err, "failed to generate provider reference from valid reference")
+
+			copied.Provider = providerRef.String()
+		}
+
+		return &copied
+	}
+
+	for _, urn := range providerRefs {
+		provider := sourceByURN[urn]
+		contract.Assertf(provider != nil, "provider %v missing from validated checkpoint", urn)
+		if err := checkDependencies(provider); err != nil {
+			return nil, err
+		}
+
+		newProvider := rewriteState(provider)
+		if existing, has := destURNs[newProvider.URN]; has {
+			if existing.ID != newProvider.ID {
+				return nil, ResourceAlreadyExistsError{URN: newProvider.URN}
+			}
+			continue
+		}
+		newResources = append(newResources, newProvider)
+	}
+
+	var result []*resource.State
+	for _, res := range moved {
+		newRes := rewriteState(res)
+		if _, has := destURNs[newRes.URN]; has && !newRes.Delete {
+			return nil, ResourceAlreadyExistsError{URN: newRes.URN}
+		}
+		newResources = append(newResources, newRes)
+		result = append(result, newRes)
+	}
+
+	// Nothing has been mutated up to this point, so a failure above leaves both snapshots untouched. Now perform the
+	// move proper: a newly created root resource must come first in the destination, and everything else is
+	// appended after the destination's existing resources, which cannot depend on anything that we are adding.
+	var remaining []*resource.State
+	for _, res := range source.Resources {
+		if !moving[res.URN] {
+			remaining = append(remaining, res)
+		}
+	}
+	source.Resources = remaining
+
+	if len(newResources) != 0 && newResources[0] == destRoot {
+		dest.Resources = append([]*resource.State{destRoot}, dest.Resources...)
+		newResources = newResources[1:]
+	}
+	dest.Resources = append(dest.Resources, newResources...)
+	return result, nil
+}
+
+// dependsOnAny returns true if the given resource depends on, or uses as its provider, any of the given resources.
+func dependsOnAny(res *resource.State, urns map[resource.URN]bool) bool {
+	for _, dep := range res.Dependencies {
+		if urns[dep] {
+			return true
+		}
+	}
+	if res.Provider != "" {
+		ref, err := providers.ParseReference(res.Provider)
+		contract.AssertNoErrorf(err, "failed to parse provider reference from validated checkpoint")
+		if urns[ref.URN()] {
+			return true
+		}
+	}
+	return false
+}
@@ -343,3 +343,134 @@ func TestRenameStack(t *testing.T) {
 		assert.Len(t, LocateResource(snap, updatedResourceURN), 1)
 	})
 }
+
+func TestMoveResources(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	c := NewResource("c", pA)
+	c.Parent = b.URN
+	d := NewResource("d", pA)
+	source := NewSnapshot([]*resource.State{
+		pA,
+		a,
+		b,
+		c,
+		d,
+	})
+	dest := NewSnapshot(nil)
+
+	moved, err := MoveResources(source, dest, []resource.URN{a.URN}, "dest", "proj")
+	assert.NoError(t, err)
+	assert.Len(t, moved, 3)
+
+	// The provider stays in the source stack, since d still uses it.
+	assert.Equal(t, []*resource.State{pA, d}, source.Resources)
+
+	// The provider is carried over, and a's dependents are moved along with it.
+	assert.Len(t, dest.Resources, 4)
+	rewrite := func(u resource.URN) resource.URN {
+		return resource.NewURN("dest", "proj", "", u.QualifiedType(), u.Name())
+	}
+	for i, urn := range []resource.URN{pA.URN, a.URN, b.URN, c.URN} {
+		assert.Equal(t, rewrite(urn), dest.Resources[i].URN)
+	}
+	assert.Equal(t, []resource.URN{rewrite(a.URN)}, dest.Resources[2].Dependencies)
+	assert.Equal(t, rewrite(b.URN), dest.Resources[3].Parent)
+
+	ref, err := providers.ParseReference(dest.Resources[1].Provider)
+	assert.NoError(t, err)
+	assert.Equal(t, rewrite(pA.URN), ref.URN())
+	assert.Equal(t, pA.ID, ref.ID())
+
+	assert.NoError(t, source.VerifyIntegrity())
+	assert.NoError(t, dest.VerifyIntegrity())
+}
+
+func TestMoveResourcesReparentsToDestinationRoot(t *testing.T) {
+	t.Parallel()
+
+	root := &resource.State{
+		Type: resource.RootStackType,
+		URN:  resource.DefaultRootStackURN("test", "test"),
+	}
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	a.Parent = root.URN
+	source := NewSnapshot([]*resource.State{
+		root,
+		pA,
+		a,
+	})
+
+	destRoot := &resource.State{
+		Type: resource.RootStackType,
+		URN:  resource.DefaultRootStackURN("dest", "test"),
+	}
+	destProvider := NewProviderResource("a", "p1", "0")
+	destProvider.URN = resource.NewURN("dest", "test", "", destProvider.Type, "p1")
+	dest := NewSnapshot([]*resource.State{
+		destRoot,
+		destProvider,
+	})
+
+	moved, err := MoveResources(source, dest, []resource.URN{a.URN}, "dest", "test")
+	assert.NoError(t, err)
+	assert.Len(t, moved, 1)
+	assert.Equal(t, destRoot.URN, moved[0].Parent)
+
+	// The identical provider that already exists in the destination is reused rather than duplicated.
+	assert.Len(t, dest.Resources, 3)
+	assert.Equal(t, []*resource.State{root, pA}, source.Resources)
+	assert.NoError(t, dest.VerifyIntegrity())
+}
+
+func TestFailedMoveDanglingDependency(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	source := NewSnapshot([]*resource.State{
+		pA,
+		a,
+		b,
+	})
+	dest := NewSnapshot(nil)
+
+	_, err := MoveResources(source, dest, []resource.URN{b.URN}, "dest", "test")
+	assert.Error(t, err)
+	depErr, ok := err.(ResourceHasDanglingDependenciesError)
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	assert.Equal(t, b, depErr.Resource)
+	assert.Equal(t, []resource.URN{a.URN}, depErr.Dependencies)
+
+	// Neither snapshot was modified.
+	assert.Equal(t, []*resource.State{pA, a, b}, source.Resources)
+	assert.Len(t, dest.Resources, 0)
+}
+
+func TestFailedMoveConflict(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	source := NewSnapshot([]*resource.State{
+		pA,
+		a,
+	})
+	dest := NewSnapshot([]*resource.State{
+		NewProviderResource("a", "p1", "0"),
+		NewResource("a", nil),
+	})
+
+	_, err := MoveResources(source, dest, []resource.URN{a.URN}, "test", "test")
+	assert.Error(t, err)
+	_, ok := err.(ResourceAlreadyExistsError)
+	assert.True(t, ok)
+	assert.Equal(t, []*resource.State{pA, a}, source.Resources)
+}
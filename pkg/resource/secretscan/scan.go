@@ -0,0 +1,199 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secretscan finds plaintext values in the state of resources that look like they should have been secrets,
+// such as values that match the patterns of well-known credentials, high-entropy strings, and the values of secret
+// configuration keys.
+package secretscan
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+)
+
+// Finding is a plaintext value in the state of a resource that looks like it should have been a secret.
+type Finding struct {
+	// URN is the URN of the resource.
+	URN resource.URN
+	// Output is true if the value is one of the resource's outputs, and false if it is one of its inputs.
+	Output bool
+	// Property is the path of the value within the resource's inputs or outputs.
+	Property resource.PropertyPath
+	// Reason describes why the value looks like a secret.
+	Reason string
+}
+
+// credentialPatterns match the values of well-known kinds of credentials.
+var credentialPatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"an AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"a private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"a GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"a Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{"a Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"a Stripe secret key", regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+	{"a Pulumi access token", regexp.MustCompile(`\bpul-[0-9a-f]{40}\b`)},
+	{"a JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"a URL with a password", regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:[^/\s:@]+@`)},
+}
+
+// tokenPattern matches the substrings of a value that are checked for high entropy. Dashes and underscores separate
+// tokens, as they join the words and random suffixes of resource names and IDs. Tokens that are longer than the
+// longest credentials are usually encoded data, such as scripts or certificates, and are not checked.
+var tokenPattern = regexp.MustCompile(`[A-Za-z0-9+/=]{20,}`)
+
+// maxTokenLength is the length of the longest tokens that are checked for high entropy.
+const maxTokenLength = 128
+
+// minConfigValueLength is the length of the shortest secret configuration values that are looked for. Shorter
+// values are found in too many unrelated strings.
+const minConfigValueLength = 6
+
+// Scanner finds plaintext values that look like secrets.
+type Scanner struct {
+	secretConfig []secretConfigValue
+}
+
+type secretConfigValue struct {
+	key   config.Key
+	value string
+}
+
+// NewScanner creates a scanner that, in addition to well-known credentials and high-entropy strings, finds the
+// plaintext values of the given secret configuration keys.
+func NewScanner(secretConfig map[config.Key]string) *Scanner {
+	s := &Scanner{}
+	for k, v := range secretConfig {
+		if len(v) >= minConfigValueLength {
+			s.secretConfig = append(s.secretConfig, secretConfigValue{key: k, value: v})
+		}
+	}
+	sort.Slice(s.secretConfig, func(i, j int) bool {
+		return s.secretConfig[i].key.String() < s.secretConfig[j].key.String()
+	})
+	return s
+}
+
+// ScanSnapshot returns the plaintext values in the inputs and outputs of a snapshot's resources that look like
+// secrets.
+func (s *Scanner) ScanSnapshot(snap *deploy.Snapshot) []Finding {
+	if snap == nil {
+		return nil
+	}
+	var findings []Finding
+	for _, res := range snap.Resources {
+		findings = append(findings, s.ScanResource(res)...)
+	}
+	return findings
+}
+
+// ScanResource returns the plaintext values in the inputs and outputs of a resource that look like secrets.
+func (s *Scanner) ScanResource(res *resource.State) []Finding {
+	var findings []Finding
+	for _, output := range []bool{false, true} {
+		props := res.Inputs
+		if output {
+			props = res.Outputs
+		}
+		s.scanObject(nil, props, func(path resource.PropertyPath, reason string) {
+			findings = append(findings, Finding{URN: res.URN, Output: output, Property: path, Reason: reason})
+		})
+	}
+	return findings
+}
+
+func (s *Scanner) scanObject(path resource.PropertyPath, props resource.PropertyMap,
+	report func(resource.PropertyPath, string)) {
+
+	for _, k := range props.StableKeys() {
+		s.scanValue(append(path[:len(path):len(path)], string(k)), props[k], report)
+	}
+}
+
+func (s *Scanner) scanValue(path resource.PropertyPath, v resource.PropertyValue,
+	report func(resource.PropertyPath, string)) {
+
+	switch {
+	case v.IsString():
+		if reason, ok := s.Check(v.StringValue()); ok {
+			report(path, reason)
+		}
+	case v.IsArray():
+		for i, elem := range v.ArrayValue() {
+			s.scanValue(append(path[:len(path):len(path)], i), elem, report)
+		}
+	case v.IsObject():
+		s.scanObject(path, v.ObjectValue(), report)
+	case v.IsOutput():
+		if output := v.OutputValue(); output.Known && !output.Secret {
+			s.scanValue(path, output.Element, report)
+		}
+	}
+	// Secrets are what plaintext values should have been, and other kinds of values cannot hold credentials.
+}
+
+// Check returns a description of why a plaintext string looks like a secret, if it does.
+func (s *Scanner) Check(value string) (string, bool) {
+	for _, c := range s.secretConfig {
+		if strings.Contains(value, c.value) {
+			return fmt.Sprintf("contains the value of the secret configuration key %s", c.key), true
+		}
+	}
+	for _, c := range credentialPatterns {
+		if c.pattern.MatchString(value) {
+			return fmt.Sprintf("looks like %s", c.name), true
+		}
+	}
+	for _, token := range tokenPattern.FindAllString(value, -1) {
+		if len(token) <= maxTokenLength && isHighEntropy(token) {
+			return "is a high-entropy string", true
+		}
+	}
+	return "", false
+}
+
+// isHighEntropy returns true if a token looks random. Such tokens mix upper case letters, lower case letters and
+// digits, and have nearly as much entropy per character as a random string of their length. Identifiers that are
+// made of words, and hexadecimal strings such as hashes and UUIDs, do not qualify.
+func isHighEntropy(token string) bool {
+	var upper, lower, digit bool
+	counts := make(map[rune]int)
+	for _, r := range token {
+		upper = upper || unicode.IsUpper(r)
+		lower = lower || unicode.IsLower(r)
+		digit = digit || unicode.IsDigit(r)
+		counts[r]++
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+
+	n := float64(len(token))
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= math.Min(0.85*math.Log2(n), 4.5)
+}
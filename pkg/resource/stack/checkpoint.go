@@ -27,14 +27,19 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
 
+// UnmarshalVersionedCheckpointToLatestCheckpoint unmarshals a versioned checkpoint and migrates it to the latest
+// version. Gzip-compressed checkpoints are decompressed transparently.
 func UnmarshalVersionedCheckpointToLatestCheckpoint(bytes []byte) (*apitype.CheckpointV3, error) {
+	if IsCompressed(bytes) {
+		return decodeCompressedCheckpoint(bytes)
+	}
+
 	var versionedCheckpoint apitype.VersionedCheckpoint
 	if err := json.Unmarshal(bytes, &versionedCheckpoint); err != nil {
 		return nil, err
 	}
 
-	switch versionedCheckpoint.Version {
-	case 0:
+	if versionedCheckpoint.Version == 0 {
 		// The happens when we are loading a checkpoint file from before we started to version things. Go's
 		// json package did not support strict marshalling before 1.10, and we use 1.9 in our toolchain today.
 		// After we upgrade, we could consider rewriting this code to use DisallowUnknownFields() on the decoder
@@ -47,9 +52,17 @@ func UnmarshalVersionedCheckpointToLatestCheckpoint(bytes []byte) (*apitype.Chec
 		v2checkpoint := migrate.UpToCheckpointV2(v1checkpoint)
 		v3checkpoint := migrate.UpToCheckpointV3(v2checkpoint)
 		return &v3checkpoint, nil
+	}
+	return unmarshalCheckpointToLatest(versionedCheckpoint.Version, versionedCheckpoint.Checkpoint)
+}
+
+// unmarshalCheckpointToLatest unmarshals the checkpoint held by a versioned checkpoint of the given version, which is
+// at least 1, and migrates it to the latest version.
+func unmarshalCheckpointToLatest(version int, checkpoint json.RawMessage) (*apitype.CheckpointV3, error) {
+	switch version {
 	case 1:
 		var v1checkpoint apitype.CheckpointV1
-		if err := json.Unmarshal(versionedCheckpoint.Checkpoint, &v1checkpoint); err != nil {
+		if err := json.Unmarshal(checkpoint, &v1checkpoint); err != nil {
 			return nil, err
 		}
 
@@ -58,7 +71,7 @@ func UnmarshalVersionedCheckpointToLatestCheckpoint(bytes []byte) (*apitype.Chec
 		return &v3checkpoint, nil
 	case 2:
 		var v2checkpoint apitype.CheckpointV2
-		if err := json.Unmarshal(versionedCheckpoint.Checkpoint, &v2checkpoint); err != nil {
+		if err := json.Unmarshal(checkpoint, &v2checkpoint); err != nil {
 			return nil, err
 		}
 
@@ -66,13 +79,13 @@ func UnmarshalVersionedCheckpointToLatestCheckpoint(bytes []byte) (*apitype.Chec
 		return &v3checkpoint, nil
 	case 3:
 		var v3checkpoint apitype.CheckpointV3
-		if err := json.Unmarshal(versionedCheckpoint.Checkpoint, &v3checkpoint); err != nil {
+		if err := json.Unmarshal(checkpoint, &v3checkpoint); err != nil {
 			return nil, err
 		}
 
 		return &v3checkpoint, nil
 	default:
-		return nil, fmt.Errorf("unsupported checkpoint version %d", versionedCheckpoint.Version)
+		return nil, fmt.Errorf("unsupported checkpoint version %d", version)
 	}
 }
 
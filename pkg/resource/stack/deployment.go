@@ -109,15 +109,9 @@ func SerializeDeployment(snap *deploy.Snapshot, sm secrets.Manager, showSecrets
 		sm = snap.SecretsManager
 	}
 
-	var enc config.Encrypter
-	if sm != nil {
-		e, err := sm.Encrypter()
-		if err != nil {
-			return nil, fmt.Errorf("getting encrypter for deployment: %w", err)
-		}
-		enc = e
-	} else {
-		enc = config.NewPanicCrypter()
+	enc, err := getEncrypter(sm)
+	if err != nil {
+		return nil, err
 	}
 
 	// Serialize all vertices and only include a vertex section if non-empty.
@@ -161,6 +155,18 @@ func SerializeDeployment(snap *deploy.Snapshot, sm secrets.Manager, showSecrets
 	}, nil
 }
 
+// getEncrypter returns the encrypter of the given secrets manager, or one that panics if the manager is nil.
+func getEncrypter(sm secrets.Manager) (config.Encrypter, error) {
+	if sm == nil {
+		return config.NewPanicCrypter(), nil
+	}
+	enc, err := sm.Encrypter()
+	if err != nil {
+		return nil, fmt.Errorf("getting encrypter for deployment: %w", err)
+	}
+	return enc, nil
+}
+
 // DeserializeUntypedDeployment deserializes an untyped deployment and produces a `deploy.Snapshot`
 // from it. DeserializeDeployment will return an error if the untyped deployment's version is
 // not within the range `DeploymentSchemaVersionCurrent` and `DeploymentSchemaVersionOldestSupported`.
@@ -209,17 +215,9 @@ func DeserializeDeploymentV3(deployment apitype.DeploymentV3, secretsProv Secret
 		return nil, err
 	}
 
-	var secretsManager secrets.Manager
-	if deployment.SecretsProviders != nil && deployment.SecretsProviders.Type != "" {
-		if secretsProv == nil {
-			return nil, errors.New("deployment uses a SecretsProvider but no SecretsProvider was provided")
-		}
-
-		sm, err := secretsProv.OfType(deployment.SecretsProviders.Type, deployment.SecretsProviders.State)
-		if err != nil {
-			return nil, err
-		}
-		secretsManager = sm
+	secretsManager, err := deploymentSecretsManager(deployment.SecretsProviders, secretsProv)
+	if err != nil {
+		return nil, err
 	}
 
 	var dec config.Decrypter
@@ -279,6 +277,20 @@ func DeserializeDeploymentV3(deployment apitype.DeploymentV3, secretsProv Secret
 	return deploy.NewSnapshot(*manifest, secretsManager, resources, ops), nil
 }
 
+// deploymentSecretsManager returns the secrets manager that a deployment's secrets providers describe, or nil if the
+// deployment has none.
+func deploymentSecretsManager(providers *apitype.SecretsProvidersV1,
+	secretsProv SecretsProvider) (secrets.Manager, error) {
+
+	if providers == nil || providers.Type == "" {
+		return nil, nil
+	}
+	if secretsProv == nil {
+		return nil, errors.New("deployment uses a SecretsProvider but no SecretsProvider was provided")
+	}
+	return secretsProv.OfType(providers.Type, providers.State)
+}
+
 // SerializeResource turns a resource into a structure suitable for serialization.
 func SerializeResource(res *resource.State, enc config.Encrypter, showSecrets bool) (apitype.ResourceV3, error) {
 	contract.Assert(res != nil)
@@ -1,10 +1,20 @@
 package stack
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+var (
+	// ErrPlanSchemaVersionTooNew is returned from `DeserializeUntypedPlan` if the plan being deserialized is too new
+	// to understand.
+	ErrPlanSchemaVersionTooNew = fmt.Errorf("this plan's version is too new")
 )
 
 func SerializePlanDiff(
@@ -143,19 +153,42 @@ func DeserializeResourcePlan(
 	dec config.Decrypter,
 	enc config.Encrypter) (*deploy.ResourcePlan, error) {
 
-	checkedInputs, err := DeserializeProperties(plan.Goal.CheckedInputs, dec, enc)
-	if err != nil {
-		return nil, err
-	}
+	var goal *deploy.GoalPlan
+	if plan.Goal != nil {
+		checkedInputs, err := DeserializeProperties(plan.Goal.CheckedInputs, dec, enc)
+		if err != nil {
+			return nil, err
+		}
 
-	inputDiff, err := DeserializePlanDiff(plan.Goal.InputDiff, dec, enc)
-	if err != nil {
-		return nil, err
-	}
+		inputDiff, err := DeserializePlanDiff(plan.Goal.InputDiff, dec, enc)
+		if err != nil {
+			return nil, err
+		}
 
-	outputDiff, err := DeserializePlanDiff(plan.Goal.OutputDiff, dec, enc)
-	if err != nil {
-		return nil, err
+		outputDiff, err := DeserializePlanDiff(plan.Goal.OutputDiff, dec, enc)
+		if err != nil {
+			return nil, err
+		}
+
+		goal = &deploy.GoalPlan{
+			Type:                    plan.Goal.Type,
+			Name:                    plan.Goal.Name,
+			Custom:                  plan.Goal.Custom,
+			CheckedInputs:           checkedInputs,
+			InputDiff:               inputDiff,
+			OutputDiff:              outputDiff,
+			Parent:                  plan.Goal.Parent,
+			Protect:                 plan.Goal.Protect,
+			Dependencies:            plan.Goal.Dependencies,
+			Provider:                plan.Goal.Provider,
+			PropertyDependencies:    plan.Goal.PropertyDependencies,
+			DeleteBeforeReplace:     plan.Goal.DeleteBeforeReplace,
+			IgnoreChanges:           plan.Goal.IgnoreChanges,
+			AdditionalSecretOutputs: plan.Goal.AdditionalSecretOutputs,
+			Aliases:                 plan.Goal.Aliases,
+			ID:                      plan.Goal.ID,
+			CustomTimeouts:          plan.Goal.CustomTimeouts,
+		}
 	}
 
 	var outputs resource.PropertyMap
@@ -167,26 +200,6 @@ func DeserializeResourcePlan(
 		outputs = outs
 	}
 
-	goal := &deploy.GoalPlan{
-		Type:                    plan.Goal.Type,
-		Name:                    plan.Goal.Name,
-		Custom:                  plan.Goal.Custom,
-		CheckedInputs:           checkedInputs,
-		InputDiff:               inputDiff,
-		OutputDiff:              outputDiff,
-		Parent:                  plan.Goal.Parent,
-		Protect:                 plan.Goal.Protect,
-		Dependencies:            plan.Goal.Dependencies,
-		Provider:                plan.Goal.Provider,
-		PropertyDependencies:    plan.Goal.PropertyDependencies,
-		DeleteBeforeReplace:     plan.Goal.DeleteBeforeReplace,
-		IgnoreChanges:           plan.Goal.IgnoreChanges,
-		AdditionalSecretOutputs: plan.Goal.AdditionalSecretOutputs,
-		Aliases:                 plan.Goal.Aliases,
-		ID:                      plan.Goal.ID,
-		CustomTimeouts:          plan.Goal.CustomTimeouts,
-	}
-
 	ops := make([]deploy.StepOp, len(plan.Steps))
 	for i, op := range plan.Steps {
 		ops[i] = deploy.StepOp(op)
@@ -219,3 +232,41 @@ func DeserializePlan(plan apitype.DeploymentPlanV1, dec config.Decrypter, enc co
 	}
 	return deserializedPlan, nil
 }
+
+// SerializeUntypedPlan serializes a plan into a versioned plan document, suitable for writing to a plan file.
+func SerializeUntypedPlan(
+	plan *deploy.Plan, enc config.Encrypter, showSecrets bool) (*apitype.VersionedDeploymentPlan, error) {
+
+	deploymentPlan, err := SerializePlan(plan, enc, showSecrets)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := json.Marshal(deploymentPlan)
+	if err != nil {
+		return nil, err
+	}
+	return &apitype.VersionedDeploymentPlan{
+		Version: apitype.DeploymentPlanSchemaVersionCurrent,
+		Plan:    bytes,
+	}, nil
+}
+
+// DeserializeUntypedPlan deserializes a versioned plan document into a plan. Plans written by newer versions of the
+// CLI are rejected with `ErrPlanSchemaVersionTooNew`.
+func DeserializeUntypedPlan(
+	plan *apitype.VersionedDeploymentPlan, dec config.Decrypter, enc config.Encrypter) (*deploy.Plan, error) {
+
+	contract.Require(plan != nil, "plan")
+	switch {
+	case plan.Version > apitype.DeploymentPlanSchemaVersionCurrent:
+		return nil, ErrPlanSchemaVersionTooNew
+	case plan.Version < 1:
+		return nil, fmt.Errorf("unsupported plan version %d", plan.Version)
+	}
+
+	var v1plan apitype.DeploymentPlanV1
+	if err := json.Unmarshal(plan.Plan, &v1plan); err != nil {
+		return nil, err
+	}
+	return DeserializePlan(v1plan, dec, enc)
+}
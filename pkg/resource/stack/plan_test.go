@@ -0,0 +1,78 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+)
+
+func TestPlanSerialization(t *testing.T) {
+	t.Parallel()
+
+	created := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resA")
+	deleted := resource.URN("urn:pulumi:test::test::pkgA:m:typA::resB")
+
+	plan := deploy.NewPlan(config.Map{})
+	plan.ResourcePlans[created] = &deploy.ResourcePlan{
+		Goal: &deploy.GoalPlan{
+			Type:          "pkgA:m:typA",
+			Name:          "resA",
+			Custom:        true,
+			CheckedInputs: resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "bar"}),
+		},
+		Ops: []deploy.StepOp{deploy.OpCreate},
+	}
+	// Resources that are planned to be deleted have no goal.
+	plan.ResourcePlans[deleted] = &deploy.ResourcePlan{
+		Ops: []deploy.StepOp{deploy.OpDelete},
+	}
+
+	untypedPlan, err := SerializeUntypedPlan(&plan, config.NopEncrypter, false /*showSecrets*/)
+	require.NoError(t, err)
+	assert.Equal(t, apitype.DeploymentPlanSchemaVersionCurrent, untypedPlan.Version)
+
+	deserialized, err := DeserializeUntypedPlan(untypedPlan, config.NopDecrypter, config.NopEncrypter)
+	require.NoError(t, err)
+	require.Len(t, deserialized.ResourcePlans, 2)
+
+	createPlan := deserialized.ResourcePlans[created]
+	require.NotNil(t, createPlan.Goal)
+	assert.Equal(t, []deploy.StepOp{deploy.OpCreate}, createPlan.Ops)
+	assert.Equal(t, resource.NewStringProperty("bar"), createPlan.Goal.CheckedInputs["foo"])
+
+	deletePlan := deserialized.ResourcePlans[deleted]
+	assert.Nil(t, deletePlan.Goal)
+	assert.Equal(t, []deploy.StepOp{deploy.OpDelete}, deletePlan.Ops)
+}
+
+func TestLoadTooNewPlan(t *testing.T) {
+	t.Parallel()
+
+	untypedPlan := &apitype.VersionedDeploymentPlan{
+		Version: apitype.DeploymentPlanSchemaVersionCurrent + 1,
+	}
+
+	plan, err := DeserializeUntypedPlan(untypedPlan, config.NopDecrypter, config.NopEncrypter)
+	assert.Nil(t, plan)
+	assert.Equal(t, ErrPlanSchemaVersionTooNew, err)
+}
@@ -0,0 +1,556 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype/migrate"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// gzipMagic are the first bytes of every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// IsCompressed returns true if the given checkpoint bytes are gzip-compressed.
+func IsCompressed(data []byte) bool {
+	return bytes.HasPrefix(data, gzipMagic)
+}
+
+func decodeCompressedCheckpoint(data []byte) (*apitype.CheckpointV3, error) {
+	return DecodeCheckpoint(bytes.NewReader(data))
+}
+
+// indent is the indentation used by encoding.JSON, which checkpoints have always been written with.
+const indent = "    "
+
+// checkpointEncoder writes JSON to a writer, remembering the first error it encounters.
+type checkpointEncoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *checkpointEncoder) raw(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+// field writes a key and value of an object nested depth levels deep.
+func (e *checkpointEncoder) field(depth int, first bool, key string, v interface{}) {
+	if !first {
+		e.raw(",")
+	}
+	e.raw("\n" + strings.Repeat(indent, depth) + fmt.Sprintf("%q: ", key))
+	e.value(depth, v)
+}
+
+// value writes a value nested depth levels deep.
+func (e *checkpointEncoder) value(depth int, v interface{}) {
+	if e.err != nil {
+		return
+	}
+	b, err := json.MarshalIndent(v, strings.Repeat(indent, depth), indent)
+	if err != nil {
+		e.err = err
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+// EncodeCheckpoint writes a snapshot to w as a versioned checkpoint. The output is the same as that of marshaling the
+// result of SerializeCheckpoint with encoding.JSON, but the snapshot's resources are serialized and written one at a
+// time, so that neither the serialized checkpoint nor its JSON is ever held in memory in its entirety.
+func EncodeCheckpoint(w io.Writer, stack tokens.Name, snap *deploy.Snapshot,
+	sm secrets.Manager, showSecrets bool) error {
+
+	e := &checkpointEncoder{w: bufio.NewWriter(w)}
+	e.raw("{")
+	e.field(1, true, "version", apitype.DeploymentSchemaVersionCurrent)
+	e.raw(",\n" + indent + `"checkpoint": {`)
+	e.field(2, true, "stack", stack.Q())
+
+	if snap != nil {
+		// Serialize everything but the resources up front. These are small, and this picks the secrets manager and
+		// encrypter to use in the same way as SerializeDeployment.
+		header := *snap
+		header.Resources = nil
+		dep, err := SerializeDeployment(&header, sm, showSecrets)
+		if err != nil {
+			return fmt.Errorf("serializing deployment: %w", err)
+		}
+		if sm == nil {
+			sm = snap.SecretsManager
+		}
+		enc, err := getEncrypter(sm)
+		if err != nil {
+			return fmt.Errorf("serializing deployment: %w", err)
+		}
+
+		e.raw(",\n" + strings.Repeat(indent, 2) + `"latest": {`)
+		e.field(3, true, "manifest", dep.Manifest)
+		if dep.SecretsProviders != nil {
+			e.field(3, false, "secrets_providers", dep.SecretsProviders)
+		}
+		if len(snap.Resources) > 0 {
+			e.raw(",\n" + strings.Repeat(indent, 3) + `"resources": [`)
+			for i, res := range snap.Resources {
+				sres, err := SerializeResource(res, enc, showSecrets)
+				if err != nil {
+					return fmt.Errorf("serializing deployment: serializing resources: %w", err)
+				}
+				if i > 0 {
+					e.raw(",")
+				}
+				e.raw("\n" + strings.Repeat(indent, 4))
+				e.value(4, sres)
+			}
+			e.raw("\n" + strings.Repeat(indent, 3) + "]")
+		}
+		if len(dep.PendingOperations) > 0 {
+			e.field(3, false, "pending_operations", dep.PendingOperations)
+		}
+		e.raw("\n" + strings.Repeat(indent, 2) + "}")
+	}
+
+	e.raw("\n" + indent + "}\n}")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// DecodeCheckpoint reads a versioned checkpoint from r and migrates it to the latest version, as
+// UnmarshalVersionedCheckpointToLatestCheckpoint does. Gzip-compressed checkpoints are detected and decompressed
+// transparently. The resources of checkpoints of the current version are decoded one at a time as they are read, so
+// that the checkpoint's JSON is never held in memory in its entirety.
+func DecodeCheckpoint(r io.Reader) (*apitype.CheckpointV3, error) {
+	chk, _, err := decodeCheckpoint(r, nil)
+	return chk, err
+}
+
+// DecodeSnapshot reads a versioned checkpoint from r, as DecodeCheckpoint does, and deserializes the snapshot of its
+// latest deployment, or returns nil if it has none. The resources of checkpoints of the current version are
+// deserialized one at a time as they are read, and each serialized resource is dropped once it has been deserialized,
+// so that the checkpoint is never held in memory alongside the snapshot. The secrets of each resource are decrypted
+// together.
+func DecodeSnapshot(r io.Reader, secretsProv SecretsProvider) (*deploy.Snapshot, error) {
+	chk, snap, err := decodeCheckpoint(r, secretsProv)
+	if err != nil || snap != nil || chk.Latest == nil {
+		return snap, err
+	}
+	// Checkpoints of older versions, and those that do not declare their version first, are migrated as a whole.
+	return DeserializeDeploymentV3(*chk.Latest, secretsProv)
+}
+
+// decodeCheckpoint reads a versioned checkpoint from r and migrates it to the latest version. If secretsProv is not
+// nil, the latest deployment of a checkpoint of the current version is deserialized into a snapshot as it is read,
+// instead of being returned as part of the checkpoint.
+func decodeCheckpoint(r io.Reader, secretsProv SecretsProvider) (*apitype.CheckpointV3, *deploy.Snapshot, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decompressing checkpoint: %w", err)
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+
+	dec := json.NewDecoder(br)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, nil, err
+	}
+
+	// Only the checkpoints of the current version that declare their version before their contents are streamed.
+	// Other checkpoints are kept and migrated once they have been read. Checkpoints from before versioning have no
+	// version, and their fields are those of the checkpoint itself.
+	version := 0
+	var checkpoint json.RawMessage
+	unversioned := make(map[string]json.RawMessage)
+	var chk *apitype.CheckpointV3
+	var snap *deploy.Snapshot
+	for dec.More() {
+		key, err := decodeKey(dec)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case key == "version":
+			err = dec.Decode(&version)
+		case key == "checkpoint" && version == apitype.DeploymentSchemaVersionCurrent:
+			chk, snap, err = decodeCheckpointV3(dec, secretsProv)
+		case key == "checkpoint":
+			err = dec.Decode(&checkpoint)
+		default:
+			var raw json.RawMessage
+			err = dec.Decode(&raw)
+			unversioned[key] = raw
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case chk != nil:
+		return chk, snap, nil
+	case version == 0:
+		if checkpoint != nil {
+			unversioned["checkpoint"] = checkpoint
+		}
+		var v1checkpoint apitype.CheckpointV1
+		if err := unmarshalFields(unversioned, &v1checkpoint); err != nil {
+			return nil, nil, err
+		}
+		v3checkpoint := migrate.UpToCheckpointV3(migrate.UpToCheckpointV2(v1checkpoint))
+		return &v3checkpoint, nil, nil
+	default:
+		chk, err := unmarshalCheckpointToLatest(version, checkpoint)
+		return chk, nil, err
+	}
+}
+
+// decodeCheckpointV3 decodes a CheckpointV3, streaming the resources of its latest deployment. If secretsProv is not
+// nil, the latest deployment is deserialized into a snapshot instead of being returned as part of the checkpoint.
+func decodeCheckpointV3(dec *json.Decoder,
+	secretsProv SecretsProvider) (*apitype.CheckpointV3, *deploy.Snapshot, error) {
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, nil, err
+	}
+
+	var chk apitype.CheckpointV3
+	var snap *deploy.Snapshot
+	for dec.More() {
+		key, err := decodeKey(dec)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case key == "stack":
+			err = dec.Decode(&chk.Stack)
+		case key == "config":
+			err = dec.Decode(&chk.Config)
+		case key == "latest" && secretsProv == nil:
+			chk.Latest, err = decodeDeploymentV3(dec)
+		case key == "latest":
+			snap, err = decodeSnapshotV3(dec, secretsProv)
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, nil, err
+	}
+	return &chk, snap, nil
+}
+
+// decodeDeploymentV3 decodes a DeploymentV3, or null, decoding its resources one at a time.
+func decodeDeploymentV3(dec *json.Decoder) (*apitype.DeploymentV3, error) {
+	if isNull, err := expectObjectOrNull(dec); err != nil || isNull {
+		return nil, err
+	}
+
+	var dep apitype.DeploymentV3
+	for dec.More() {
+		key, err := decodeKey(dec)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "manifest":
+			err = dec.Decode(&dep.Manifest)
+		case "secrets_providers":
+			err = dec.Decode(&dep.SecretsProviders)
+		case "pending_operations":
+			err = dec.Decode(&dep.PendingOperations)
+		case "resources":
+			err = decodeResources(dec, func(res apitype.ResourceV3) error {
+				dep.Resources = append(dep.Resources, res)
+				return nil
+			})
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	return &dep, nil
+}
+
+// decodeSnapshotV3 decodes a DeploymentV3, or null, into a snapshot, deserializing its resources one at a time.
+// Checkpoints are written with their secrets providers before their resources. Resources with secrets that come
+// before the secrets providers are kept until the providers have been read.
+func decodeSnapshotV3(dec *json.Decoder, secretsProv SecretsProvider) (*deploy.Snapshot, error) {
+	if isNull, err := expectObjectOrNull(dec); err != nil || isNull {
+		return nil, err
+	}
+
+	var manifest apitype.ManifestV1
+	var pendingOperations []apitype.OperationV2
+	var resources []*resource.State
+	deferred := make(map[int]apitype.ResourceV3)
+	var crypter *snapshotCrypter
+	for dec.More() {
+		key, err := decodeKey(dec)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "manifest":
+			err = dec.Decode(&manifest)
+		case "secrets_providers":
+			var providers *apitype.SecretsProvidersV1
+			if err = dec.Decode(&providers); err == nil {
+				crypter, err = newSnapshotCrypter(providers, secretsProv)
+			}
+		case "pending_operations":
+			err = dec.Decode(&pendingOperations)
+		case "resources":
+			err = decodeResources(dec, func(res apitype.ResourceV3) error {
+				if crypter == nil && (containsSecrets(res.Inputs) || containsSecrets(res.Outputs)) {
+					deferred[len(resources)] = res
+					resources = append(resources, nil)
+					return nil
+				}
+				state, err := crypter.deserializeResource(res)
+				if err != nil {
+					return err
+				}
+				resources = append(resources, state)
+				return nil
+			})
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+
+	if crypter == nil {
+		var err error
+		if crypter, err = newSnapshotCrypter(nil, secretsProv); err != nil {
+			return nil, err
+		}
+	}
+	for i, res := range deferred {
+		state, err := crypter.deserializeResource(res)
+		if err != nil {
+			return nil, err
+		}
+		resources[i] = state
+	}
+	var ops []resource.Operation
+	for _, op := range pendingOperations {
+		desop, err := crypter.deserializeOperation(op)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, desop)
+	}
+
+	m, err := deploy.DeserializeManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return deploy.NewSnapshot(*m, crypter.sm, resources, ops), nil
+}
+
+// snapshotCrypter decrypts and encrypts the secrets of the resources of a snapshot as they are deserialized.
+type snapshotCrypter struct {
+	sm  secrets.Manager
+	dec *mapDecrypter
+	enc config.Encrypter
+}
+
+func newSnapshotCrypter(providers *apitype.SecretsProvidersV1,
+	secretsProv SecretsProvider) (*snapshotCrypter, error) {
+
+	sm, err := deploymentSecretsManager(providers, secretsProv)
+	if err != nil {
+		return nil, err
+	}
+	if sm == nil {
+		return &snapshotCrypter{
+			dec: &mapDecrypter{decrypter: config.NewPanicCrypter()},
+			enc: config.NewPanicCrypter(),
+		}, nil
+	}
+
+	dec, err := sm.Decrypter()
+	if err != nil {
+		return nil, err
+	}
+	enc, err := sm.Encrypter()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotCrypter{sm: sm, dec: &mapDecrypter{decrypter: dec}, enc: enc}, nil
+}
+
+// deserializeResource deserializes a resource, decrypting all of its secrets at once. A nil crypter is used for
+// resources without secrets that are read before the snapshot's secrets providers.
+func (c *snapshotCrypter) deserializeResource(res apitype.ResourceV3) (*resource.State, error) {
+	if c == nil {
+		panicCrypter := config.NewPanicCrypter()
+		return DeserializeResource(res, panicCrypter, panicCrypter)
+	}
+
+	var ciphertexts []string
+	collectCiphertexts(&ciphertexts, res.Inputs)
+	collectCiphertexts(&ciphertexts, res.Outputs)
+	if len(ciphertexts) > 0 {
+		plaintexts, err := config.BulkDecrypt(c.dec.decrypter, ciphertexts)
+		if err != nil {
+			return nil, err
+		}
+		if c.dec.cache == nil {
+			c.dec.cache = make(map[string]string, len(plaintexts))
+		}
+		for ciphertext, plaintext := range plaintexts {
+			c.dec.cache[ciphertext] = plaintext
+		}
+	}
+	return DeserializeResource(res, c.dec, c.enc)
+}
+
+func (c *snapshotCrypter) deserializeOperation(op apitype.OperationV2) (resource.Operation, error) {
+	return DeserializeOperation(op, c.dec, c.enc)
+}
+
+// containsSecrets returns true if a serialized property value holds a secret, whether encrypted or not.
+func containsSecrets(prop interface{}) bool {
+	switch prop := prop.(type) {
+	case []interface{}:
+		for _, v := range prop {
+			if containsSecrets(v) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		if prop[resource.SigKey] == resource.SecretSig {
+			return true
+		}
+		for _, v := range prop {
+			if containsSecrets(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeResources decodes a list of resources, or null, passing each resource to f as soon as it has been decoded.
+func decodeResources(dec *json.Decoder, f func(apitype.ResourceV3) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("expected a list of resources, got %v", tok)
+	}
+	for dec.More() {
+		var res apitype.ResourceV3
+		if err = dec.Decode(&res); err != nil {
+			return err
+		}
+		if err = f(res); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectObjectOrNull reads the start of an object, or null, in which case it returns true.
+func expectObjectOrNull(dec *json.Decoder) (bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+	if tok == nil {
+		return true, nil
+	}
+	if tok != json.Delim('{') {
+		return false, fmt.Errorf("expected an object, got %v", tok)
+	}
+	return false, nil
+}
+
+// skipValue reads a value that is not needed.
+func skipValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
+
+// unmarshalFields unmarshals the fields of a JSON object into v.
+func unmarshalFields(fields map[string]json.RawMessage, v interface{}) error {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func decodeKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("expected an object key, got %v", tok)
+	}
+	return key, nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected '%v', got %v", delim, tok)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+)
+
+func gzipData(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDecodeCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	for _, file := range []string{"checkpoint-v0.json", "checkpoint-v1.json", "checkpoint-v3.json",
+		"checkpoint-secrets.json"} {
+		file := file
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := ioutil.ReadFile("testdata/" + file)
+			require.NoError(t, err)
+			expected, err := UnmarshalVersionedCheckpointToLatestCheckpoint(data)
+			require.NoError(t, err)
+
+			chk, err := DecodeCheckpoint(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, expected, chk)
+
+			// Compressed checkpoints are detected whether they are decoded or unmarshaled.
+			compressed := gzipData(t, data)
+			assert.True(t, IsCompressed(compressed))
+			chk, err = DecodeCheckpoint(bytes.NewReader(compressed))
+			require.NoError(t, err)
+			assert.Equal(t, expected, chk)
+			chk, err = UnmarshalVersionedCheckpointToLatestCheckpoint(compressed)
+			require.NoError(t, err)
+			assert.Equal(t, expected, chk)
+		})
+	}
+}
+
+func TestDecodeSnapshot(t *testing.T) {
+	t.Parallel()
+
+	for _, file := range []string{"checkpoint-v0.json", "checkpoint-v1.json", "checkpoint-v3.json",
+		"checkpoint-secrets.json"} {
+		file := file
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := ioutil.ReadFile("testdata/" + file)
+			require.NoError(t, err)
+			chk, err := UnmarshalVersionedCheckpointToLatestCheckpoint(data)
+			require.NoError(t, err)
+			expected, err := DeserializeCheckpoint(chk)
+			require.NoError(t, err)
+			require.NotEmpty(t, expected.Resources)
+
+			inputs := [][]byte{data, gzipData(t, data)}
+
+			// Marshaling the latest deployment as a map puts its resources before its secrets providers, so that
+			// resources with secrets have to wait for them.
+			var reordered struct {
+				Version    int                    `json:"version"`
+				Checkpoint map[string]interface{} `json:"checkpoint"`
+			}
+			require.NoError(t, json.Unmarshal(data, &reordered))
+			if reordered.Checkpoint != nil {
+				reorderedData, err := json.Marshal(reordered)
+				require.NoError(t, err)
+				inputs = append(inputs, reorderedData)
+			}
+
+			for _, data := range inputs {
+				snap, err := DecodeSnapshot(bytes.NewReader(data), DefaultSecretsProvider)
+				require.NoError(t, err)
+				assert.Equal(t, expected, snap)
+			}
+		})
+	}
+}
+
+func TestEncodeCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	data, err := ioutil.ReadFile("testdata/checkpoint-v3.json")
+	require.NoError(t, err)
+	chk, err := UnmarshalVersionedCheckpointToLatestCheckpoint(data)
+	require.NoError(t, err)
+	snap, err := DeserializeCheckpoint(chk)
+	require.NoError(t, err)
+
+	for _, snap := range []*deploy.Snapshot{snap, {}, nil} {
+		// The streamed checkpoint is identical to the marshaled one.
+		vchk, err := SerializeCheckpoint("dev", snap, nil, false)
+		require.NoError(t, err)
+		expected, err := encoding.JSON.Marshal(vchk)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, EncodeCheckpoint(&buf, "dev", snap, nil, false))
+		assert.Equal(t, string(expected), buf.String())
+
+		expectedChk, err := UnmarshalVersionedCheckpointToLatestCheckpoint(expected)
+		require.NoError(t, err)
+		chk, err := DecodeCheckpoint(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, expectedChk, chk)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package external implements support for secrets managers that delegate encryption to an external program, which
+// is selected with a secrets provider URL of the form exec://<path>.
+//
+// The program is run with a single argument, either "encrypt" or "decrypt". It reads a JSON request of the form
+// {"values": ["..."]} from its standard input, and writes a JSON response of the same form to its standard output
+// holding the encrypted or decrypted values, in the same order. Ciphertexts are opaque to Pulumi. A program that
+// cannot process a request should exit with a non-zero status; anything it writes to its standard error is reported.
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+)
+
+// Type is the type of secrets managed by this secrets provider
+const Type = "exec"
+
+// URLPrefix is the prefix of the secrets provider URLs that select an external program.
+const URLPrefix = "exec://"
+
+var _ config.BulkDecrypter = (*externalCrypter)(nil)
+
+type externalSecretsManagerState struct {
+	URL string `json:"url"`
+}
+
+// IsExternalSecretsProviderURL returns true if the given secrets provider URL selects an external program.
+func IsExternalSecretsProviderURL(url string) bool {
+	return strings.HasPrefix(url, URLPrefix)
+}
+
+// trustedURLs holds the secrets provider URLs whose programs may be run for deserialized deployments. A deployment
+// names the program that encrypted its secrets, so running whatever program a deployment names would let anyone who
+// can write a checkpoint run arbitrary programs on the machines that read it.
+var trustedURLs sync.Map
+
+// Trust allows the program selected by the given secrets provider URL to be run for the deployments that name it. It
+// must only be called with URLs taken from a stack's configuration, never with URLs read from a deployment.
+func Trust(url string) {
+	trustedURLs.Store(url, true)
+}
+
+func isTrusted(url string) bool {
+	_, ok := trustedURLs.Load(url)
+	return ok
+}
+
+// NewExternalSecretsManagerFromState deserializes configuration from state and returns a secrets manager that uses
+// the external program it refers to. The program is only run if its URL has been trusted by a call to Trust or
+// NewExternalSecretsManager, i.e. if it is the secrets provider in a stack's configuration.
+func NewExternalSecretsManagerFromState(state json.RawMessage) (secrets.Manager, error) {
+	var s externalSecretsManagerState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return nil, fmt.Errorf("unmarshalling state: %w", err)
+	}
+	if !isTrusted(s.URL) {
+		return nil, fmt.Errorf("refusing to run the secrets provider %q that the state names, as it is not the "+
+			"secrets provider in the stack's configuration file; set `secretsProvider: %s` in the stack's "+
+			"configuration file if you trust it", s.URL, s.URL)
+	}
+
+	return newExternalSecretsManager(s.URL)
+}
+
+// NewExternalSecretsManager returns a secrets manager that uses the external program selected by the given
+// exec://<path> URL to encrypt and decrypt secret values. A path without a slash is looked up in the PATH. The URL
+// is trusted, so that the deployments whose secrets the program encrypts can be deserialized.
+func NewExternalSecretsManager(url string) (*Manager, error) {
+	m, err := newExternalSecretsManager(url)
+	if err != nil {
+		return nil, err
+	}
+	Trust(url)
+	return m, nil
+}
+
+func newExternalSecretsManager(url string) (*Manager, error) {
+	if !IsExternalSecretsProviderURL(url) {
+		return nil, fmt.Errorf("secrets provider URL %q does not start with %s", url, URLPrefix)
+	}
+	path := strings.TrimPrefix(url, URLPrefix)
+	if path == "" {
+		return nil, fmt.Errorf("secrets provider URL %q does not name a program", url)
+	}
+	command, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("finding secrets provider program: %w", err)
+	}
+
+	return &Manager{
+		crypter: &externalCrypter{command: command},
+		state:   externalSecretsManagerState{URL: url},
+	}, nil
+}
+
+// Manager is the secrets.Manager implementation for external programs
+type Manager struct {
+	state   externalSecretsManagerState
+	crypter *externalCrypter
+}
+
+func (m *Manager) Type() string                         { return Type }
+func (m *Manager) State() interface{}                   { return m.state }
+func (m *Manager) Encrypter() (config.Encrypter, error) { return m.crypter, nil }
+func (m *Manager) Decrypter() (config.Decrypter, error) { return m.crypter, nil }
+
+// externalMessage is the request and response of the external program.
+type externalMessage struct {
+	Values []string `json:"values"`
+}
+
+// externalCrypter is an encrypter/decrypter that runs an external program to encrypt/decrypt values.
+type externalCrypter struct {
+	command string
+}
+
+func (c *externalCrypter) EncryptValue(plaintext string) (string, error) {
+	ciphertexts, err := c.run("encrypt", []string{plaintext})
+	if err != nil {
+		return "", err
+	}
+	return ciphertexts[0], nil
+}
+
+func (c *externalCrypter) DecryptValue(ciphertext string) (string, error) {
+	plaintexts, err := c.run("decrypt", []string{ciphertext})
+	if err != nil {
+		return "", err
+	}
+	return plaintexts[0], nil
+}
+
+func (c *externalCrypter) BulkDecrypt(ciphertexts []string) (map[string]string, error) {
+	plaintexts, err := c.run("decrypt", ciphertexts)
+	if err != nil {
+		return nil, err
+	}
+
+	secretMap := make(map[string]string, len(ciphertexts))
+	for i, ciphertext := range ciphertexts {
+		secretMap[ciphertext] = plaintexts[i]
+	}
+	return secretMap, nil
+}
+
+// run sends the given values to the external program and returns the values it responds with.
+func (c *externalCrypter) run(operation string, values []string) ([]string, error) {
+	request, err := json.Marshal(externalMessage{Values: values})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(c.command, operation)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("%s %s: %w", c.command, operation, err)
+	}
+
+	var response externalMessage
+	if err = json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("%s %s: reading response: %w", c.command, operation, err)
+	}
+	if len(response.Values) != len(values) {
+		return nil, fmt.Errorf("%s %s: expected %d values in the response, got %d", c.command, operation,
+			len(values), len(response.Values))
+	}
+	return response.Values, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubEnvVar makes the test binary act as a stub secrets provider program, which "encrypts" values by base64
+// encoding them. When it is set to "fail", the stub fails every request.
+const stubEnvVar = "PULUMI_TEST_EXTERNAL_SECRETS_STUB"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(stubEnvVar); mode != "" {
+		os.Exit(runStub(mode, os.Args[len(os.Args)-1]))
+	}
+	os.Exit(m.Run())
+}
+
+func runStub(mode, operation string) int {
+	if mode == "fail" {
+		fmt.Fprintln(os.Stderr, "the key is unavailable")
+		return 1
+	}
+
+	var request externalMessage
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	response := externalMessage{Values: make([]string, len(request.Values))}
+	for i, value := range request.Values {
+		switch operation {
+		case "encrypt":
+			response.Values[i] = "stub:" + base64.StdEncoding.EncodeToString([]byte(value))
+		case "decrypt":
+			plaintext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, "stub:"))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			response.Values[i] = string(plaintext)
+		default:
+			fmt.Fprintf(os.Stderr, "unknown operation %q\n", operation)
+			return 1
+		}
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(response); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func stubURL(t *testing.T) string {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	return URLPrefix + executable
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestExternalSecretsManager(t *testing.T) {
+	t.Setenv(stubEnvVar, "ok")
+
+	manager, err := NewExternalSecretsManager(stubURL(t))
+	require.NoError(t, err)
+	assert.Equal(t, Type, manager.Type())
+
+	enc, err := manager.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue("hunter2")
+	require.NoError(t, err)
+	assert.Equal(t, "stub:aHVudGVyMg==", ciphertext)
+
+	// Managers are reconstructed from their state when deployments are deserialized.
+	state, err := json.Marshal(manager.State())
+	require.NoError(t, err)
+	fromState, err := NewExternalSecretsManagerFromState(state)
+	require.NoError(t, err)
+	dec, err := fromState.Decrypter()
+	require.NoError(t, err)
+	plaintext, err := dec.DecryptValue(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", plaintext)
+
+	other, err := enc.EncryptValue("correct horse")
+	require.NoError(t, err)
+	plaintexts, err := dec.(*externalCrypter).BulkDecrypt([]string{ciphertext, other})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{ciphertext: "hunter2", other: "correct horse"}, plaintexts)
+}
+
+func TestExternalSecretsManagerFromUntrustedState(t *testing.T) {
+	t.Parallel()
+
+	// The program is never run, so it does not need to exist.
+	state, err := json.Marshal(externalSecretsManagerState{URL: "exec:///tmp/pulumi-test-untrusted-program"})
+	require.NoError(t, err)
+	_, err = NewExternalSecretsManagerFromState(state)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to run the secrets provider")
+
+	Trust("exec:///tmp/pulumi-test-untrusted-program")
+	_, err = NewExternalSecretsManagerFromState(state)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "finding secrets provider program")
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestExternalSecretsManagerErrors(t *testing.T) {
+	_, err := NewExternalSecretsManager("exec://")
+	assert.Error(t, err)
+	_, err = NewExternalSecretsManager("exec://pulumi-test-no-such-secrets-provider")
+	assert.Error(t, err)
+
+	t.Setenv(stubEnvVar, "fail")
+	manager, err := NewExternalSecretsManager(stubURL(t))
+	require.NoError(t, err)
+	enc, err := manager.Encrypter()
+	require.NoError(t, err)
+	_, err = enc.EncryptValue("hunter2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the key is unavailable")
+}
@@ -72,6 +72,20 @@ func IsCreateStack409Error(e error) bool {
 	return regex.MatchString(ae.stderr)
 }
 
+// driftExitCode is the exit code of `pulumi refresh --exit-code` when resources have drifted.
+const driftExitCode = 2
+
+// IsDriftDetectedError returns true if the error was a result of a refresh run with optrefresh.ExitCode finding
+// resources that have drifted from the state recorded in the stack.
+func IsDriftDetectedError(e error) bool {
+	ae, ok := e.(autoError)
+	if !ok {
+		return false
+	}
+
+	return ae.code == driftExitCode && strings.Contains(ae.stderr, "drift was detected")
+}
+
 // IsCompilationError returns true if the program failed at the build/run step (only Typescript, Go, .NET)
 func IsCompilationError(e error) bool {
 	as, ok := e.(autoError)
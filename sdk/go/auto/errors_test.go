@@ -579,3 +579,14 @@ func TestRuntimeErrorDotnet(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestDriftDetectedError(t *testing.T) {
+	t.Parallel()
+
+	err := newAutoError(fmt.Errorf("failed to refresh stack"), "", "error: drift was detected in 2 resources\n", 2)
+	assert.True(t, IsDriftDetectedError(err))
+
+	err = newAutoError(fmt.Errorf("failed to refresh stack"), "", "error: the stack is locked\n", 255)
+	assert.False(t, IsDriftDetectedError(err))
+	assert.False(t, IsDriftDetectedError(fmt.Errorf("drift was detected")))
+}
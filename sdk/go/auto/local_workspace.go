@@ -358,6 +358,17 @@ func (l *LocalWorkspace) RemoveStack(ctx context.Context, stackName string, opts
 	return nil
 }
 
+// RenameStack renames the stack matching the stack name to the new name, along with its Pulumi.<stack>.yaml file.
+// The new name may be fully qualified (org/project/stack) to move the stack to another project, in which case the
+// project's settings must be updated to match before the stack can be updated again.
+func (l *LocalWorkspace) RenameStack(ctx context.Context, stackName string, newName string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx, "stack", "rename", newName, "--stack", stackName)
+	if err != nil {
+		return newAutoError(errors.Wrap(err, "failed to rename stack"), stdout, stderr, errCode)
+	}
+	return nil
+}
+
 // ListStacks returns all Stacks created under the current Project.
 // This queries underlying backend and may return stacks not present in the Workspace (as Pulumi.<stack>.yaml files).
 func (l *LocalWorkspace) ListStacks(ctx context.Context) ([]StackSummary, error) {
@@ -507,6 +518,62 @@ func (l *LocalWorkspace) StackOutputs(ctx context.Context, stackName string) (Ou
 	return res, nil
 }
 
+// ChangeSecretsProvider changes the secrets provider of the stack matching the stack name, and re-encrypts the
+// secrets in its Pulumi.<stack>.yaml file and state with the new provider. Valid providers are `default`,
+// `passphrase`, and the URLs of cloud secrets providers such as `awskms://alias/ExampleAlias?region=us-east-1` or of
+// external programs such as `exec:///path/to/program`. Changing to the passphrase provider prompts for the new
+// passphrase, so it requires an interactive terminal.
+func (l *LocalWorkspace) ChangeSecretsProvider(ctx context.Context, stackName string, newSecretsProvider string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx,
+		"stack", "change-secrets-provider", newSecretsProvider, "--stack", stackName)
+	if err != nil {
+		return newAutoError(errors.Wrap(err, "failed to change secrets provider"), stdout, stderr, errCode)
+	}
+	return nil
+}
+
+// GetTag returns the value of the specified tag of the stack matching the stack name.
+func (l *LocalWorkspace) GetTag(ctx context.Context, stackName string, key string) (string, error) {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx, "stack", "tag", "get", key, "--stack", stackName)
+	if err != nil {
+		return "", newAutoError(errors.Wrap(err, "unable to read tag"), stdout, stderr, errCode)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// SetTag sets the specified tag of the stack matching the stack name to the specified value.
+func (l *LocalWorkspace) SetTag(ctx context.Context, stackName string, key string, value string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx, "stack", "tag", "set", key, value, "--stack", stackName)
+	if err != nil {
+		return newAutoError(errors.Wrap(err, "unable to set tag"), stdout, stderr, errCode)
+	}
+	return nil
+}
+
+// RemoveTag removes the specified tag from the stack matching the stack name.
+func (l *LocalWorkspace) RemoveTag(ctx context.Context, stackName string, key string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx, "stack", "tag", "rm", key, "--stack", stackName)
+	if err != nil {
+		return newAutoError(errors.Wrap(err, "unable to remove tag"), stdout, stderr, errCode)
+	}
+	return nil
+}
+
+// ListTags returns the tags of the stack matching the stack name, including the tags that the backend sets itself,
+// such as pulumi:project.
+func (l *LocalWorkspace) ListTags(ctx context.Context, stackName string) (map[string]string, error) {
+	var tags map[string]string
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx, "stack", "tag", "ls", "--json", "--stack", stackName)
+	if err != nil {
+		return tags, newAutoError(errors.Wrap(err, "unable to list tags"), stdout, stderr, errCode)
+	}
+	err = json.Unmarshal([]byte(stdout), &tags)
+	if err != nil {
+		return tags, errors.Wrap(err, "unable to unmarshal tags")
+	}
+	return tags, nil
+}
+
 func (l *LocalWorkspace) getPulumiVersion(ctx context.Context) (string, error) {
 	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx, "version")
 	if err != nil {
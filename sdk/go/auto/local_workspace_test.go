@@ -297,6 +297,10 @@ func TestNewStackLocalSource(t *testing.T) {
 	assert.Equal(t, 1, prev.ChangeSummary[apitype.OpSame])
 	steps := countSteps(previewEvents)
 	assert.Equal(t, 1, steps)
+	if assert.Len(t, prev.Steps, 1) {
+		assert.Equal(t, apitype.OpSame, prev.Steps[0].Op)
+		assert.Equal(t, "pulumi:pulumi:Stack", prev.Steps[0].Type)
+	}
 
 	// -- pulumi refresh --
 
@@ -0,0 +1,183 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package optimport contains functional options to be used with stack import operations
+// github.com/sdk/v2/go/x/auto Stack.ImportResources(...optimport.Option)
+package optimport
+
+import (
+	"io"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/debug"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+)
+
+// ImportResource describes an existing cloud resource to import into the stack.
+type ImportResource struct {
+	// Type is the type token of the resource, e.g. aws:s3/bucket:Bucket
+	Type string `json:"type"`
+	// Name is the name to give the resource in the stack
+	Name string `json:"name"`
+	// ID is the provider's ID of the resource to import
+	ID string `json:"id"`
+	// Parent (optional) is the name in the NameTable of the resource's parent
+	Parent string `json:"parent,omitempty"`
+	// Provider (optional) is the name in the NameTable of the provider to import the resource with
+	Provider string `json:"provider,omitempty"`
+	// Version (optional) is the version of the provider to import the resource with
+	Version string `json:"version,omitempty"`
+	// Properties (optional) are the names of the inputs to import, which defaults to the resource's required inputs
+	Properties []string `json:"properties,omitempty"`
+}
+
+// Resources specifies the resources to import
+func Resources(resources []ImportResource) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Resources = resources
+	})
+}
+
+// NameTable maps the names that ImportResource.Parent and ImportResource.Provider refer to, to the URNs of resources
+// that already exist in the stack
+func NameTable(names map[string]string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.NameTable = names
+	})
+}
+
+// Protect sets whether the imported resources are protected from deletion. Defaults to true.
+func Protect(protect bool) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Protect = &protect
+	})
+}
+
+// GenerateCode generates the declarations of the imported resources in the language of the project, which are
+// returned in ImportResult.GeneratedCode
+func GenerateCode() Option {
+	return optionFunc(func(opts *Options) {
+		opts.GenerateCode = true
+	})
+}
+
+// Parallel is the number of resource operations to run in parallel at once during the import
+// (1 for no parallelism). Defaults to unbounded. (default 2147483647)
+func Parallel(n int) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Parallel = n
+	})
+}
+
+// Message (optional) to associate with the import operation
+func Message(message string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Message = message
+	})
+}
+
+// ProgressStreams allows specifying one or more io.Writers to redirect incremental import output
+func ProgressStreams(writers ...io.Writer) Option {
+	return optionFunc(func(opts *Options) {
+		opts.ProgressStreams = writers
+	})
+}
+
+// EventStreams allows specifying one or more channels to receive the Pulumi event stream
+func EventStreams(channels ...chan<- events.EngineEvent) Option {
+	return optionFunc(func(opts *Options) {
+		opts.EventStreams = channels
+	})
+}
+
+// DebugLogging provides options for verbose logging to standard error, and enabling plugin logs.
+func DebugLogging(debugOpts debug.LoggingOptions) Option {
+	return optionFunc(func(opts *Options) {
+		opts.DebugLogOpts = debugOpts
+	})
+}
+
+// UserAgent specifies the agent responsible for the import, stored in backends as "environment.exec.agent"
+func UserAgent(agent string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.UserAgent = agent
+	})
+}
+
+// Diff displays operation as a rich diff showing the overall change
+func Diff() Option {
+	return optionFunc(func(opts *Options) {
+		opts.Diff = true
+	})
+}
+
+// SuppressOutputs suppresses the display of stack outputs (in case they contain sensitive values)
+func SuppressOutputs() Option {
+	return optionFunc(func(opts *Options) {
+		opts.SuppressOutputs = true
+	})
+}
+
+// SuppressPermalink suppresses the display of the state permalink
+func SuppressPermalink() Option {
+	return optionFunc(func(opts *Options) {
+		opts.SuppressPermalink = true
+	})
+}
+
+// Option is a parameter to be applied to a Stack.ImportResources() operation
+type Option interface {
+	ApplyOption(*Options)
+}
+
+// ---------------------------------- implementation details ----------------------------------
+
+// Options is an implementation detail
+type Options struct {
+	// The resources to import
+	Resources []ImportResource
+	// The URNs of existing resources that the resources to import refer to by name
+	NameTable map[string]string
+	// Whether to protect the imported resources from deletion. Defaults to true.
+	Protect *bool
+	// Generate the declarations of the imported resources
+	GenerateCode bool
+	// Parallel is the number of resource operations to run in parallel at once
+	// (1 for no parallelism). Defaults to unbounded. (default 2147483647)
+	Parallel int
+	// Message (optional) to associate with the import operation
+	Message string
+	// ProgressStreams allows specifying one or more io.Writers to redirect incremental import output
+	ProgressStreams []io.Writer
+	// EventStreams allows specifying one or more channels to receive the Pulumi event stream
+	EventStreams []chan<- events.EngineEvent
+	// DebugLogOpts specifies additional settings for debug logging
+	DebugLogOpts debug.LoggingOptions
+	// UserAgent specifies the agent responsible for the import, stored in backends as "environment.exec.agent"
+	UserAgent string
+	// Colorize output. Choices are: always, never, raw, auto (default "auto")
+	Color string
+	// Diff displays operation as a rich diff showing the overall change
+	Diff bool
+	// Suppress display of stack outputs
+	SuppressOutputs bool
+	// Suppress display of the state permalink
+	SuppressPermalink bool
+}
+
+type optionFunc func(*Options)
+
+// ApplyOption is an implementation detail
+func (o optionFunc) ApplyOption(opts *Options) {
+	o(opts)
+}
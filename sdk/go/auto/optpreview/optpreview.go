@@ -101,6 +101,108 @@ func UserAgent(agent string) Option {
 	})
 }
 
+// Plan specifies the path to a file to save the update plan proposed by the preview to. The plan can then be passed to
+// Stack.Up with optup.Plan to constrain the update to the operations planned by this preview.
+func Plan(path string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Plan = path
+	})
+}
+
+// PolicyPacks specifies paths to local policy packs to run as part of the preview
+func PolicyPacks(paths []string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.PolicyPacks = paths
+	})
+}
+
+// PolicyPackConfigs specifies paths to JSON files containing the config for the policy packs of the
+// corresponding PolicyPacks
+func PolicyPackConfigs(paths []string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.PolicyPackConfigs = paths
+	})
+}
+
+// Refresh will run a refresh before the preview
+func Refresh() Option {
+	return optionFunc(func(opts *Options) {
+		opts.Refresh = true
+	})
+}
+
+// TargetReplace specifies an array of resource URNs to replace. Other resources will not be updated
+func TargetReplace(urns []string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.TargetReplace = urns
+	})
+}
+
+// ParallelLimit limits how many resource operations of a package or resource type run in parallel at once during
+// the preview, keyed by package name (e.g. aws) or resource type (e.g. aws:route53/record:Record)
+func ParallelLimit(limits map[string]int) Option {
+	return optionFunc(func(opts *Options) {
+		opts.ParallelLimit = limits
+	})
+}
+
+// Retries is the number of times to retry resource operations that fail with an error the provider reports as
+// transient, waiting longer between each attempt
+func Retries(n int) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Retries = n
+	})
+}
+
+// ShowSecrets emits secrets in plaintext in the plan file saved with Plan
+func ShowSecrets() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowSecrets = true
+	})
+}
+
+// ShowConfig shows configuration keys and variables in the output
+func ShowConfig() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowConfig = true
+	})
+}
+
+// ShowReplacementSteps shows detailed resource replacement creates and deletes instead of a single step
+func ShowReplacementSteps() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowReplacementSteps = true
+	})
+}
+
+// ShowSames shows resources that needn't be updated because they haven't changed, alongside those that do
+func ShowSames() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowSames = true
+	})
+}
+
+// ShowReads shows resources that are being read in, alongside those being managed directly in the stack
+func ShowReads() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowReads = true
+	})
+}
+
+// SuppressOutputs suppresses the display of stack outputs (in case they contain sensitive values)
+func SuppressOutputs() Option {
+	return optionFunc(func(opts *Options) {
+		opts.SuppressOutputs = true
+	})
+}
+
+// SuppressPermalink suppresses the display of the state permalink
+func SuppressPermalink() Option {
+	return optionFunc(func(opts *Options) {
+		opts.SuppressPermalink = true
+	})
+}
+
 // Option is a parameter to be applied to a Stack.Preview() operation
 type Option interface {
 	ApplyOption(*Options)
@@ -135,6 +237,34 @@ type Options struct {
 	UserAgent string
 	// Colorize output. Choices are: always, never, raw, auto (default "auto")
 	Color string
+	// Save the update plan proposed by the preview to the given file path
+	Plan string
+	// Run one or more policy packs as part of this preview
+	PolicyPacks []string
+	// Paths to JSON files containing the config for the policy packs of the corresponding PolicyPacks
+	PolicyPackConfigs []string
+	// Refresh the state of the stack's resources before this preview
+	Refresh bool
+	// Specify resources to replace, which are also added to the Target list
+	TargetReplace []string
+	// Limit how many resource operations of a package or resource type run in parallel at once
+	ParallelLimit map[string]int
+	// Retry resource operations that fail with a transient error up to this many times
+	Retries int
+	// Emit secrets in plaintext in the plan file
+	ShowSecrets bool
+	// Show configuration keys and variables
+	ShowConfig bool
+	// Show detailed resource replacement creates and deletes instead of a single step
+	ShowReplacementSteps bool
+	// Show resources that needn't be updated because they haven't changed, alongside those that do
+	ShowSames bool
+	// Show resources that are being read in, alongside those being managed directly in the stack
+	ShowReads bool
+	// Suppress display of stack outputs
+	SuppressOutputs bool
+	// Suppress display of the state permalink
+	SuppressPermalink bool
 }
 
 type optionFunc func(*Options)
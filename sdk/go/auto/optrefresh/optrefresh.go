@@ -80,6 +80,72 @@ func UserAgent(agent string) Option {
 	})
 }
 
+// PreviewOnly only previews the refresh and reports any drift, but doesn't perform the refresh itself
+func PreviewOnly() Option {
+	return optionFunc(func(opts *Options) {
+		opts.PreviewOnly = true
+	})
+}
+
+// ExitCode causes the refresh to fail with an error for which auto.IsDriftDetectedError returns true if any
+// resources have drifted from the state recorded in the stack
+func ExitCode() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ExitCode = true
+	})
+}
+
+// ParallelLimit limits how many resource operations of a package or resource type run in parallel at once during
+// the refresh, keyed by package name (e.g. aws) or resource type (e.g. aws:route53/record:Record)
+func ParallelLimit(limits map[string]int) Option {
+	return optionFunc(func(opts *Options) {
+		opts.ParallelLimit = limits
+	})
+}
+
+// Retries is the number of times to retry resource operations that fail with an error the provider reports as
+// transient, waiting longer between each attempt
+func Retries(n int) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Retries = n
+	})
+}
+
+// Diff displays operation as a rich diff showing the overall change
+func Diff() Option {
+	return optionFunc(func(opts *Options) {
+		opts.Diff = true
+	})
+}
+
+// ShowReplacementSteps shows detailed resource replacement creates and deletes instead of a single step
+func ShowReplacementSteps() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowReplacementSteps = true
+	})
+}
+
+// ShowSames shows resources that needn't be updated because they haven't changed, alongside those that do
+func ShowSames() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowSames = true
+	})
+}
+
+// SuppressOutputs suppresses the display of stack outputs (in case they contain sensitive values)
+func SuppressOutputs() Option {
+	return optionFunc(func(opts *Options) {
+		opts.SuppressOutputs = true
+	})
+}
+
+// SuppressPermalink suppresses the display of the state permalink
+func SuppressPermalink() Option {
+	return optionFunc(func(opts *Options) {
+		opts.SuppressPermalink = true
+	})
+}
+
 // Option is a parameter to be applied to a Stack.Refresh() operation
 type Option interface {
 	ApplyOption(*Options)
@@ -108,6 +174,24 @@ type Options struct {
 	UserAgent string
 	// Colorize output. Choices are: always, never, raw, auto (default "auto")
 	Color string
+	// Only preview the refresh, but don't perform it
+	PreviewOnly bool
+	// Fail if any resources have drifted from the state recorded in the stack
+	ExitCode bool
+	// Limit how many resource operations of a package or resource type run in parallel at once
+	ParallelLimit map[string]int
+	// Retry resource operations that fail with a transient error up to this many times
+	Retries int
+	// Diff displays operation as a rich diff showing the overall change
+	Diff bool
+	// Show detailed resource replacement creates and deletes instead of a single step
+	ShowReplacementSteps bool
+	// Show resources that needn't be updated because they haven't changed, alongside those that do
+	ShowSames bool
+	// Suppress display of stack outputs
+	SuppressOutputs bool
+	// Suppress display of the state permalink
+	SuppressPermalink bool
 }
 
 type optionFunc func(*Options)
@@ -101,6 +101,116 @@ func UserAgent(agent string) Option {
 	})
 }
 
+// Plan specifies the path to an update plan saved by Stack.Preview with optpreview.Plan. The update will not perform
+// operations that exceed its plan.
+func Plan(path string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Plan = path
+	})
+}
+
+// PolicyPacks specifies paths to local policy packs to run as part of the update
+func PolicyPacks(paths []string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.PolicyPacks = paths
+	})
+}
+
+// PolicyPackConfigs specifies paths to JSON files containing the config for the policy packs of the
+// corresponding PolicyPacks
+func PolicyPackConfigs(paths []string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.PolicyPackConfigs = paths
+	})
+}
+
+// Refresh will run a refresh before the update
+func Refresh() Option {
+	return optionFunc(func(opts *Options) {
+		opts.Refresh = true
+	})
+}
+
+// TargetReplace specifies an array of resource URNs to replace. Other resources will not be updated
+func TargetReplace(urns []string) Option {
+	return optionFunc(func(opts *Options) {
+		opts.TargetReplace = urns
+	})
+}
+
+// ParallelLimit limits how many resource operations of a package or resource type run in parallel at once during
+// the update, keyed by package name (e.g. aws) or resource type (e.g. aws:route53/record:Record)
+func ParallelLimit(limits map[string]int) Option {
+	return optionFunc(func(opts *Options) {
+		opts.ParallelLimit = limits
+	})
+}
+
+// Retries is the number of times to retry resource operations that fail with an error the provider reports as
+// transient, waiting longer between each attempt
+func Retries(n int) Option {
+	return optionFunc(func(opts *Options) {
+		opts.Retries = n
+	})
+}
+
+// ContinueOnError continues updating resources that do not depend on a failed resource. Resources that depend on
+// it are skipped, and no resources are deleted
+func ContinueOnError() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ContinueOnError = true
+	})
+}
+
+// AuditSecrets warns about plaintext values in the state of resources that look like secrets
+func AuditSecrets() Option {
+	return optionFunc(func(opts *Options) {
+		opts.AuditSecrets = true
+	})
+}
+
+// ShowConfig shows configuration keys and variables in the output
+func ShowConfig() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowConfig = true
+	})
+}
+
+// ShowReplacementSteps shows detailed resource replacement creates and deletes instead of a single step
+func ShowReplacementSteps() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowReplacementSteps = true
+	})
+}
+
+// ShowSames shows resources that needn't be updated because they haven't changed, alongside those that do
+func ShowSames() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowSames = true
+	})
+}
+
+// ShowReads shows resources that are being read in, alongside those being managed directly in the stack
+func ShowReads() Option {
+	return optionFunc(func(opts *Options) {
+		opts.ShowReads = true
+	})
+}
+
+// SuppressOutputs suppresses the display of stack outputs (in case they contain sensitive values)
+func SuppressOutputs() Option {
+	return optionFunc(func(opts *Options) {
+		opts.SuppressOutputs = true
+	})
+}
+
+// SuppressPermalink suppresses the display of the state permalink
+func SuppressPermalink() Option {
+	return optionFunc(func(opts *Options) {
+		opts.SuppressPermalink = true
+	})
+}
+
 // Option is a parameter to be applied to a Stack.Up() operation
 type Option interface {
 	ApplyOption(*Options)
@@ -135,6 +245,36 @@ type Options struct {
 	UserAgent string
 	// Colorize output. Choices are: always, never, raw, auto (default "auto")
 	Color string
+	// Use the update plan at the given file path to constrain the update
+	Plan string
+	// Run one or more policy packs as part of this update
+	PolicyPacks []string
+	// Paths to JSON files containing the config for the policy packs of the corresponding PolicyPacks
+	PolicyPackConfigs []string
+	// Refresh the state of the stack's resources before this update
+	Refresh bool
+	// Specify resources to replace, which are also added to the Target list
+	TargetReplace []string
+	// Limit how many resource operations of a package or resource type run in parallel at once
+	ParallelLimit map[string]int
+	// Retry resource operations that fail with a transient error up to this many times
+	Retries int
+	// Continue updating resources that do not depend on a failed resource
+	ContinueOnError bool
+	// Warn about plaintext values in the state of resources that look like secrets
+	AuditSecrets bool
+	// Show configuration keys and variables
+	ShowConfig bool
+	// Show detailed resource replacement creates and deletes instead of a single step
+	ShowReplacementSteps bool
+	// Show resources that needn't be updated because they haven't changed, alongside those that do
+	ShowSames bool
+	// Show resources that are being read in, alongside those being managed directly in the stack
+	ShowReads bool
+	// Suppress display of stack outputs
+	SuppressOutputs bool
+	// Suppress display of the state permalink
+	SuppressPermalink bool
 }
 
 type optionFunc func(*Options)
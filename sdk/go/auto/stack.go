@@ -96,6 +96,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
@@ -107,8 +108,10 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/debug"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optimport"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optrefresh"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optstate"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/constant"
@@ -244,6 +247,46 @@ func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (Preview
 	if preOpts.Color != "" {
 		sharedArgs = append(sharedArgs, fmt.Sprintf("--color=%q", preOpts.Color))
 	}
+	if preOpts.Plan != "" {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--save-plan=%s", preOpts.Plan))
+	}
+	if preOpts.ShowSecrets {
+		sharedArgs = append(sharedArgs, "--show-secrets")
+	}
+	for _, pack := range preOpts.PolicyPacks {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--policy-pack=%s", pack))
+	}
+	for _, packConfig := range preOpts.PolicyPackConfigs {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--policy-pack-config=%s", packConfig))
+	}
+	if preOpts.Refresh {
+		sharedArgs = append(sharedArgs, "--refresh")
+	}
+	for _, trURN := range preOpts.TargetReplace {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--target-replace=%s", trURN))
+	}
+	sharedArgs = append(sharedArgs, parallelLimitArgs(preOpts.ParallelLimit)...)
+	if preOpts.Retries > 0 {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--retries=%d", preOpts.Retries))
+	}
+	if preOpts.ShowConfig {
+		sharedArgs = append(sharedArgs, "--show-config")
+	}
+	if preOpts.ShowReplacementSteps {
+		sharedArgs = append(sharedArgs, "--show-replacement-steps")
+	}
+	if preOpts.ShowSames {
+		sharedArgs = append(sharedArgs, "--show-sames")
+	}
+	if preOpts.ShowReads {
+		sharedArgs = append(sharedArgs, "--show-reads")
+	}
+	if preOpts.SuppressOutputs {
+		sharedArgs = append(sharedArgs, "--suppress-outputs")
+	}
+	if preOpts.SuppressPermalink {
+		sharedArgs = append(sharedArgs, "--suppress-permalink=true")
+	}
 
 	kind, args := constant.ExecKindAutoLocal, []string{"preview"}
 	if program := s.Workspace().Program(); program != nil {
@@ -259,20 +302,7 @@ func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (Preview
 	args = append(args, fmt.Sprintf("--exec-kind=%s", kind))
 	args = append(args, sharedArgs...)
 
-	var summaryEvents []apitype.SummaryEvent
-	eventChannel := make(chan events.EngineEvent)
-	go func(ch chan events.EngineEvent, events *[]apitype.SummaryEvent) {
-		for {
-			event, ok := <-eventChannel
-			if !ok {
-				return
-			}
-			if event.SummaryEvent != nil {
-				summaryEvents = append(summaryEvents, *event.SummaryEvent)
-			}
-		}
-	}(eventChannel, &summaryEvents)
-
+	collector, eventChannel := newStepCollector()
 	eventChannels := []chan<- events.EngineEvent{eventChannel}
 	eventChannels = append(eventChannels, preOpts.EventStreams...)
 
@@ -288,6 +318,7 @@ func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (Preview
 		return res, newAutoError(errors.Wrap(err, "failed to run preview"), stdout, stderr, code)
 	}
 
+	steps, summaryEvents := collector.wait(ctx)
 	if len(summaryEvents) == 0 {
 		return res, newAutoError(errors.New("failed to get preview summary"), stdout, stderr, code)
 	}
@@ -298,6 +329,7 @@ func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (Preview
 	res.StdOut = stdout
 	res.StdErr = stderr
 	res.ChangeSummary = summaryEvents[0].ResourceChanges
+	res.Steps = steps
 
 	return res, nil
 }
@@ -342,6 +374,49 @@ func (s *Stack) Up(ctx context.Context, opts ...optup.Option) (UpResult, error)
 	if upOpts.Color != "" {
 		sharedArgs = append(sharedArgs, fmt.Sprintf("--color=%q", upOpts.Color))
 	}
+	if upOpts.Plan != "" {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--plan=%s", upOpts.Plan))
+	}
+	for _, pack := range upOpts.PolicyPacks {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--policy-pack=%s", pack))
+	}
+	for _, packConfig := range upOpts.PolicyPackConfigs {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--policy-pack-config=%s", packConfig))
+	}
+	if upOpts.Refresh {
+		sharedArgs = append(sharedArgs, "--refresh")
+	}
+	for _, trURN := range upOpts.TargetReplace {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--target-replace=%s", trURN))
+	}
+	sharedArgs = append(sharedArgs, parallelLimitArgs(upOpts.ParallelLimit)...)
+	if upOpts.Retries > 0 {
+		sharedArgs = append(sharedArgs, fmt.Sprintf("--retries=%d", upOpts.Retries))
+	}
+	if upOpts.ContinueOnError {
+		sharedArgs = append(sharedArgs, "--continue-on-error")
+	}
+	if upOpts.AuditSecrets {
+		sharedArgs = append(sharedArgs, "--audit-secrets")
+	}
+	if upOpts.ShowConfig {
+		sharedArgs = append(sharedArgs, "--show-config")
+	}
+	if upOpts.ShowReplacementSteps {
+		sharedArgs = append(sharedArgs, "--show-replacement-steps")
+	}
+	if upOpts.ShowSames {
+		sharedArgs = append(sharedArgs, "--show-sames")
+	}
+	if upOpts.ShowReads {
+		sharedArgs = append(sharedArgs, "--show-reads")
+	}
+	if upOpts.SuppressOutputs {
+		sharedArgs = append(sharedArgs, "--suppress-outputs")
+	}
+	if upOpts.SuppressPermalink {
+		sharedArgs = append(sharedArgs, "--suppress-permalink=true")
+	}
 
 	kind, args := constant.ExecKindAutoLocal, []string{"up", "--yes", "--skip-preview"}
 	if program := s.Workspace().Program(); program != nil {
@@ -355,21 +430,23 @@ func (s *Stack) Up(ctx context.Context, opts ...optup.Option) (UpResult, error)
 	}
 	args = append(args, fmt.Sprintf("--exec-kind=%s", kind))
 
-	if len(upOpts.EventStreams) > 0 {
-		eventChannels := upOpts.EventStreams
-		t, err := tailLogs("up", eventChannels)
-		if err != nil {
-			return res, errors.Wrap(err, "failed to tail logs")
-		}
-		defer cleanup(t, eventChannels)
-		args = append(args, "--event-log", t.Filename)
+	collector, eventChannel := newStepCollector()
+	eventChannels := []chan<- events.EngineEvent{eventChannel}
+	eventChannels = append(eventChannels, upOpts.EventStreams...)
+
+	t, err := tailLogs("up", eventChannels)
+	if err != nil {
+		return res, errors.Wrap(err, "failed to tail logs")
 	}
+	defer cleanup(t, eventChannels)
+	args = append(args, "--event-log", t.Filename)
 
 	args = append(args, sharedArgs...)
 	stdout, stderr, code, err := s.runPulumiCmdSync(ctx, upOpts.ProgressStreams, args...)
 	if err != nil {
 		return res, newAutoError(errors.Wrap(err, "failed to run update"), stdout, stderr, code)
 	}
+	steps, _ := collector.wait(ctx)
 
 	outs, err := s.Outputs(ctx)
 	if err != nil {
@@ -385,6 +462,7 @@ func (s *Stack) Up(ctx context.Context, opts ...optup.Option) (UpResult, error)
 		Outputs: outs,
 		StdOut:  stdout,
 		StdErr:  stderr,
+		Steps:   steps,
 	}
 
 	if len(history) > 0 {
@@ -407,7 +485,11 @@ func (s *Stack) Refresh(ctx context.Context, opts ...optrefresh.Option) (Refresh
 	var args []string
 
 	args = debug.AddArgs(&refreshOpts.DebugLogOpts, args)
-	args = append(args, "refresh", "--yes", "--skip-preview")
+	if refreshOpts.PreviewOnly {
+		args = append(args, "refresh", "--preview-only")
+	} else {
+		args = append(args, "refresh", "--yes", "--skip-preview")
+	}
 	if refreshOpts.Message != "" {
 		args = append(args, fmt.Sprintf("--message=%q", refreshOpts.Message))
 	}
@@ -426,6 +508,28 @@ func (s *Stack) Refresh(ctx context.Context, opts ...optrefresh.Option) (Refresh
 	if refreshOpts.Color != "" {
 		args = append(args, fmt.Sprintf("--color=%q", refreshOpts.Color))
 	}
+	if refreshOpts.ExitCode {
+		args = append(args, "--exit-code")
+	}
+	args = append(args, parallelLimitArgs(refreshOpts.ParallelLimit)...)
+	if refreshOpts.Retries > 0 {
+		args = append(args, fmt.Sprintf("--retries=%d", refreshOpts.Retries))
+	}
+	if refreshOpts.Diff {
+		args = append(args, "--diff")
+	}
+	if refreshOpts.ShowReplacementSteps {
+		args = append(args, "--show-replacement-steps")
+	}
+	if refreshOpts.ShowSames {
+		args = append(args, "--show-sames")
+	}
+	if refreshOpts.SuppressOutputs {
+		args = append(args, "--suppress-outputs")
+	}
+	if refreshOpts.SuppressPermalink {
+		args = append(args, "--suppress-permalink=true")
+	}
 	execKind := constant.ExecKindAutoLocal
 	if s.Workspace().Program() != nil {
 		execKind = constant.ExecKindAutoInline
@@ -447,6 +551,11 @@ func (s *Stack) Refresh(ctx context.Context, opts ...optrefresh.Option) (Refresh
 		return res, newAutoError(errors.Wrap(err, "failed to refresh stack"), stdout, stderr, code)
 	}
 
+	// A previewed refresh is not recorded in the stack's history.
+	if refreshOpts.PreviewOnly {
+		return RefreshResult{StdOut: stdout, StdErr: stderr}, nil
+	}
+
 	history, err := s.History(ctx, 1 /*pageSize*/, 1 /*page*/)
 	if err != nil {
 		return res, errors.Wrap(err, "failed to refresh stack")
@@ -497,6 +606,34 @@ func (s *Stack) Destroy(ctx context.Context, opts ...optdestroy.Option) (Destroy
 	if destroyOpts.Color != "" {
 		args = append(args, fmt.Sprintf("--color=%q", destroyOpts.Color))
 	}
+	if destroyOpts.ExcludeProtected {
+		args = append(args, "--exclude-protected")
+	}
+	if destroyOpts.Refresh {
+		args = append(args, "--refresh")
+	}
+	args = append(args, parallelLimitArgs(destroyOpts.ParallelLimit)...)
+	if destroyOpts.Retries > 0 {
+		args = append(args, fmt.Sprintf("--retries=%d", destroyOpts.Retries))
+	}
+	if destroyOpts.Diff {
+		args = append(args, "--diff")
+	}
+	if destroyOpts.ShowConfig {
+		args = append(args, "--show-config")
+	}
+	if destroyOpts.ShowReplacementSteps {
+		args = append(args, "--show-replacement-steps")
+	}
+	if destroyOpts.ShowSames {
+		args = append(args, "--show-sames")
+	}
+	if destroyOpts.SuppressOutputs {
+		args = append(args, "--suppress-outputs")
+	}
+	if destroyOpts.SuppressPermalink {
+		args = append(args, "--suppress-permalink=true")
+	}
 	execKind := constant.ExecKindAutoLocal
 	if s.Workspace().Program() != nil {
 		execKind = constant.ExecKindAutoInline
@@ -537,6 +674,133 @@ func (s *Stack) Destroy(ctx context.Context, opts ...optdestroy.Option) (Destroy
 	return res, nil
 }
 
+// ImportResources imports existing cloud resources into the stack, so that they are managed by Pulumi from then on.
+// The resources to import are specified with optimport.Resources. They are protected from deletion unless
+// optimport.Protect(false) is passed. To keep managing them, add their declarations to the stack's program;
+// optimport.GenerateCode generates these declarations.
+func (s *Stack) ImportResources(ctx context.Context, opts ...optimport.Option) (ImportResult, error) {
+	var res ImportResult
+
+	importOpts := &optimport.Options{}
+	for _, o := range opts {
+		o.ApplyOption(importOpts)
+	}
+	if len(importOpts.Resources) == 0 {
+		return res, errors.New("failed to import resources: no resources specified")
+	}
+
+	tempDir, err := ioutil.TempDir("", "pulumi_import")
+	if err != nil {
+		return res, errors.Wrap(err, "failed to import resources, unable to create tmp directory")
+	}
+	defer func() { contract.IgnoreError(os.RemoveAll(tempDir)) }()
+
+	importFile, err := writeImportFile(tempDir, importOpts)
+	if err != nil {
+		return res, errors.Wrap(err, "failed to import resources")
+	}
+
+	args := []string{"import", "--yes", "--skip-preview", "--file", importFile}
+	args = debug.AddArgs(&importOpts.DebugLogOpts, args)
+	if importOpts.Message != "" {
+		args = append(args, fmt.Sprintf("--message=%q", importOpts.Message))
+	}
+	if importOpts.Protect != nil {
+		args = append(args, fmt.Sprintf("--protect=%t", *importOpts.Protect))
+	}
+	codeFile := filepath.Join(tempDir, "code")
+	if importOpts.GenerateCode {
+		args = append(args, fmt.Sprintf("--out=%s", codeFile))
+	} else {
+		args = append(args, "--generate-code=false")
+	}
+	if importOpts.Parallel > 0 {
+		args = append(args, fmt.Sprintf("--parallel=%d", importOpts.Parallel))
+	}
+	if importOpts.UserAgent != "" {
+		args = append(args, fmt.Sprintf("--exec-agent=%s", importOpts.UserAgent))
+	}
+	if importOpts.Color != "" {
+		args = append(args, fmt.Sprintf("--color=%q", importOpts.Color))
+	}
+	if importOpts.Diff {
+		args = append(args, "--diff")
+	}
+	if importOpts.SuppressOutputs {
+		args = append(args, "--suppress-outputs")
+	}
+	if importOpts.SuppressPermalink {
+		args = append(args, "--suppress-permalink=true")
+	}
+	execKind := constant.ExecKindAutoLocal
+	if s.Workspace().Program() != nil {
+		execKind = constant.ExecKindAutoInline
+	}
+	args = append(args, fmt.Sprintf("--exec-kind=%s", execKind))
+
+	collector, eventChannel := newStepCollector()
+	eventChannels := []chan<- events.EngineEvent{eventChannel}
+	eventChannels = append(eventChannels, importOpts.EventStreams...)
+
+	t, err := tailLogs("import", eventChannels)
+	if err != nil {
+		return res, errors.Wrap(err, "failed to tail logs")
+	}
+	defer cleanup(t, eventChannels)
+	args = append(args, "--event-log", t.Filename)
+
+	stdout, stderr, code, err := s.runPulumiCmdSync(ctx, importOpts.ProgressStreams, args...)
+	if err != nil {
+		return res, newAutoError(errors.Wrap(err, "failed to import resources"), stdout, stderr, code)
+	}
+	steps, _ := collector.wait(ctx)
+
+	var generatedCode []byte
+	if importOpts.GenerateCode {
+		generatedCode, err = ioutil.ReadFile(codeFile)
+		if err != nil && !os.IsNotExist(err) {
+			return res, errors.Wrap(err, "failed to read generated code")
+		}
+	}
+
+	history, err := s.History(ctx, 1 /*pageSize*/, 1 /*page*/)
+	if err != nil {
+		return res, errors.Wrap(err, "failed to import resources")
+	}
+
+	res = ImportResult{
+		StdOut:        stdout,
+		StdErr:        stderr,
+		GeneratedCode: string(generatedCode),
+		Steps:         steps,
+	}
+
+	if len(history) > 0 {
+		res.Summary = history[0]
+	}
+
+	return res, nil
+}
+
+// importFile is the format of the file that `pulumi import --file` reads the resources to import from.
+type importFile struct {
+	NameTable map[string]string          `json:"nameTable,omitempty"`
+	Resources []optimport.ImportResource `json:"resources"`
+}
+
+// writeImportFile writes the resources to import to a file in the given directory, and returns its path.
+func writeImportFile(dir string, opts *optimport.Options) (string, error) {
+	bytes, err := json.Marshal(importFile{NameTable: opts.NameTable, Resources: opts.Resources})
+	if err != nil {
+		return "", errors.Wrap(err, "unable to marshal resources to import")
+	}
+	path := filepath.Join(dir, "resources.json")
+	if err = ioutil.WriteFile(path, bytes, 0600); err != nil {
+		return "", errors.Wrap(err, "unable to write resources to import")
+	}
+	return path, nil
+}
+
 // Outputs get the current set of Stack outputs from the last Stack.Up().
 func (s *Stack) Outputs(ctx context.Context) (OutputMap, error) {
 	return s.Workspace().StackOutputs(ctx, s.Name())
@@ -655,6 +919,104 @@ func (s *Stack) Import(ctx context.Context, state apitype.UntypedDeployment) err
 	return s.Workspace().ImportStack(ctx, s.Name(), state)
 }
 
+// Rename renames the stack, along with its Pulumi.<stack>.yaml file. The new name may be fully qualified
+// (org/project/stack) to move the stack to another project.
+// Note that renaming a stack changes the value of `pulumi.Context.Stack()`, so if it is used in the names of
+// resources, the next update will replace them.
+func (s *Stack) Rename(ctx context.Context, newName string) error {
+	if err := s.Workspace().RenameStack(ctx, s.Name(), newName); err != nil {
+		return err
+	}
+	s.stackName = newName
+	return nil
+}
+
+// ChangeSecretsProvider changes the secrets provider of the stack, and re-encrypts the secrets in its configuration
+// and state with the new provider.
+func (s *Stack) ChangeSecretsProvider(ctx context.Context, newSecretsProvider string) error {
+	return s.Workspace().ChangeSecretsProvider(ctx, s.Name(), newSecretsProvider)
+}
+
+// GetTag returns the value of the specified stack tag.
+func (s *Stack) GetTag(ctx context.Context, key string) (string, error) {
+	return s.Workspace().GetTag(ctx, s.Name(), key)
+}
+
+// SetTag sets the specified stack tag to the specified value.
+func (s *Stack) SetTag(ctx context.Context, key string, value string) error {
+	return s.Workspace().SetTag(ctx, s.Name(), key, value)
+}
+
+// RemoveTag removes the specified stack tag.
+func (s *Stack) RemoveTag(ctx context.Context, key string) error {
+	return s.Workspace().RemoveTag(ctx, s.Name(), key)
+}
+
+// ListTags returns the stack's tags.
+func (s *Stack) ListTags(ctx context.Context) (map[string]string, error) {
+	return s.Workspace().ListTags(ctx, s.Name())
+}
+
+// StateDelete deletes the resource matching the URN from the stack's state, without deleting the cloud resource.
+// Resources that other resources depend on cannot be deleted, and protected resources are only deleted if
+// optstate.Force is passed.
+func (s *Stack) StateDelete(ctx context.Context, urn string, opts ...optstate.Option) error {
+	stateOpts := &optstate.Options{}
+	for _, o := range opts {
+		o.ApplyOption(stateOpts)
+	}
+
+	args := []string{"state", "delete", urn, "--yes"}
+	if stateOpts.Force {
+		args = append(args, "--force")
+	}
+
+	stdout, stderr, errCode, err := s.runPulumiCmdSync(ctx, nil /* additionalOutput */, args...)
+	if err != nil {
+		return newAutoError(errors.Wrap(err, "failed to delete resource from state"), stdout, stderr, errCode)
+	}
+	return nil
+}
+
+// StateUnprotect clears the protect bit of the resource matching the URN in the stack's state, allowing it to be
+// deleted.
+func (s *Stack) StateUnprotect(ctx context.Context, urn string) error {
+	stdout, stderr, errCode, err := s.runPulumiCmdSync(
+		ctx,
+		nil, /* additionalOutput */
+		"state", "unprotect", urn, "--yes")
+	if err != nil {
+		return newAutoError(errors.Wrap(err, "failed to unprotect resource"), stdout, stderr, errCode)
+	}
+	return nil
+}
+
+// StateUnprotectAll clears the protect bit of all of the resources in the stack's state.
+func (s *Stack) StateUnprotectAll(ctx context.Context) error {
+	stdout, stderr, errCode, err := s.runPulumiCmdSync(
+		ctx,
+		nil, /* additionalOutput */
+		"state", "unprotect", "--all", "--yes")
+	if err != nil {
+		return newAutoError(errors.Wrap(err, "failed to unprotect resources"), stdout, stderr, errCode)
+	}
+	return nil
+}
+
+// StateRename renames the resource matching the URN in the stack's state, and updates the resources that depend on
+// it. The resource's declaration in the stack's program must be renamed to match, or the next update will replace
+// it.
+func (s *Stack) StateRename(ctx context.Context, urn string, newName string) error {
+	stdout, stderr, errCode, err := s.runPulumiCmdSync(
+		ctx,
+		nil, /* additionalOutput */
+		"state", "rename", urn, newName, "--yes")
+	if err != nil {
+		return newAutoError(errors.Wrap(err, "failed to rename resource"), stdout, stderr, errCode)
+	}
+	return nil
+}
+
 // UpdateSummary provides a summary of a Stack lifecycle operation (up/preview/refresh/destroy).
 type UpdateSummary struct {
 	Version     int               `json:"version"`
@@ -683,6 +1045,8 @@ type UpResult struct {
 	StdErr  string
 	Outputs OutputMap
 	Summary UpdateSummary
+	// Steps are the steps that the update performed, in order, including the resources that were unchanged.
+	Steps []StepResult
 }
 
 // GetPermalink returns the permalink URL in the Pulumi Console for the update operation.
@@ -752,6 +1116,8 @@ type PreviewResult struct {
 	StdOut        string
 	StdErr        string
 	ChangeSummary map[apitype.OpType]int
+	// Steps are the steps that the update would perform, in order, including the resources that are unchanged.
+	Steps []StepResult
 }
 
 // GetPermalink returns the permalink URL in the Pulumi Console for the preview operation.
@@ -761,8 +1127,10 @@ func (pr *PreviewResult) GetPermalink() (string, error) {
 
 // RefreshResult is the output of a successful Stack.Refresh operation
 type RefreshResult struct {
-	StdOut  string
-	StdErr  string
+	StdOut string
+	StdErr string
+	// Summary is the summary of the refresh in the stack's history. It is not set for refreshes run with
+	// optrefresh.PreviewOnly, which are not recorded.
 	Summary UpdateSummary
 }
 
@@ -783,6 +1151,22 @@ func (dr *DestroyResult) GetPermalink() (string, error) {
 	return GetPermalink(dr.StdOut)
 }
 
+// ImportResult is the output of a successful Stack.ImportResources operation
+type ImportResult struct {
+	StdOut string
+	StdErr string
+	// GeneratedCode holds the declarations of the imported resources, if optimport.GenerateCode was passed.
+	GeneratedCode string
+	// Steps are the steps that the import performed, in order.
+	Steps   []StepResult
+	Summary UpdateSummary
+}
+
+// GetPermalink returns the permalink URL in the Pulumi Console for the import operation.
+func (ir *ImportResult) GetPermalink() (string, error) {
+	return GetPermalink(ir.StdOut)
+}
+
 // secretSentinel represents the CLI response for an output marked as "secret"
 const secretSentinel = "[secret]"
 
@@ -971,6 +1355,21 @@ func (s *languageRuntimeServer) GetPluginInfo(ctx context.Context, req *pbempty.
 	}, nil
 }
 
+// parallelLimitArgs returns the --parallel-limit flags for the given limits, in a stable order.
+func parallelLimitArgs(limits map[string]int) []string {
+	keys := make([]string, 0, len(limits))
+	for key := range limits {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("--parallel-limit=%s=%d", key, limits[key]))
+	}
+	return args
+}
+
 func tailLogs(command string, receivers []chan<- events.EngineEvent) (*tail.Tail, error) {
 	logDir, err := ioutil.TempDir("", fmt.Sprintf("automation-logs-%s-", command))
 	if err != nil {
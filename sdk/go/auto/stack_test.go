@@ -15,8 +15,15 @@
 package auto
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optimport"
 )
 
 const testPermalink = "Permalink: https://gotest"
@@ -53,3 +60,37 @@ func TestGetPermalink(t *testing.T) {
 	}
 
 }
+
+func TestWriteImportFile(t *testing.T) {
+	t.Parallel()
+
+	opts := &optimport.Options{}
+	for _, o := range []optimport.Option{
+		optimport.NameTable(map[string]string{"prov": "urn:pulumi:dev::proj::pulumi:providers:aws::prov"}),
+		optimport.Resources([]optimport.ImportResource{
+			{Type: "aws:s3/bucket:Bucket", Name: "bucket", ID: "my-bucket", Provider: "prov"},
+			{Type: "aws:s3/bucketPolicy:BucketPolicy", Name: "policy", ID: "my-bucket", Properties: []string{"bucket"}},
+		}),
+	} {
+		o.ApplyOption(opts)
+	}
+
+	path, err := writeImportFile(t.TempDir(), opts)
+	require.NoError(t, err)
+	bytes, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	// The file must be in the format that `pulumi import --file` reads.
+	var file map[string]interface{}
+	require.NoError(t, json.Unmarshal(bytes, &file))
+	assert.Equal(t, map[string]interface{}{
+		"nameTable": map[string]interface{}{"prov": "urn:pulumi:dev::proj::pulumi:providers:aws::prov"},
+		"resources": []interface{}{
+			map[string]interface{}{"type": "aws:s3/bucket:Bucket", "name": "bucket", "id": "my-bucket", "provider": "prov"},
+			map[string]interface{}{
+				"type": "aws:s3/bucketPolicy:BucketPolicy", "name": "policy", "id": "my-bucket",
+				"properties": []interface{}{"bucket"},
+			},
+		},
+	}, file)
+}
@@ -0,0 +1,121 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auto
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+// StepResult describes a step that a preview planned, or that an update performed, on a resource.
+type StepResult struct {
+	// URN is the URN of the resource.
+	URN string
+	// Type is the type of the resource.
+	Type string
+	// Op is the operation of the step. Resources that are replaced have several steps, such as
+	// apitype.OpCreateReplacement, apitype.OpReplace and apitype.OpDeleteReplaced.
+	Op apitype.OpType
+	// OldInputs are the inputs of the resource before the step, or nil if it did not exist. Secrets are filtered
+	// out.
+	OldInputs map[string]interface{}
+	// NewInputs are the inputs of the resource after the step, or nil if it is deleted. Secrets are filtered out.
+	NewInputs map[string]interface{}
+	// DiffKeys are the keys of the top-level properties that differ.
+	DiffKeys []string
+	// DetailedDiff is the difference of each changed property, keyed by property path. It is only set for
+	// resources whose providers support detailed diffs.
+	DetailedDiff map[string]apitype.PropertyDiff
+	// ReplaceReasons are the keys of the properties whose changes require the resource to be replaced.
+	ReplaceReasons []string
+}
+
+// newStepResult returns the step described by the metadata of an engine event.
+func newStepResult(metadata apitype.StepEventMetadata) StepResult {
+	step := StepResult{
+		URN:            metadata.URN,
+		Type:           metadata.Type,
+		Op:             metadata.Op,
+		DiffKeys:       metadata.Diffs,
+		DetailedDiff:   metadata.DetailedDiff,
+		ReplaceReasons: metadata.Keys,
+	}
+	if metadata.Old != nil {
+		step.OldInputs = metadata.Old.Inputs
+	}
+	if metadata.New != nil {
+		step.NewInputs = metadata.New.Inputs
+	}
+	return step
+}
+
+// summaryTimeout is how long to wait for the summary event of an operation once the command has exited. The event log
+// is complete by then, so this only bounds the time it takes to read it.
+const summaryTimeout = 30 * time.Second
+
+// stepCollector collects the steps and the summary of an operation from its engine events.
+type stepCollector struct {
+	lock      sync.Mutex
+	steps     []StepResult
+	summaries []apitype.SummaryEvent
+	// summarized is closed once the first summary event is received.
+	summarized chan struct{}
+}
+
+// newStepCollector returns a collector and the channel to send an operation's engine events to. The collector stops
+// when the channel is closed.
+func newStepCollector() (*stepCollector, chan events.EngineEvent) {
+	c := &stepCollector{summarized: make(chan struct{})}
+	ch := make(chan events.EngineEvent)
+	go func() {
+		for event := range ch {
+			c.collect(event)
+		}
+	}()
+	return c, ch
+}
+
+func (c *stepCollector) collect(event events.EngineEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	switch {
+	case event.ResourcePreEvent != nil:
+		c.steps = append(c.steps, newStepResult(event.ResourcePreEvent.Metadata))
+	case event.SummaryEvent != nil:
+		c.summaries = append(c.summaries, *event.SummaryEvent)
+		if len(c.summaries) == 1 {
+			close(c.summarized)
+		}
+	}
+}
+
+// wait waits until the summary event of the operation is received, which is the last event of an operation that
+// succeeds, and returns the steps and summaries received so far. It must only be called once the command has exited.
+func (c *stepCollector) wait(ctx context.Context) ([]StepResult, []apitype.SummaryEvent) {
+	select {
+	case <-c.summarized:
+	case <-ctx.Done():
+	case <-time.After(summaryTimeout):
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.steps, c.summaries
+}
@@ -0,0 +1,78 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+)
+
+func TestStepCollector(t *testing.T) {
+	t.Parallel()
+
+	collector, ch := newStepCollector()
+	defer close(ch)
+
+	urn := "urn:pulumi:dev::proj::random:index/randomString:RandomString::str"
+	ch <- events.EngineEvent{EngineEvent: apitype.EngineEvent{
+		ResourcePreEvent: &apitype.ResourcePreEvent{
+			Metadata: apitype.StepEventMetadata{
+				Op:   apitype.OpReplace,
+				URN:  urn,
+				Type: "random:index/randomString:RandomString",
+				Old: &apitype.StepEventStateMetadata{
+					Inputs: map[string]interface{}{"length": 8},
+				},
+				New: &apitype.StepEventStateMetadata{
+					Inputs: map[string]interface{}{"length": 16},
+				},
+				Keys:  []string{"length"},
+				Diffs: []string{"length"},
+				DetailedDiff: map[string]apitype.PropertyDiff{
+					"length": {Kind: apitype.DiffUpdateReplace, InputDiff: true},
+				},
+			},
+			Planning: true,
+		},
+	}}
+	ch <- events.EngineEvent{EngineEvent: apitype.EngineEvent{
+		DiagnosticEvent: &apitype.DiagnosticEvent{Message: "a warning", Severity: "warning"},
+	}}
+	ch <- events.EngineEvent{EngineEvent: apitype.EngineEvent{
+		SummaryEvent: &apitype.SummaryEvent{
+			ResourceChanges: map[apitype.OpType]int{apitype.OpReplace: 1},
+		},
+	}}
+
+	steps, summaries := collector.wait(context.Background())
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, []StepResult{{
+		URN:       urn,
+		Type:      "random:index/randomString:RandomString",
+		Op:        apitype.OpReplace,
+		OldInputs: map[string]interface{}{"length": 8},
+		NewInputs: map[string]interface{}{"length": 16},
+		DiffKeys:  []string{"length"},
+		DetailedDiff: map[string]apitype.PropertyDiff{
+			"length": {Kind: apitype.DiffUpdateReplace, InputDiff: true},
+		},
+		ReplaceReasons: []string{"length"},
+	}}, steps)
+}
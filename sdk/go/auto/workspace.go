@@ -87,6 +87,9 @@ type Workspace interface {
 	SelectStack(context.Context, string) error
 	// RemoveStack deletes the stack and all associated configuration and history.
 	RemoveStack(context.Context, string, ...optremove.Option) error
+	// RenameStack renames the stack matching the first stack name to the second, along with its configuration file.
+	// The new name may be fully qualified to move the stack to another project.
+	RenameStack(context.Context, string, string) error
 	// ListStacks returns all Stacks created under the current Project.
 	// This queries underlying backend and may return stacks not present in the Workspace.
 	ListStacks(context.Context) ([]StackSummary, error)
@@ -109,6 +112,17 @@ type Workspace interface {
 	ImportStack(context.Context, string, apitype.UntypedDeployment) error
 	// Outputs get the current set of Stack outputs from the last Stack.Up().
 	StackOutputs(context.Context, string) (OutputMap, error)
+	// ChangeSecretsProvider changes the secrets provider of the stack matching the specified stack name, and
+	// re-encrypts its configuration and state with the new provider.
+	ChangeSecretsProvider(context.Context, string, string) error
+	// GetTag returns the value of the specified tag of the stack matching the specified stack name.
+	GetTag(context.Context, string, string) (string, error)
+	// SetTag sets the specified tag of the stack matching the specified stack name to the specified value.
+	SetTag(context.Context, string, string, string) error
+	// RemoveTag removes the specified tag from the stack matching the specified stack name.
+	RemoveTag(context.Context, string, string) error
+	// ListTags returns the tags of the stack matching the specified stack name.
+	ListTags(context.Context, string) (map[string]string, error)
 }
 
 // ConfigValue is a configuration value used by a Pulumi program.
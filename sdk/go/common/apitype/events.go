@@ -74,6 +74,8 @@ type SummaryEvent struct {
 	DurationSeconds int `json:"durationSeconds"`
 	// ResourceChanges contains the count for resource change by type.
 	ResourceChanges map[OpType]int `json:"resourceChanges"`
+	// Failures is the number of resource operations that failed.
+	Failures int `json:"failures,omitempty"`
 	// PolicyPacks run during update. Maps PolicyPackName -> version.
 	// Note: When this field was initially added, we forgot to add the JSON tag
 	// and are now locked into to using PascalCase for this field to maintain backwards
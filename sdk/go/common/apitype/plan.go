@@ -67,6 +67,12 @@ type ResourcePlanV1 struct {
 	Outputs map[string]interface{} `json:"state"`
 }
 
+const (
+	// DeploymentPlanSchemaVersionCurrent is the current version of the `DeploymentPlan` schema.
+	// Any plans newer than this version will be rejected.
+	DeploymentPlanSchemaVersionCurrent = 1
+)
+
 // VersionedDeploymentPlan is a version number plus a JSON document. The version number describes what
 // version of the DeploymentPlan structure the DeploymentPlan member's JSON document can decode into.
 type VersionedDeploymentPlan struct {
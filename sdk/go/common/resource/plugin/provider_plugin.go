@@ -21,6 +21,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	pbempty "github.com/golang/protobuf/ptypes/empty"
@@ -1065,7 +1066,7 @@ func (p *provider) Delete(urn resource.URN, id resource.ID, props resource.Prope
 	}); err != nil {
 		resourceStatus, rpcErr := resourceStateAndError(err)
 		logging.V(7).Infof("%s failed: %v", label, rpcErr)
-		return resourceStatus, rpcErr
+		return resourceStatus, retryableError(rpcErr, rpcErr)
 	}
 
 	logging.V(7).Infof("%s success", label)
@@ -1566,9 +1567,42 @@ func parseError(err error) (
 		}
 	}
 
+	// Resources that were created but failed to initialize are never retried, as retrying would create them again.
+	if resourceStatus != resource.StatusPartialFailure {
+		resourceErr = retryableError(responseErr, resourceErr)
+	}
+
 	return resourceStatus, id, liveObject, liveInputs, resourceErr
 }
 
+// retryableError wraps err in a RetryableError if the provider marked the given RPC error as retryable.
+func retryableError(rpcErr *rpcerror.Error, err error) error {
+	for _, detail := range rpcErr.Details() {
+		if retryable, ok := detail.(*pulumirpc.ErrorRetryable); ok {
+			retryAfter := time.Duration(retryable.GetRetryAfter() * float64(time.Second))
+			return &RetryableError{Err: err, RetryAfter: retryAfter}
+		}
+	}
+	return err
+}
+
+// RetryableError represents a failure that the provider considers transient, e.g. because a request was throttled.
+// The operation that failed may succeed if it is retried.
+type RetryableError struct {
+	Err        error         // the underlying error.
+	RetryAfter time.Duration // the delay the provider suggests before retrying, or zero if it has no preference.
+}
+
+var _ error = (*RetryableError)(nil)
+
+func (re *RetryableError) Error() string {
+	return re.Err.Error()
+}
+
+func (re *RetryableError) Unwrap() error {
+	return re.Err
+}
+
 // InitError represents a failure to initialize a resource, i.e., the resource has been successfully
 // created, but it has failed to initialize.
 type InitError struct {
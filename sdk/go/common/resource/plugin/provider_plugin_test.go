@@ -1,12 +1,17 @@
 package plugin
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil/rpcerror"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 )
 
 func TestAnnotateSecrets(t *testing.T) {
@@ -225,3 +230,27 @@ func TestNestedSecret(t *testing.T) {
 
 	assert.Truef(t, reflect.DeepEqual(to, expected), "did not match expected after annotation")
 }
+
+func TestParseRetryableError(t *testing.T) {
+	t.Parallel()
+
+	err := rpcerror.WithDetails(rpcerror.New(codes.Unavailable, "throttled"), &pulumirpc.ErrorRetryable{RetryAfter: 1.5})
+	status, _, _, _, resourceErr := parseError(err)
+	assert.Equal(t, resource.StatusOK, status)
+	var retryable *RetryableError
+	if assert.True(t, errors.As(resourceErr, &retryable)) {
+		assert.Equal(t, 1500*time.Millisecond, retryable.RetryAfter)
+		assert.Contains(t, retryable.Error(), "throttled")
+	}
+
+	// Errors without the detail are not retryable.
+	_, _, _, _, resourceErr = parseError(rpcerror.New(codes.Unavailable, "throttled"))
+	assert.False(t, errors.As(resourceErr, &retryable))
+
+	// Neither are resources that were created but failed to initialize.
+	err = rpcerror.WithDetails(rpcerror.New(codes.Unknown, "failed"),
+		&pulumirpc.ErrorResourceInitFailed{Id: "id", Reasons: []string{"failed"}}, &pulumirpc.ErrorRetryable{})
+	status, _, _, _, resourceErr = parseError(err)
+	assert.Equal(t, resource.StatusPartialFailure, status)
+	assert.IsType(t, &InitError{}, resourceErr)
+}
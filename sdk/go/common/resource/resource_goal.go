@@ -40,6 +40,9 @@ type Goal struct {
 	ReplaceOnChanges        []string              // a list of property paths that if changed should force a replacement.
 	// if set to True, the providers Delete method will not be called for this resource.
 	RetainOnDelete bool
+	// the number of times to retry operations that fail with a retryable error, or zero to use the deployment's default.
+	// This is not saved in the resource's state, so deleting a resource that has no goal uses the deployment's default.
+	Retries int
 }
 
 // NewGoal allocates a new resource goal state.
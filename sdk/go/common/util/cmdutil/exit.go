@@ -135,11 +135,37 @@ func RunResultFunc(run func(cmd *cobra.Command, args []string) result.Result) fu
 				logging.V(3).Infof(DetailedError(err))
 			}
 
-			ExitError(msg)
+			exitErrorCodef(exitCode(err), "%s", msg)
 		}
 	}
 }
 
+// ExitCodeError is an error that causes a command wrapped in [RunFunc] or [RunResultFunc] to exit with the given code
+// instead of the standard error exit code. This allows scripts to tell a condition the command detected apart from a
+// failure to run the command.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// exitCode returns the code to exit with when a command fails with the given error, which is that of the first
+// ExitCodeError that the error wraps, if any.
+func exitCode(err error) int {
+	var exitErr *ExitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return -1
+}
+
 // Exit exits with a given error.
 func Exit(err error) {
 	ExitError(errorMessage(err))
@@ -0,0 +1,32 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	exitErr := &ExitCodeError{Code: 2, Err: errors.New("drift detected")}
+	assert.Equal(t, 2, exitCode(exitErr))
+	assert.Equal(t, 2, exitCode(fmt.Errorf("refreshing stack: %w", exitErr)))
+	assert.Equal(t, -1, exitCode(errors.New("failed")))
+}
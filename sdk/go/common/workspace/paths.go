@@ -39,10 +39,14 @@ const (
 	GitDir = ".git"
 	// HistoryDir is the name of the directory that holds historical information for projects.
 	HistoryDir = "history"
+	// JournalDir is the name of the directory that holds in-progress update journals for stacks.
+	JournalDir = "journals"
 	// PluginDir is the name of the directory containing plugins.
 	PluginDir = "plugins"
 	// PolicyDir is the name of the directory that holds policy packs.
 	PolicyDir = "policies"
+	// PolicyGroupDir is the name of the directory that holds the policy groups of self-managed backends.
+	PolicyGroupDir = "policygroups"
 	// StackDir is the name of the directory that holds stack information for projects.
 	StackDir = "stacks"
 	// LockDir is the name of the directory that holds locking information for projects.
@@ -61,6 +61,9 @@ type ProjectBackend struct {
 type ProjectOptions struct {
 	// Refresh is the ability to always run a refresh as part of a pulumi update / preview / destroy
 	Refresh string `json:"refresh,omitempty" yaml:"refresh,omitempty"`
+	// Parallelism limits how many resource operations of particular packages or resource types run in parallel at
+	// once, keyed by package name (e.g. `aws`) or resource type (e.g. `aws:route53/record:Record`).
+	Parallelism map[string]int `json:"parallelism,omitempty" yaml:"parallelism,omitempty"`
 }
 
 // Project is a Pulumi project manifest.
@@ -87,7 +90,8 @@ type Project struct {
 	// License is the optional license governing this project's usage.
 	License *string `json:"license,omitempty" yaml:"license,omitempty"`
 
-	// Config has been renamed to StackConfigDir.
+	// Config is an optional declaration of the configuration keys that the project's program expects, which
+	// ConfigSchema parses. A string is accepted for backwards compatibility: it has been renamed to StackConfigDir.
 	Config interface{} `json:"config,omitempty" yaml:"config,omitempty"`
 
 	// StackConfigDir indicates where to store the Pulumi.<stack-name>.yaml files, combined with the folder
@@ -111,6 +115,16 @@ func (proj *Project) Validate() error {
 	if proj.Runtime.Name() == "" {
 		return errors.New("project is missing a 'runtime' attribute")
 	}
+	if proj.Options != nil {
+		for key, limit := range proj.Options.Parallelism {
+			if limit < 1 {
+				return errors.Errorf("the parallelism limit for '%s' must be at least 1", key)
+			}
+		}
+	}
+	if err := proj.validateConfigTypes(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,304 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// The types of configuration values that a project can declare.
+const (
+	ConfigTypeString  = "string"
+	ConfigTypeInteger = "integer"
+	ConfigTypeBoolean = "boolean"
+	ConfigTypeArray   = "array"
+)
+
+// ProjectConfigType declares a configuration key that a project's program expects.
+type ProjectConfigType struct {
+	// Type is the type of the value: string, integer, boolean or array. Defaults to string.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Description is an optional description of the value.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Default is an optional value that is used when a stack does not set one.
+	Default interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	// Required may be set to true to indicate that every stack must set a value.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+	// Secret may be set to true to indicate that the value must be encrypted.
+	Secret bool `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// ConfigSchema returns the declarations of the configuration keys in the project's config section, keyed by their
+// names as written. It returns nil if there is no config section, or if the section is a string, which is the
+// directory that StackConfigDir now holds.
+func (proj *Project) ConfigSchema() (map[string]ProjectConfigType, error) {
+	switch proj.Config.(type) {
+	case nil, string:
+		return nil, nil
+	}
+
+	bytes, err := json.Marshal(stringMapValue(proj.Config))
+	if err != nil {
+		return nil, errors.Wrap(err, "config section must be a string or a map of configuration keys")
+	}
+	// Numbers are decoded as json.Number so that integer defaults are not formatted as floats.
+	dec := json.NewDecoder(strings.NewReader(string(bytes)))
+	dec.UseNumber()
+	var types map[string]ProjectConfigType
+	if err = dec.Decode(&types); err != nil {
+		return nil, errors.Wrap(err, "config section must be a string or a map of configuration keys")
+	}
+	return types, nil
+}
+
+// ConfigTypes returns the configuration keys that the project declares, keyed by their fully qualified keys.
+func (proj *Project) ConfigTypes() (map[config.Key]ProjectConfigType, error) {
+	schema, err := proj.ConfigSchema()
+	if err != nil || len(schema) == 0 {
+		return nil, err
+	}
+
+	types := make(map[config.Key]ProjectConfigType, len(schema))
+	for name, t := range schema {
+		key, err := proj.configKey(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid configuration key '%s'", name)
+		}
+		types[key] = t
+	}
+	return types, nil
+}
+
+func (proj *Project) configKey(name string) (config.Key, error) {
+	if !strings.Contains(name, tokens.TokenDelimiter) {
+		name = fmt.Sprintf("%s:%s", proj.Name, name)
+	}
+	return config.ParseKey(name)
+}
+
+// validateConfigTypes returns an error if any of the configuration keys that the project declares is malformed.
+func (proj *Project) validateConfigTypes() error {
+	types, err := proj.ConfigTypes()
+	if err != nil {
+		return err
+	}
+	for key, t := range types {
+		switch t.Type {
+		case "", ConfigTypeString, ConfigTypeInteger, ConfigTypeBoolean, ConfigTypeArray:
+		default:
+			return errors.Errorf("configuration key '%s' has unknown type '%s'; expected one of string, integer, "+
+				"boolean or array", key, t.Type)
+		}
+		if t.Default == nil {
+			continue
+		}
+		if t.Secret {
+			return errors.Errorf("secret configuration key '%s' cannot have a default value", key)
+		}
+		v, err := t.defaultValue()
+		if err != nil {
+			return errors.Wrapf(err, "default value of configuration key '%s'", key)
+		}
+		if err = t.check(v); err != nil {
+			return errors.Wrapf(err, "default value of configuration key '%s'", key)
+		}
+	}
+	return nil
+}
+
+// ValidateConfigValue returns an error if a stack's configuration value does not conform to the project's
+// declaration of its key. If the project declares any keys, the keys in its namespace that it does not declare are
+// rejected, as they are usually typos.
+func (proj *Project) ValidateConfigValue(key config.Key, v config.Value) error {
+	types, err := proj.ConfigTypes()
+	if err != nil || len(types) == 0 {
+		return err
+	}
+
+	t, ok := types[key]
+	if !ok {
+		if key.Namespace() == string(proj.Name) {
+			return errors.Errorf("configuration key '%s' is not declared in the project's config section", key)
+		}
+		return nil
+	}
+	if t.Secret && !v.Secure() {
+		return errors.Errorf("configuration key '%s' must be a secret; set it with --secret", key)
+	}
+	if err = t.check(v); err != nil {
+		return errors.Wrapf(err, "configuration key '%s'", key)
+	}
+	return nil
+}
+
+// ValidateConfig returns an error if any of a stack's configuration values do not conform to the project's
+// declarations of their keys, or if the stack does not set a required key that has no default value.
+func (proj *Project) ValidateConfig(m config.Map) error {
+	keys := make(config.KeyArray, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Sort(keys)
+
+	var errs []string
+	for _, key := range keys {
+		if err := proj.ValidateConfigValue(key, m[key]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	types, err := proj.ConfigTypes()
+	if err != nil {
+		return err
+	}
+	for _, key := range proj.UnsetConfigKeys(m) {
+		if t := types[key]; t.Required && t.Default == nil {
+			errs = append(errs, fmt.Sprintf("required configuration key '%s' is not set", key))
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Errorf("stack configuration is invalid:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// UnsetConfigKeys returns the configuration keys that the project declares but a stack's configuration does not set,
+// in order.
+func (proj *Project) UnsetConfigKeys(m config.Map) []config.Key {
+	types, err := proj.ConfigTypes()
+	if err != nil {
+		return nil
+	}
+
+	var keys config.KeyArray
+	for key := range types {
+		if _, ok := m[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Sort(keys)
+	return keys
+}
+
+// ApplyConfigDefaults returns a copy of a stack's configuration that also holds the default values of the keys that
+// the project declares but the stack does not set.
+func (proj *Project) ApplyConfigDefaults(m config.Map) (config.Map, error) {
+	types, err := proj.ConfigTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(config.Map, len(m))
+	for key, v := range m {
+		result[key] = v
+	}
+	for _, key := range proj.UnsetConfigKeys(m) {
+		if t := types[key]; t.Default != nil {
+			if result[key], err = t.defaultValue(); err != nil {
+				return nil, errors.Wrapf(err, "default value of configuration key '%s'", key)
+			}
+		}
+	}
+	return result, nil
+}
+
+// defaultValue returns the default value of a configuration key as a configuration value.
+func (t ProjectConfigType) defaultValue() (config.Value, error) {
+	switch d := t.Default.(type) {
+	case string:
+		return config.NewValue(d), nil
+	case json.Number:
+		return config.NewValue(d.String()), nil
+	case bool, int, int64, uint64, float64:
+		return config.NewValue(fmt.Sprintf("%v", d)), nil
+	default:
+		bytes, err := json.Marshal(stringMapValue(d))
+		if err != nil {
+			return config.Value{}, err
+		}
+		return config.NewObjectValue(string(bytes)), nil
+	}
+}
+
+// check returns an error if a configuration value does not have the declared type. The types of encrypted scalar
+// values are not checked, as that would require decrypting them.
+func (t ProjectConfigType) check(v config.Value) error {
+	if t.Type == ConfigTypeArray {
+		obj, err := v.ToObject()
+		if err != nil {
+			return err
+		}
+		if _, ok := obj.([]interface{}); !v.Object() || !ok {
+			return errors.New("expected an array")
+		}
+		return nil
+	}
+
+	typ := t.Type
+	if typ == "" {
+		typ = ConfigTypeString
+	}
+	if v.Object() {
+		return errors.Errorf("expected a %s, not an object or array", typ)
+	}
+	if v.Secure() {
+		return nil
+	}
+
+	raw, err := v.Value(config.NopDecrypter)
+	if err != nil {
+		return err
+	}
+	switch typ {
+	case ConfigTypeInteger:
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return errors.Errorf("expected an integer, got '%s'", raw)
+		}
+	case ConfigTypeBoolean:
+		if _, err := strconv.ParseBool(raw); err != nil {
+			return errors.Errorf("expected a boolean, got '%s'", raw)
+		}
+	}
+	return nil
+}
+
+// stringMapValue converts the `map[interface{}]interface{}` values that YAML produces to the
+// `map[string]interface{}` values that the JSON marshaller supports.
+func stringMapValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[fmt.Sprintf("%v", key)] = stringMapValue(val)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i, val := range t {
+			a[i] = stringMapValue(val)
+		}
+		return a
+	}
+	return v
+}
@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 )
 
 func TestProjectRuntimeInfoRoundtripYAML(t *testing.T) {
@@ -38,3 +41,135 @@ func TestProjectRuntimeInfoRoundtripYAML(t *testing.T) {
 	doTest(yaml.Marshal, yaml.Unmarshal)
 	doTest(json.Marshal, json.Unmarshal)
 }
+
+func TestProjectParallelismOption(t *testing.T) {
+	t.Parallel()
+
+	var proj Project
+	err := yaml.Unmarshal([]byte(`name: proj
+runtime: nodejs
+options:
+  parallelism:
+    aws: 10
+    "aws:route53/record:Record": 2
+`), &proj)
+	assert.NoError(t, err)
+	assert.NoError(t, proj.Validate())
+	assert.Equal(t, map[string]int{"aws": 10, "aws:route53/record:Record": 2}, proj.Options.Parallelism)
+
+	proj.Options.Parallelism["aws"] = 0
+	assert.Error(t, proj.Validate())
+}
+
+func TestProjectConfigTypes(t *testing.T) {
+	t.Parallel()
+
+	doTest := func(marshal func(interface{}) ([]byte, error), unmarshal func([]byte, interface{}) error) {
+		var proj Project
+		err := yaml.Unmarshal([]byte(`name: proj
+runtime: nodejs
+config:
+  region:
+    description: The region to deploy to
+    default: us-west-2
+  instanceCount:
+    type: integer
+    default: 3
+  dbPassword:
+    secret: true
+    required: true
+  zones:
+    type: array
+  aws:profile:
+    required: true
+`), &proj)
+		require.NoError(t, err)
+		require.NoError(t, proj.Validate())
+
+		// The config section roundtrips through both formats.
+		byts, err := marshal(&proj)
+		require.NoError(t, err)
+		var roundtrip Project
+		require.NoError(t, unmarshal(byts, &roundtrip))
+		require.NoError(t, roundtrip.Validate())
+		types, err := roundtrip.ConfigTypes()
+		require.NoError(t, err)
+		assert.Len(t, types, 5)
+		assert.Equal(t, "The region to deploy to", types[config.MustMakeKey("proj", "region")].Description)
+		assert.True(t, types[config.MustMakeKey("aws", "profile")].Required)
+
+		cfg := config.Map{
+			config.MustMakeKey("proj", "dbPassword"): config.NewSecureValue("ciphertext"),
+			config.MustMakeKey("proj", "zones"):      config.NewObjectValue(`["a","b"]`),
+			config.MustMakeKey("aws", "profile"):     config.NewValue("dev"),
+			config.MustMakeKey("aws", "region"):      config.NewValue("us-east-1"),
+		}
+		assert.NoError(t, roundtrip.ValidateConfig(cfg))
+		assert.Equal(t, []config.Key{
+			config.MustMakeKey("proj", "instanceCount"),
+			config.MustMakeKey("proj", "region"),
+		}, roundtrip.UnsetConfigKeys(cfg))
+
+		withDefaults, err := roundtrip.ApplyConfigDefaults(cfg)
+		require.NoError(t, err)
+		assert.Len(t, cfg, 4)
+		assert.Equal(t, config.NewValue("3"), withDefaults[config.MustMakeKey("proj", "instanceCount")])
+		assert.Equal(t, config.NewValue("us-west-2"), withDefaults[config.MustMakeKey("proj", "region")])
+
+		assert.Error(t, roundtrip.ValidateConfigValue(config.MustMakeKey("proj", "dbPassword"), config.NewValue("x")))
+		assert.Error(t, roundtrip.ValidateConfigValue(config.MustMakeKey("proj", "instanceCount"), config.NewValue("x")))
+		assert.Error(t, roundtrip.ValidateConfigValue(config.MustMakeKey("proj", "zones"), config.NewValue("a")))
+		assert.Error(t, roundtrip.ValidateConfigValue(config.MustMakeKey("proj", "regoin"), config.NewValue("x")))
+
+		delete(cfg, config.MustMakeKey("proj", "dbPassword"))
+		err = roundtrip.ValidateConfig(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "required configuration key 'proj:dbPassword' is not set")
+	}
+
+	doTest(yaml.Marshal, yaml.Unmarshal)
+	doTest(json.Marshal, json.Unmarshal)
+}
+
+func TestProjectConfigTypesValidation(t *testing.T) {
+	t.Parallel()
+
+	for _, config := range []string{
+		"{count: {type: number}}",
+		"{count: {type: integer, default: many}}",
+		"{password: {secret: true, default: hunter2}}",
+		"{zones: {type: array, default: a}}",
+	} {
+		var proj Project
+		require.NoError(t, yaml.Unmarshal([]byte("name: proj\nruntime: nodejs\nconfig: "+config), &proj))
+		assert.Error(t, proj.Validate(), config)
+	}
+}
+
+func TestProjectConfigDir(t *testing.T) {
+	t.Parallel()
+
+	// A string config section is the directory that StackConfigDir now holds.
+	var proj Project
+	require.NoError(t, yaml.Unmarshal([]byte("name: proj\nruntime: nodejs\nconfig: stacks\n"), &proj))
+	require.NoError(t, proj.Validate())
+	assert.Equal(t, "stacks", proj.Config)
+	schema, err := proj.ConfigSchema()
+	require.NoError(t, err)
+	assert.Nil(t, schema)
+	byts, err := yaml.Marshal(&proj)
+	require.NoError(t, err)
+	assert.Contains(t, string(byts), "config: stacks")
+}
+
+func TestProjectConfigIntegerDefault(t *testing.T) {
+	t.Parallel()
+
+	var proj Project
+	require.NoError(t, yaml.Unmarshal([]byte(
+		"name: proj\nruntime: nodejs\nconfig: {size: {type: integer, default: 1000000}}\n"), &proj))
+	require.NoError(t, proj.Validate())
+	withDefaults, err := proj.ApplyConfigDefaults(config.Map{})
+	require.NoError(t, err)
+	assert.Equal(t, config.NewValue("1000000"), withDefaults[config.MustMakeKey("proj", "size")])
+}
@@ -844,6 +844,7 @@ func (ctx *Context) registerResource(
 				Remote:                  remote,
 				ReplaceOnChanges:        inputs.replaceOnChanges,
 				RetainOnDelete:          inputs.retainOnDelete,
+				Retries:                 inputs.retries,
 			})
 			if err != nil {
 				logging.V(9).Infof("RegisterResource(%s, %s): error: %v", t, name, err)
@@ -1222,6 +1223,7 @@ type resourceInputs struct {
 	pluginDownloadURL       string
 	replaceOnChanges        []string
 	retainOnDelete          bool
+	retries                 int32
 }
 
 // prepareResourceInputs prepares the inputs for a resource operation, shared between read and register.
@@ -1309,6 +1311,7 @@ func (ctx *Context) prepareResourceInputs(res Resource, props Input, t string, o
 		pluginDownloadURL:       state.pluginDownloadURL,
 		replaceOnChanges:        resOpts.replaceOnChanges,
 		retainOnDelete:          opts.RetainOnDelete,
+		retries:                 int32(opts.Retries),
 	}, nil
 }
 
@@ -301,6 +301,9 @@ type resourceOptions struct {
 	PluginDownloadURL string
 	// If set to True, the providers Delete method will not be called for this resource.
 	RetainOnDelete bool
+	// Retries is the number of times the engine should retry an operation on this resource that fails with an error
+	// the provider reports as retryable. If zero, the deployment's default is used.
+	Retries int
 }
 
 type invokeOptions struct {
@@ -556,3 +559,13 @@ func RetainOnDelete(b bool) ResourceOption {
 		ro.RetainOnDelete = b
 	})
 }
+
+// Retries sets the number of times the engine should retry an operation on this resource that fails with an error
+// the provider reports as retryable, such as a throttling error. This overrides the deployment's default. Deleting a
+// resource that has been removed from the program uses the deployment's default, as this option is not recorded in the
+// stack's state.
+func Retries(n int) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.Retries = n
+	})
+}
@@ -1317,7 +1317,8 @@ proto.pulumirpc.RegisterResourceRequest.toObject = function(includeInstance, msg
     providersMap: (f = msg.getProvidersMap()) ? f.toObject(includeInstance, undefined) : [],
     replaceonchangesList: (f = jspb.Message.getRepeatedField(msg, 23)) == null ? undefined : f,
     plugindownloadurl: jspb.Message.getFieldWithDefault(msg, 24, ""),
-    retainondelete: jspb.Message.getBooleanFieldWithDefault(msg, 25, false)
+    retainondelete: jspb.Message.getBooleanFieldWithDefault(msg, 25, false),
+    retries: jspb.Message.getFieldWithDefault(msg, 26, 0)
   };
 
   if (includeInstance) {
@@ -1460,6 +1461,10 @@ proto.pulumirpc.RegisterResourceRequest.deserializeBinaryFromReader = function(m
       var value = /** @type {boolean} */ (reader.readBool());
       msg.setRetainondelete(value);
       break;
+    case 26:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setRetries(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1660,6 +1665,13 @@ proto.pulumirpc.RegisterResourceRequest.serializeBinaryToWriter = function(messa
       f
     );
   }
+  f = message.getRetries();
+  if (f !== 0) {
+    writer.writeInt32(
+      26,
+      f
+    );
+  }
 };
 
 
@@ -2600,6 +2612,24 @@ proto.pulumirpc.RegisterResourceRequest.prototype.setRetainondelete = function(v
 };
 
 
+/**
+ * optional int32 retries = 26;
+ * @return {number}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetries = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 26, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetries = function(value) {
+  return jspb.Message.setProto3IntField(this, 26, value);
+};
+
+
 
 /**
  * List of repeated fields within this message type.
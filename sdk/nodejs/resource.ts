@@ -690,6 +690,14 @@ export interface CustomResourceOptions extends ResourceOptions {
      */
     import?: ID;
 
+    /**
+     * The number of times the engine should retry an operation on this resource that fails with an error the
+     * provider reports as retryable, such as a throttling error. This overrides the deployment's default. Deleting a
+     * resource that has been removed from the program uses the deployment's default, as this option is not recorded
+     * in the stack's state.
+     */
+    retries?: number;
+
     // !!! IMPORTANT !!! If you add a new field to this type, make sure to add test that verifies
     // that mergeOptions works properly for it.
 }
@@ -320,6 +320,7 @@ export function registerResource(res: Resource, t: string, name: string, custom:
         req.setReplaceonchangesList(opts.replaceOnChanges || []);
         req.setPlugindownloadurl(opts.pluginDownloadURL || "");
         req.setRetainondelete(opts.retainOnDelete || false);
+        req.setRetries((<any>opts).retries || 0);
 
         const customTimeouts = new resproto.RegisterResourceRequest.CustomTimeouts();
         if (opts.customTimeouts != null) {
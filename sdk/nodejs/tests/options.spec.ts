@@ -55,6 +55,17 @@ describe("options", () => {
             }));
         });
 
+        describe("retries", () => {
+            it("keeps value from opts1 if not provided in opts2", () => {
+                const result = mergeOptions({ retries: 3 }, {});
+                assert.strictEqual(result.retries, 3);
+            });
+            it("overwrites value from opts1 if given value in opts2", () => {
+                const result = mergeOptions({ retries: 3 }, { retries: 5 });
+                assert.strictEqual(result.retries, 5);
+            });
+        });
+
         describe("array", () => {
             it("keeps value from opts1 if not provided in opts2", asyncTest(async () => {
                 const result = mergeOptions({ ignoreChanges: ["a"] }, {});
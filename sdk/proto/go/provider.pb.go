@@ -1948,6 +1948,47 @@ func (m *ErrorResourceInitFailed) GetInputs() *_struct.Struct {
 	return nil
 }
 
+// ErrorRetryable is sent as a Detail when a `ResourceProvider.{Create, Read, Update, Delete}` call fails with an
+// error that is likely to be transient, such as the provider being throttled. The engine may retry such calls.
+type ErrorRetryable struct {
+	RetryAfter           float64  `protobuf:"fixed64,1,opt,name=retryAfter,proto3" json:"retryAfter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ErrorRetryable) Reset()         { *m = ErrorRetryable{} }
+func (m *ErrorRetryable) String() string { return proto.CompactTextString(m) }
+func (*ErrorRetryable) ProtoMessage()    {}
+func (*ErrorRetryable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c6a9f3c02af3d1c8, []int{25}
+}
+
+func (m *ErrorRetryable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorRetryable.Unmarshal(m, b)
+}
+func (m *ErrorRetryable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorRetryable.Marshal(b, m, deterministic)
+}
+func (m *ErrorRetryable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorRetryable.Merge(m, src)
+}
+func (m *ErrorRetryable) XXX_Size() int {
+	return xxx_messageInfo_ErrorRetryable.Size(m)
+}
+func (m *ErrorRetryable) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorRetryable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorRetryable proto.InternalMessageInfo
+
+func (m *ErrorRetryable) GetRetryAfter() float64 {
+	if m != nil {
+		return m.RetryAfter
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("pulumirpc.PropertyDiff_Kind", PropertyDiff_Kind_name, PropertyDiff_Kind_value)
 	proto.RegisterEnum("pulumirpc.DiffResponse_DiffChanges", DiffResponse_DiffChanges_name, DiffResponse_DiffChanges_value)
@@ -1990,134 +2031,136 @@ func init() {
 	proto.RegisterMapType((map[string]*ConstructResponse_PropertyDependencies)(nil), "pulumirpc.ConstructResponse.StateDependenciesEntry")
 	proto.RegisterType((*ConstructResponse_PropertyDependencies)(nil), "pulumirpc.ConstructResponse.PropertyDependencies")
 	proto.RegisterType((*ErrorResourceInitFailed)(nil), "pulumirpc.ErrorResourceInitFailed")
+	proto.RegisterType((*ErrorRetryable)(nil), "pulumirpc.ErrorRetryable")
 }
 
 func init() { proto.RegisterFile("provider.proto", fileDescriptor_c6a9f3c02af3d1c8) }
 
 var fileDescriptor_c6a9f3c02af3d1c8 = []byte{
-	// 1947 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0xcd, 0x73, 0x1b, 0x49,
-	0x15, 0xf7, 0xe8, 0xcb, 0xd2, 0xd3, 0x47, 0xe4, 0x66, 0xb1, 0xb5, 0xda, 0x1c, 0x5c, 0xc3, 0x16,
-	0x98, 0x64, 0x57, 0x09, 0xce, 0x01, 0x92, 0xca, 0x56, 0xd6, 0xb1, 0xe4, 0xac, 0x2b, 0x89, 0x63,
-	0xc6, 0x6b, 0x3e, 0x4e, 0xd9, 0xc9, 0xa8, 0xa5, 0x0c, 0x1e, 0xcd, 0xcc, 0xf6, 0xf4, 0x38, 0xe5,
+	// 1957 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xcd, 0x73, 0xdc, 0x48,
+	0x15, 0xb7, 0xe6, 0xcb, 0x33, 0x6f, 0x3e, 0x32, 0x6e, 0x16, 0x5b, 0xd1, 0xba, 0x28, 0x97, 0xd8,
+	0x02, 0x93, 0xec, 0x4e, 0x8c, 0x73, 0x80, 0xa4, 0xb2, 0x95, 0x75, 0x3c, 0xe3, 0xe0, 0x4a, 0xe2,
+	0x18, 0x79, 0xcd, 0xc7, 0x29, 0xab, 0x68, 0x7a, 0x26, 0xc2, 0x1a, 0x49, 0xdb, 0x6a, 0x39, 0xe5,
 	0x3b, 0x07, 0x4e, 0x5c, 0x29, 0x4e, 0x54, 0x71, 0x86, 0xa2, 0x0a, 0xfe, 0x01, 0xfe, 0x09, 0x6e,
-	0xe1, 0xc8, 0x3f, 0xc0, 0x5f, 0x40, 0xf5, 0xd7, 0xa8, 0x5b, 0x33, 0xb2, 0x65, 0x93, 0x82, 0x5b,
-	0xbf, 0xee, 0xd7, 0xaf, 0xdf, 0xfb, 0xbd, 0x8f, 0x7e, 0xdd, 0xd0, 0x89, 0x49, 0x74, 0xee, 0x8f,
-	0x31, 0x19, 0xc4, 0x24, 0xa2, 0x11, 0x6a, 0xc4, 0x69, 0x90, 0xce, 0x7c, 0x12, 0x7b, 0xfd, 0x56,
-	0x1c, 0xa4, 0x53, 0x3f, 0x14, 0x0b, 0xfd, 0x4f, 0xa6, 0x51, 0x34, 0x0d, 0xf0, 0x3d, 0x4e, 0xbd,
-	0x49, 0x27, 0xf7, 0xf0, 0x2c, 0xa6, 0x17, 0x72, 0xf1, 0xf6, 0xe2, 0x62, 0x42, 0x49, 0xea, 0x51,
-	0xb1, 0x6a, 0x7f, 0x06, 0xdd, 0x67, 0x98, 0x9e, 0x78, 0x6f, 0xf1, 0xcc, 0x75, 0xf0, 0xb7, 0x29,
-	0x4e, 0x28, 0xea, 0xc1, 0xfa, 0x39, 0x26, 0x89, 0x1f, 0x85, 0x3d, 0x6b, 0xdb, 0xda, 0xa9, 0x3a,
-	0x8a, 0xb4, 0xef, 0xc2, 0x86, 0xc6, 0x9d, 0xc4, 0x51, 0x98, 0x60, 0xb4, 0x09, 0xb5, 0x84, 0xcf,
-	0x70, 0xee, 0x86, 0x23, 0x29, 0xfb, 0x77, 0x25, 0xe8, 0xee, 0x47, 0xe1, 0xc4, 0x9f, 0xa6, 0x04,
-	0x2b, 0xd9, 0x5f, 0x41, 0xe3, 0xdc, 0x25, 0xbe, 0xfb, 0x26, 0xc0, 0x49, 0xcf, 0xda, 0x2e, 0xef,
-	0x34, 0x77, 0xef, 0x0c, 0x32, 0xbb, 0x06, 0x8b, 0xfc, 0x83, 0x9f, 0x29, 0xe6, 0x51, 0x48, 0xc9,
-	0x85, 0x33, 0xdf, 0x8c, 0xee, 0x42, 0xc5, 0x25, 0xd3, 0xa4, 0x57, 0xda, 0xb6, 0x76, 0x9a, 0xbb,
-	0x5b, 0x03, 0x61, 0xe6, 0x40, 0x99, 0x39, 0x38, 0xe1, 0x66, 0x3a, 0x9c, 0x09, 0x7d, 0x0a, 0x6d,
-	0xd7, 0xf3, 0x70, 0x4c, 0x4f, 0xb0, 0x47, 0x30, 0x4d, 0x7a, 0xe5, 0x6d, 0x6b, 0xa7, 0xee, 0x98,
-	0x93, 0x68, 0x07, 0x6e, 0x89, 0x09, 0x07, 0x27, 0x51, 0x4a, 0x3c, 0x9c, 0xf4, 0x2a, 0x9c, 0x6f,
-	0x71, 0xba, 0xff, 0x18, 0x3a, 0xa6, 0x66, 0xa8, 0x0b, 0xe5, 0x33, 0x7c, 0x21, 0x21, 0x60, 0x43,
-	0xf4, 0x11, 0x54, 0xcf, 0xdd, 0x20, 0xc5, 0x5c, 0xc3, 0x86, 0x23, 0x88, 0x47, 0xa5, 0x9f, 0x58,
-	0xf6, 0xdf, 0x2c, 0xd8, 0xd0, 0x2c, 0x95, 0x38, 0xe6, 0x74, 0xb4, 0x96, 0xe8, 0x98, 0xa4, 0x71,
-	0x1c, 0x11, 0x9a, 0x1c, 0x13, 0x7c, 0xee, 0xe3, 0x77, 0x5c, 0x7e, 0xdd, 0x59, 0x9c, 0x2e, 0xb2,
-	0xa6, 0x5c, 0x68, 0xcd, 0xfc, 0xe4, 0x57, 0x29, 0x8d, 0x53, 0xaa, 0xac, 0x36, 0x27, 0xed, 0xbf,
-	0x5a, 0xf0, 0x71, 0xa6, 0xf5, 0x88, 0x90, 0x88, 0xbc, 0xf4, 0x93, 0xc4, 0x0f, 0xa7, 0xcf, 0xf1,
-	0x45, 0x82, 0x7e, 0x0a, 0xcd, 0xd9, 0x9c, 0x94, 0xae, 0xbd, 0x57, 0xe4, 0xda, 0xc5, 0xad, 0x83,
-	0xf9, 0xd8, 0xd1, 0x65, 0xf4, 0x9f, 0x02, 0xcc, 0x97, 0x10, 0x82, 0x4a, 0xe8, 0xce, 0xb0, 0x44,
-	0x98, 0x8f, 0xd1, 0x36, 0x34, 0xc7, 0x38, 0xf1, 0x88, 0x1f, 0x53, 0x16, 0xad, 0x02, 0x68, 0x7d,
-	0xca, 0x7e, 0x6f, 0x41, 0xfb, 0x30, 0x3c, 0x8f, 0xce, 0xb2, 0x08, 0xec, 0x42, 0x99, 0x46, 0x67,
-	0xca, 0x51, 0x34, 0x3a, 0xbb, 0x5e, 0x24, 0xf5, 0xa1, 0xae, 0xd2, 0x92, 0xc3, 0xd9, 0x70, 0x32,
-	0x5a, 0x4f, 0x9c, 0x0a, 0x5f, 0x52, 0x64, 0x91, 0x2f, 0xaa, 0xc5, 0xbe, 0xf8, 0x0c, 0x36, 0x44,
-	0x6e, 0x0f, 0xa3, 0x77, 0x61, 0x10, 0xb9, 0xe3, 0x53, 0xe7, 0x45, 0xaf, 0xc6, 0xa5, 0xe5, 0x17,
-	0xec, 0x73, 0xe8, 0x28, 0xeb, 0x64, 0x14, 0xdd, 0x83, 0x1a, 0xc1, 0x34, 0x25, 0x22, 0x77, 0x2f,
-	0x31, 0x47, 0xb2, 0xa1, 0x07, 0x50, 0x9f, 0xb8, 0x7e, 0x90, 0x12, 0xcc, 0x10, 0x28, 0xf3, 0x2d,
-	0x9a, 0xd7, 0xde, 0x62, 0xef, 0xec, 0x40, 0xac, 0x3b, 0x19, 0xa3, 0xfd, 0x8f, 0x2a, 0x34, 0xf7,
-	0xdd, 0x20, 0xf8, 0x40, 0xa0, 0x9e, 0xc2, 0x2d, 0x97, 0x4c, 0x87, 0x38, 0xc6, 0xe1, 0x18, 0x87,
-	0x9e, 0xcf, 0x43, 0x95, 0xa9, 0x72, 0x57, 0x57, 0x65, 0x7e, 0xde, 0x60, 0xcf, 0xe4, 0x16, 0xc5,
-	0x61, 0x51, 0x86, 0xe1, 0xab, 0xca, 0x72, 0x5f, 0x55, 0x4d, 0x5f, 0x15, 0x7a, 0xa0, 0xbd, 0xc4,
-	0x03, 0x4c, 0x4e, 0x4c, 0xa2, 0x5f, 0x61, 0x8f, 0x4a, 0x2f, 0x29, 0x92, 0xe5, 0x7f, 0x42, 0x5d,
-	0xef, 0xac, 0xb7, 0x2e, 0xf2, 0x9f, 0x13, 0xe8, 0x11, 0xd4, 0x3c, 0x9e, 0x09, 0xbd, 0x3a, 0xb7,
-	0xd0, 0x5e, 0x62, 0xa1, 0x48, 0x17, 0x61, 0x98, 0xdc, 0x81, 0xee, 0x40, 0x57, 0x8c, 0x44, 0x31,
-	0xe0, 0x89, 0xd6, 0xd8, 0x2e, 0xef, 0x34, 0x9c, 0xdc, 0x3c, 0xab, 0xca, 0x63, 0x72, 0xe1, 0xa4,
-	0x61, 0x0f, 0x78, 0xa0, 0x49, 0x8a, 0x63, 0xe2, 0x12, 0x37, 0x08, 0x70, 0xd0, 0x6b, 0xf2, 0xea,
-	0x9e, 0xd1, 0x2c, 0x4a, 0x67, 0x51, 0xe8, 0xd3, 0x88, 0x8c, 0xc2, 0x71, 0x1c, 0xf9, 0x21, 0xed,
-	0xb5, 0xb8, 0xee, 0x8b, 0xd3, 0xfd, 0x3b, 0xf0, 0xd1, 0x1e, 0x99, 0xa6, 0x33, 0x1c, 0x52, 0x03,
-	0x71, 0x04, 0x95, 0x94, 0x84, 0x22, 0xfd, 0x1b, 0x0e, 0x1f, 0xf7, 0x23, 0xce, 0x9b, 0x73, 0x57,
-	0x41, 0xc5, 0xdc, 0xd3, 0x2b, 0xe6, 0xa5, 0xce, 0xcf, 0x9d, 0xac, 0x95, 0xd7, 0xfe, 0x43, 0x68,
-	0x6a, 0xe8, 0x5d, 0xab, 0x32, 0xff, 0xbb, 0x04, 0x2d, 0x71, 0xd4, 0x4d, 0xd3, 0xe9, 0x35, 0x20,
-	0x31, 0x32, 0xa2, 0xb9, 0x94, 0x2f, 0x87, 0xda, 0x29, 0x03, 0x27, 0xb7, 0x43, 0x38, 0xbe, 0x40,
-	0x94, 0x91, 0xaf, 0xe5, 0x15, 0xf3, 0xb5, 0xbf, 0x03, 0x28, 0x7f, 0x46, 0xa1, 0xb7, 0xbe, 0x85,
-	0xad, 0x25, 0xda, 0x14, 0x00, 0xf9, 0xa5, 0xe9, 0xb0, 0x3b, 0xab, 0xdb, 0xa7, 0x83, 0xfe, 0x47,
-	0x0b, 0x5a, 0x5c, 0x6f, 0xad, 0x9a, 0x28, 0xc4, 0x1b, 0x0e, 0x1b, 0xb2, 0x6a, 0x12, 0x05, 0xe3,
-	0xab, 0xab, 0x09, 0x63, 0x62, 0xcc, 0x21, 0x7e, 0x27, 0x6e, 0xbb, 0xcb, 0x98, 0x19, 0x13, 0xfa,
-	0x3e, 0x74, 0x12, 0x76, 0x6c, 0xe8, 0xe1, 0xa3, 0x74, 0xf6, 0x46, 0x56, 0x8a, 0xaa, 0xb3, 0x30,
-	0x6b, 0xa7, 0xd0, 0x96, 0x3a, 0xce, 0x23, 0xc3, 0x0f, 0xf9, 0x6d, 0x79, 0x55, 0x64, 0x08, 0xb6,
-	0x9b, 0x15, 0xda, 0xa7, 0x12, 0x1a, 0xb9, 0x22, 0x4b, 0x5a, 0x8c, 0x09, 0x55, 0x8e, 0xc8, 0x68,
-	0x96, 0xf2, 0x04, 0xbb, 0x49, 0x76, 0x11, 0x4a, 0xca, 0xfe, 0x8b, 0x05, 0xcd, 0xa1, 0x3f, 0x99,
-	0x28, 0x78, 0x3b, 0x50, 0xf2, 0xc7, 0x72, 0x77, 0xc9, 0x1f, 0x2b, 0xb8, 0x4b, 0x79, 0xb8, 0xcb,
-	0xd7, 0x81, 0xbb, 0xb2, 0x0a, 0xdc, 0x9f, 0x42, 0xdb, 0x9f, 0x86, 0x11, 0xc1, 0xfb, 0x6f, 0xdd,
-	0x70, 0xca, 0xaf, 0x41, 0x16, 0x7b, 0xe6, 0xa4, 0xfd, 0x77, 0x0b, 0x5a, 0xc7, 0xd2, 0x2c, 0xa6,
-	0x39, 0xba, 0x0f, 0x95, 0x33, 0x3f, 0x14, 0x4a, 0x77, 0x76, 0x6f, 0x6b, 0xb8, 0xe9, 0x6c, 0x83,
-	0xe7, 0x7e, 0x38, 0x76, 0x38, 0x27, 0xba, 0x0d, 0x0d, 0x8e, 0x3b, 0x9b, 0x97, 0x1d, 0xd2, 0x7c,
-	0xc2, 0xfe, 0x06, 0x2a, 0x8c, 0x17, 0xad, 0x43, 0x79, 0x6f, 0x38, 0xec, 0xae, 0xa1, 0x5b, 0xd0,
-	0xdc, 0x1b, 0x0e, 0x5f, 0x3b, 0xa3, 0xe3, 0x17, 0x7b, 0xfb, 0xa3, 0xae, 0x85, 0x00, 0x6a, 0xc3,
-	0xd1, 0x8b, 0xd1, 0xd7, 0xa3, 0x6e, 0x09, 0x21, 0xe8, 0x88, 0x71, 0xb6, 0x5e, 0x66, 0xeb, 0xa7,
-	0xc7, 0xc3, 0xbd, 0xaf, 0x47, 0xdd, 0x0a, 0x5b, 0x17, 0xe3, 0x6c, 0xbd, 0x6a, 0xff, 0xb3, 0x0c,
-	0x2d, 0x01, 0xba, 0x8c, 0x97, 0x3e, 0xd4, 0x09, 0x8e, 0x03, 0xd7, 0xc3, 0x2a, 0xe1, 0x32, 0x9a,
-	0x5d, 0x22, 0x09, 0x15, 0x3d, 0x71, 0x89, 0x2f, 0x29, 0x12, 0xdd, 0x87, 0xef, 0x8c, 0x71, 0x80,
-	0x29, 0x7e, 0x8a, 0x27, 0x11, 0x6b, 0x16, 0xf9, 0x0e, 0xd9, 0xc8, 0x15, 0x2d, 0xa1, 0x2f, 0x60,
-	0xdd, 0x93, 0xd8, 0x56, 0x38, 0x5a, 0xdf, 0xd3, 0xd0, 0xd2, 0x35, 0xe2, 0x84, 0x44, 0xdc, 0x51,
-	0x7b, 0x58, 0x6d, 0x1c, 0xfb, 0x93, 0x89, 0x72, 0x8c, 0x20, 0xd0, 0x4b, 0x68, 0x8d, 0x31, 0x75,
-	0xfd, 0x00, 0x8f, 0x39, 0xa0, 0x35, 0x1e, 0xbf, 0x3f, 0x5c, 0x2a, 0x59, 0xe3, 0x15, 0x95, 0xcc,
-	0xd8, 0xce, 0x2e, 0x9a, 0xb7, 0x6e, 0xa2, 0x73, 0xf1, 0x4b, 0xb2, 0xee, 0x2c, 0x4e, 0xf7, 0x7f,
-	0x01, 0x1b, 0x39, 0x61, 0x05, 0x85, 0xe8, 0x73, 0xb3, 0x10, 0x6d, 0x2d, 0x09, 0x10, 0xbd, 0xea,
-	0x7c, 0x21, 0x92, 0x42, 0x02, 0x80, 0xba, 0xd0, 0x1a, 0x1e, 0x1e, 0x1c, 0xbc, 0x3e, 0x3d, 0x7a,
-	0x7e, 0xf4, 0xea, 0xe7, 0x47, 0xdd, 0x35, 0xd4, 0x86, 0x06, 0x9f, 0x39, 0x7a, 0x75, 0xc4, 0x02,
-	0x42, 0x91, 0x27, 0xaf, 0x5e, 0x8e, 0xba, 0x25, 0xfb, 0xb7, 0x16, 0xb4, 0xf7, 0x09, 0x76, 0x29,
-	0x5e, 0x5e, 0xb5, 0x7e, 0x0c, 0x20, 0x93, 0x53, 0xdc, 0x01, 0x97, 0xe6, 0x87, 0xc6, 0xca, 0xe2,
-	0x81, 0xfa, 0x33, 0x1c, 0xa5, 0x94, 0x7b, 0xda, 0x72, 0x14, 0x29, 0xda, 0x0d, 0xd1, 0xf6, 0x8b,
-	0x26, 0x5d, 0x91, 0xf6, 0x2f, 0xa1, 0xa3, 0xf4, 0x91, 0x11, 0xb7, 0x98, 0xe7, 0x37, 0x55, 0xc7,
-	0xfe, 0xbd, 0x05, 0x4d, 0x07, 0xbb, 0xe3, 0xd5, 0x0b, 0x88, 0x79, 0x54, 0x79, 0x75, 0xcb, 0xe7,
-	0x55, 0xb5, 0xb2, 0x52, 0x55, 0xb5, 0x7f, 0x63, 0x41, 0x4b, 0xe8, 0xf6, 0x81, 0xad, 0xd6, 0x54,
-	0x29, 0xaf, 0xa6, 0xca, 0xbf, 0x2c, 0x68, 0x9f, 0xc6, 0x63, 0x2d, 0x24, 0xfe, 0x9f, 0x95, 0x56,
-	0x8b, 0xa1, 0xaa, 0x19, 0x43, 0xb9, 0x1a, 0x5c, 0x2b, 0xa8, 0xc1, 0x7a, 0xa4, 0xad, 0x9b, 0x91,
-	0x76, 0x08, 0x1d, 0x65, 0xa6, 0xc4, 0xdc, 0xc4, 0xd8, 0x5a, 0x3d, 0xb2, 0x7e, 0x6d, 0x41, 0x7b,
-	0xc8, 0x8b, 0xd8, 0xff, 0x20, 0xb6, 0x34, 0x44, 0x2a, 0x06, 0x22, 0xf6, 0x1f, 0xd6, 0xf9, 0x57,
-	0x85, 0xf8, 0x19, 0xd1, 0xbe, 0x41, 0x54, 0x67, 0x6f, 0x2d, 0xe9, 0xec, 0x4b, 0x7a, 0x67, 0xff,
-	0x24, 0xeb, 0xec, 0x45, 0x5b, 0xf6, 0x03, 0xf3, 0xf1, 0x6b, 0x08, 0x2f, 0x6c, 0xef, 0xe7, 0x2d,
-	0x7b, 0x65, 0x69, 0xcb, 0x5e, 0xbd, 0xba, 0x65, 0xaf, 0x15, 0xb6, 0xec, 0xac, 0xd9, 0xa3, 0x17,
-	0x31, 0x96, 0xaf, 0x11, 0x3e, 0xce, 0xde, 0xd4, 0x75, 0xed, 0x4d, 0xbd, 0x09, 0xb5, 0xd8, 0x25,
-	0x38, 0xa4, 0xbd, 0x86, 0xe8, 0x22, 0x04, 0xa5, 0xa5, 0x03, 0xac, 0xd6, 0xef, 0x7c, 0x03, 0x1b,
-	0xe2, 0xc2, 0xd5, 0x1b, 0xe1, 0x26, 0x87, 0x66, 0xf7, 0x32, 0x68, 0x0e, 0x17, 0x37, 0x09, 0x94,
-	0xf2, 0xc2, 0xa4, 0x87, 0x28, 0xf3, 0x50, 0x4b, 0x85, 0x28, 0x27, 0xd1, 0x57, 0xd0, 0x50, 0x2f,
-	0xbd, 0xa4, 0xd7, 0x2e, 0xfa, 0x66, 0x32, 0xcf, 0x3c, 0x56, 0xcc, 0xf2, 0x9b, 0x29, 0xdb, 0xcc,
-	0xce, 0x70, 0x03, 0xdf, 0x4d, 0x70, 0xd2, 0xeb, 0x88, 0xab, 0x59, 0x92, 0xc8, 0x66, 0x77, 0xa2,
-	0x66, 0xda, 0x2d, 0xbe, 0x6c, 0xcc, 0x15, 0xbe, 0xd8, 0xba, 0xc5, 0x2f, 0x36, 0xf6, 0xa6, 0xca,
-	0xee, 0xaa, 0xab, 0xba, 0xf4, 0x9b, 0x3f, 0x71, 0xfa, 0xe7, 0xb0, 0x59, 0x8c, 0x70, 0x81, 0x94,
-	0x03, 0xf3, 0x5a, 0xbd, 0x7f, 0x05, 0x84, 0x39, 0xdd, 0xf5, 0x73, 0x1f, 0x43, 0xc7, 0x44, 0xf9,
-	0x5a, 0x0f, 0xb3, 0xf7, 0x25, 0xfe, 0x65, 0xa6, 0x8e, 0x94, 0x75, 0x27, 0x7f, 0xe5, 0x7e, 0xce,
-	0x53, 0x93, 0xe2, 0xab, 0x0a, 0xbd, 0xe0, 0x42, 0x2e, 0x6c, 0xf0, 0x41, 0xc1, 0xd7, 0xc3, 0x83,
-	0x62, 0x63, 0x65, 0x87, 0x73, 0xb2, 0xb8, 0x4b, 0x06, 0x69, 0x4e, 0xda, 0xb5, 0xdc, 0xfa, 0x0e,
-	0x36, 0x8b, 0x05, 0x17, 0x60, 0xf5, 0xcc, 0xf4, 0xcd, 0x8f, 0x2e, 0x55, 0xf7, 0x0a, 0xe7, 0xd8,
-	0x7f, 0xb6, 0x60, 0x8b, 0xff, 0xcb, 0xa9, 0x8f, 0xa8, 0xc3, 0xd0, 0xa7, 0x07, 0xbc, 0xed, 0xfa,
-	0x70, 0x17, 0x6a, 0x0f, 0xd6, 0xc5, 0x8b, 0x44, 0x40, 0xdc, 0x70, 0x14, 0x79, 0xed, 0x5b, 0x7f,
-	0xf7, 0x4f, 0x75, 0xe8, 0x2a, 0x55, 0x55, 0x54, 0xb1, 0xa4, 0xcf, 0x7e, 0xa7, 0xd1, 0x27, 0x1a,
-	0x1e, 0x8b, 0x3f, 0xdc, 0xfd, 0xdb, 0xc5, 0x8b, 0x02, 0x2c, 0x7b, 0x0d, 0x3d, 0x85, 0x26, 0x7f,
-	0x75, 0x89, 0x1c, 0x43, 0xb9, 0x77, 0x9a, 0x92, 0xd3, 0xcb, 0x2f, 0x64, 0x32, 0x9e, 0x00, 0xf0,
-	0xfe, 0x52, 0xd6, 0xf6, 0x5c, 0xab, 0x2c, 0x24, 0x6c, 0x2d, 0x69, 0xa1, 0xed, 0x35, 0x66, 0x4e,
-	0xf6, 0x67, 0x6a, 0x98, 0xb3, 0xf8, 0x49, 0x6e, 0x98, 0x93, 0xfb, 0x57, 0xe6, 0xaa, 0xd4, 0xc4,
-	0x2f, 0x21, 0xd2, 0x15, 0x36, 0xbe, 0x45, 0xfb, 0x1f, 0x17, 0xac, 0x64, 0x02, 0x9e, 0x41, 0xeb,
-	0x84, 0x12, 0xec, 0xce, 0xfe, 0x2b, 0x31, 0xf7, 0x2d, 0xf4, 0x10, 0x2a, 0xfb, 0x6e, 0x10, 0x18,
-	0x70, 0x68, 0x5f, 0x3b, 0x06, 0x1c, 0xfa, 0x0f, 0x82, 0xbd, 0x86, 0x1e, 0x43, 0x95, 0x43, 0x7c,
-	0x33, 0x6f, 0x3c, 0x84, 0x0a, 0x7f, 0x79, 0xdc, 0xc0, 0x0f, 0x4f, 0xa0, 0x26, 0x1a, 0x6b, 0xc3,
-	0x6c, 0xa3, 0xf7, 0x37, 0xcc, 0x36, 0xbb, 0x70, 0x71, 0x36, 0xeb, 0x50, 0x8d, 0xb3, 0xb5, 0x76,
-	0xda, 0x38, 0x5b, 0x6f, 0x65, 0xc5, 0xd9, 0xa2, 0xd5, 0x32, 0xce, 0x36, 0x9a, 0x4c, 0xe3, 0x6c,
-	0xb3, 0x2f, 0xe3, 0xa8, 0xd5, 0x44, 0x7f, 0x65, 0x08, 0x30, 0x5a, 0xae, 0xfe, 0x66, 0x2e, 0xdb,
-	0x46, 0xb3, 0x98, 0x5e, 0x64, 0x21, 0x28, 0x6a, 0xc9, 0x62, 0x08, 0x1a, 0xd5, 0x7f, 0x31, 0x04,
-	0xcd, 0xf2, 0x63, 0xaf, 0xa1, 0x47, 0x50, 0xdb, 0x77, 0x43, 0x0f, 0x33, 0xd7, 0x17, 0x9e, 0x76,
-	0x89, 0x16, 0x5f, 0x42, 0xfb, 0x19, 0xa6, 0xc7, 0xfc, 0xeb, 0xf5, 0x30, 0x9c, 0x44, 0x4b, 0x45,
-	0x7c, 0x57, 0x7f, 0xf6, 0x65, 0xec, 0xf6, 0xda, 0x9b, 0x1a, 0x67, 0x7c, 0xf0, 0x9f, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x9c, 0xb7, 0x22, 0x0e, 0x52, 0x1b, 0x00, 0x00,
+	0xcb, 0x91, 0x7f, 0x80, 0xbf, 0x80, 0xea, 0x0f, 0x69, 0xba, 0x47, 0x1a, 0x7f, 0x91, 0x82, 0x9b,
+	0x5e, 0xbf, 0xd7, 0xdd, 0xef, 0xfd, 0xde, 0x47, 0xbf, 0x6e, 0x41, 0x2f, 0x26, 0xd1, 0xb9, 0x3f,
+	0xc6, 0x64, 0x10, 0x93, 0x88, 0x46, 0xa8, 0x15, 0xa7, 0x41, 0x3a, 0xf3, 0x49, 0xec, 0x59, 0x9d,
+	0x38, 0x48, 0xa7, 0x7e, 0x28, 0x18, 0xd6, 0xc7, 0xd3, 0x28, 0x9a, 0x06, 0xf8, 0x01, 0xa7, 0xde,
+	0xa6, 0x93, 0x07, 0x78, 0x16, 0xd3, 0x0b, 0xc9, 0xdc, 0x5c, 0x64, 0x26, 0x94, 0xa4, 0x1e, 0x15,
+	0x5c, 0xfb, 0x53, 0xe8, 0x3f, 0xc7, 0xf4, 0xc4, 0x7b, 0x87, 0x67, 0xae, 0x83, 0xbf, 0x4e, 0x71,
+	0x42, 0x91, 0x09, 0xab, 0xe7, 0x98, 0x24, 0x7e, 0x14, 0x9a, 0xc6, 0x96, 0xb1, 0x5d, 0x77, 0x32,
+	0xd2, 0xbe, 0x0f, 0x6b, 0x8a, 0x74, 0x12, 0x47, 0x61, 0x82, 0xd1, 0x3a, 0x34, 0x12, 0x3e, 0xc2,
+	0xa5, 0x5b, 0x8e, 0xa4, 0xec, 0xdf, 0x55, 0xa0, 0xbf, 0x1f, 0x85, 0x13, 0x7f, 0x9a, 0x12, 0x9c,
+	0xad, 0xfd, 0x13, 0x68, 0x9d, 0xbb, 0xc4, 0x77, 0xdf, 0x06, 0x38, 0x31, 0x8d, 0xad, 0xea, 0x76,
+	0x7b, 0xf7, 0xde, 0x20, 0xb7, 0x6b, 0xb0, 0x28, 0x3f, 0xf8, 0x59, 0x26, 0x3c, 0x0a, 0x29, 0xb9,
+	0x70, 0xe6, 0x93, 0xd1, 0x7d, 0xa8, 0xb9, 0x64, 0x9a, 0x98, 0x95, 0x2d, 0x63, 0xbb, 0xbd, 0xbb,
+	0x31, 0x10, 0x66, 0x0e, 0x32, 0x33, 0x07, 0x27, 0xdc, 0x4c, 0x87, 0x0b, 0xa1, 0x4f, 0xa0, 0xeb,
+	0x7a, 0x1e, 0x8e, 0xe9, 0x09, 0xf6, 0x08, 0xa6, 0x89, 0x59, 0xdd, 0x32, 0xb6, 0x9b, 0x8e, 0x3e,
+	0x88, 0xb6, 0xe1, 0x8e, 0x18, 0x70, 0x70, 0x12, 0xa5, 0xc4, 0xc3, 0x89, 0x59, 0xe3, 0x72, 0x8b,
+	0xc3, 0xd6, 0x13, 0xe8, 0xe9, 0x9a, 0xa1, 0x3e, 0x54, 0xcf, 0xf0, 0x85, 0x84, 0x80, 0x7d, 0xa2,
+	0x8f, 0xa0, 0x7e, 0xee, 0x06, 0x29, 0xe6, 0x1a, 0xb6, 0x1c, 0x41, 0x3c, 0xae, 0xfc, 0xd8, 0xb0,
+	0xff, 0x66, 0xc0, 0x9a, 0x62, 0xa9, 0xc4, 0xb1, 0xa0, 0xa3, 0xb1, 0x44, 0xc7, 0x24, 0x8d, 0xe3,
+	0x88, 0xd0, 0xe4, 0x98, 0xe0, 0x73, 0x1f, 0xbf, 0xe7, 0xeb, 0x37, 0x9d, 0xc5, 0xe1, 0x32, 0x6b,
+	0xaa, 0xa5, 0xd6, 0xcc, 0x77, 0x7e, 0x9d, 0xd2, 0x38, 0xa5, 0x99, 0xd5, 0xfa, 0xa0, 0xfd, 0x57,
+	0x03, 0xee, 0xe6, 0x5a, 0x8f, 0x08, 0x89, 0xc8, 0x2b, 0x3f, 0x49, 0xfc, 0x70, 0xfa, 0x02, 0x5f,
+	0x24, 0xe8, 0xa7, 0xd0, 0x9e, 0xcd, 0x49, 0xe9, 0xda, 0x07, 0x65, 0xae, 0x5d, 0x9c, 0x3a, 0x98,
+	0x7f, 0x3b, 0xea, 0x1a, 0xd6, 0x33, 0x80, 0x39, 0x0b, 0x21, 0xa8, 0x85, 0xee, 0x0c, 0x4b, 0x84,
+	0xf9, 0x37, 0xda, 0x82, 0xf6, 0x18, 0x27, 0x1e, 0xf1, 0x63, 0xca, 0xa2, 0x55, 0x00, 0xad, 0x0e,
+	0xd9, 0xdf, 0x18, 0xd0, 0x3d, 0x0c, 0xcf, 0xa3, 0xb3, 0x3c, 0x02, 0xfb, 0x50, 0xa5, 0xd1, 0x59,
+	0xe6, 0x28, 0x1a, 0x9d, 0xdd, 0x2c, 0x92, 0x2c, 0x68, 0x66, 0x69, 0xc9, 0xe1, 0x6c, 0x39, 0x39,
+	0xad, 0x26, 0x4e, 0x8d, 0xb3, 0x32, 0xb2, 0xcc, 0x17, 0xf5, 0x72, 0x5f, 0x7c, 0x0a, 0x6b, 0x22,
+	0xb7, 0x87, 0xd1, 0xfb, 0x30, 0x88, 0xdc, 0xf1, 0xa9, 0xf3, 0xd2, 0x6c, 0xf0, 0xd5, 0x8a, 0x0c,
+	0xfb, 0x1c, 0x7a, 0x99, 0x75, 0x32, 0x8a, 0x1e, 0x40, 0x83, 0x60, 0x9a, 0x12, 0x91, 0xbb, 0x97,
+	0x98, 0x23, 0xc5, 0xd0, 0x43, 0x68, 0x4e, 0x5c, 0x3f, 0x48, 0x09, 0x66, 0x08, 0x54, 0xf9, 0x14,
+	0xc5, 0x6b, 0xef, 0xb0, 0x77, 0x76, 0x20, 0xf8, 0x4e, 0x2e, 0x68, 0xff, 0xa3, 0x0e, 0xed, 0x7d,
+	0x37, 0x08, 0x3e, 0x10, 0xa8, 0xa7, 0x70, 0xc7, 0x25, 0xd3, 0x21, 0x8e, 0x71, 0x38, 0xc6, 0xa1,
+	0xe7, 0xf3, 0x50, 0x65, 0xaa, 0xdc, 0x57, 0x55, 0x99, 0xef, 0x37, 0xd8, 0xd3, 0xa5, 0x45, 0x71,
+	0x58, 0x5c, 0x43, 0xf3, 0x55, 0x6d, 0xb9, 0xaf, 0xea, 0xba, 0xaf, 0x4a, 0x3d, 0xd0, 0x5d, 0xe2,
+	0x01, 0xb6, 0x4e, 0x4c, 0xa2, 0x5f, 0x61, 0x8f, 0x4a, 0x2f, 0x65, 0x24, 0xcb, 0xff, 0x84, 0xba,
+	0xde, 0x99, 0xb9, 0x2a, 0xf2, 0x9f, 0x13, 0xe8, 0x31, 0x34, 0x3c, 0x9e, 0x09, 0x66, 0x93, 0x5b,
+	0x68, 0x2f, 0xb1, 0x50, 0xa4, 0x8b, 0x30, 0x4c, 0xce, 0x40, 0xf7, 0xa0, 0x2f, 0xbe, 0x44, 0x31,
+	0xe0, 0x89, 0xd6, 0xda, 0xaa, 0x6e, 0xb7, 0x9c, 0xc2, 0x38, 0xab, 0xca, 0x63, 0x72, 0xe1, 0xa4,
+	0xa1, 0x09, 0x3c, 0xd0, 0x24, 0xc5, 0x31, 0x71, 0x89, 0x1b, 0x04, 0x38, 0x30, 0xdb, 0xbc, 0xba,
+	0xe7, 0x34, 0x8b, 0xd2, 0x59, 0x14, 0xfa, 0x34, 0x22, 0xa3, 0x70, 0x1c, 0x47, 0x7e, 0x48, 0xcd,
+	0x0e, 0xd7, 0x7d, 0x71, 0xd8, 0xba, 0x07, 0x1f, 0xed, 0x91, 0x69, 0x3a, 0xc3, 0x21, 0xd5, 0x10,
+	0x47, 0x50, 0x4b, 0x49, 0x28, 0xd2, 0xbf, 0xe5, 0xf0, 0x6f, 0x2b, 0xe2, 0xb2, 0x05, 0x77, 0x95,
+	0x54, 0xcc, 0x3d, 0xb5, 0x62, 0x5e, 0xea, 0xfc, 0xc2, 0xce, 0x4a, 0x79, 0xb5, 0x1e, 0x41, 0x5b,
+	0x41, 0xef, 0x46, 0x95, 0xf9, 0xdf, 0x15, 0xe8, 0x88, 0xad, 0x6e, 0x9b, 0x4e, 0x6f, 0x00, 0x89,
+	0x2f, 0x2d, 0x9a, 0x2b, 0xc5, 0x72, 0xa8, 0xec, 0x32, 0x70, 0x0a, 0x33, 0x84, 0xe3, 0x4b, 0x96,
+	0xd2, 0xf2, 0xb5, 0x7a, 0xcd, 0x7c, 0xb5, 0xb6, 0x01, 0x15, 0xf7, 0x28, 0xf5, 0xd6, 0xd7, 0xb0,
+	0xb1, 0x44, 0x9b, 0x12, 0x20, 0xbf, 0xd0, 0x1d, 0x76, 0xef, 0xfa, 0xf6, 0xa9, 0xa0, 0xff, 0xd1,
+	0x80, 0x0e, 0xd7, 0x5b, 0xa9, 0x26, 0x19, 0xe2, 0x2d, 0x87, 0x7d, 0xb2, 0x6a, 0x12, 0x05, 0xe3,
+	0xab, 0xab, 0x09, 0x13, 0x62, 0xc2, 0x21, 0x7e, 0x2f, 0x4e, 0xbb, 0xcb, 0x84, 0x99, 0x10, 0xfa,
+	0x1e, 0xf4, 0x12, 0xb6, 0x6d, 0xe8, 0xe1, 0xa3, 0x74, 0xf6, 0x56, 0x56, 0x8a, 0xba, 0xb3, 0x30,
+	0x6a, 0xa7, 0xd0, 0x95, 0x3a, 0xce, 0x23, 0xc3, 0x0f, 0xf9, 0x69, 0x79, 0x55, 0x64, 0x08, 0xb1,
+	0xdb, 0x15, 0xda, 0x67, 0xd0, 0x51, 0x39, 0xb2, 0xa4, 0xc5, 0x98, 0xd0, 0xcc, 0x11, 0x39, 0xcd,
+	0x52, 0x9e, 0x60, 0x37, 0xc9, 0x0f, 0x42, 0x49, 0xd9, 0x7f, 0x31, 0xa0, 0x3d, 0xf4, 0x27, 0x93,
+	0x0c, 0xde, 0x1e, 0x54, 0xfc, 0xb1, 0x9c, 0x5d, 0xf1, 0xc7, 0x19, 0xdc, 0x95, 0x22, 0xdc, 0xd5,
+	0x9b, 0xc0, 0x5d, 0xbb, 0x0e, 0xdc, 0x9f, 0x40, 0xd7, 0x9f, 0x86, 0x11, 0xc1, 0xfb, 0xef, 0xdc,
+	0x70, 0xca, 0x8f, 0x41, 0x16, 0x7b, 0xfa, 0xa0, 0xfd, 0x77, 0x03, 0x3a, 0xc7, 0xd2, 0x2c, 0xa6,
+	0x39, 0xda, 0x81, 0xda, 0x99, 0x1f, 0x0a, 0xa5, 0x7b, 0xbb, 0x9b, 0x0a, 0x6e, 0xaa, 0xd8, 0xe0,
+	0x85, 0x1f, 0x8e, 0x1d, 0x2e, 0x89, 0x36, 0xa1, 0xc5, 0x71, 0x67, 0xe3, 0xb2, 0x43, 0x9a, 0x0f,
+	0xd8, 0x5f, 0x41, 0x8d, 0xc9, 0xa2, 0x55, 0xa8, 0xee, 0x0d, 0x87, 0xfd, 0x15, 0x74, 0x07, 0xda,
+	0x7b, 0xc3, 0xe1, 0x1b, 0x67, 0x74, 0xfc, 0x72, 0x6f, 0x7f, 0xd4, 0x37, 0x10, 0x40, 0x63, 0x38,
+	0x7a, 0x39, 0xfa, 0x72, 0xd4, 0xaf, 0x20, 0x04, 0x3d, 0xf1, 0x9d, 0xf3, 0xab, 0x8c, 0x7f, 0x7a,
+	0x3c, 0xdc, 0xfb, 0x72, 0xd4, 0xaf, 0x31, 0xbe, 0xf8, 0xce, 0xf9, 0x75, 0xfb, 0x9f, 0x55, 0xe8,
+	0x08, 0xd0, 0x65, 0xbc, 0x58, 0xd0, 0x24, 0x38, 0x0e, 0x5c, 0x0f, 0x67, 0x09, 0x97, 0xd3, 0xec,
+	0x10, 0x49, 0xa8, 0xe8, 0x89, 0x2b, 0x9c, 0x95, 0x91, 0x68, 0x07, 0xbe, 0x35, 0xc6, 0x01, 0xa6,
+	0xf8, 0x19, 0x9e, 0x44, 0x04, 0x3b, 0x62, 0x86, 0x6c, 0xe4, 0xca, 0x58, 0xe8, 0x73, 0x58, 0xf5,
+	0x24, 0xb6, 0x35, 0x8e, 0xd6, 0x77, 0x15, 0xb4, 0x54, 0x8d, 0x38, 0x21, 0x11, 0x77, 0xb2, 0x39,
+	0xac, 0x36, 0x8e, 0xfd, 0xc9, 0x24, 0x73, 0x8c, 0x20, 0xd0, 0x2b, 0xe8, 0x8c, 0x31, 0x75, 0xfd,
+	0x00, 0x8f, 0x39, 0xa0, 0x0d, 0x1e, 0xbf, 0x3f, 0x58, 0xba, 0xb2, 0x22, 0x2b, 0x2a, 0x99, 0x36,
+	0x9d, 0x1d, 0x34, 0xef, 0xdc, 0x44, 0x95, 0xe2, 0x87, 0x64, 0xd3, 0x59, 0x1c, 0xb6, 0x7e, 0x01,
+	0x6b, 0x85, 0xc5, 0x4a, 0x0a, 0xd1, 0x67, 0x7a, 0x21, 0xda, 0x58, 0x12, 0x20, 0x6a, 0xd5, 0xf9,
+	0x1c, 0xda, 0x0a, 0x00, 0xa8, 0x0f, 0x9d, 0xe1, 0xe1, 0xc1, 0xc1, 0x9b, 0xd3, 0xa3, 0x17, 0x47,
+	0xaf, 0x7f, 0x7e, 0xd4, 0x5f, 0x41, 0x5d, 0x68, 0xf1, 0x91, 0xa3, 0xd7, 0x47, 0x2c, 0x20, 0x32,
+	0xf2, 0xe4, 0xf5, 0xab, 0x51, 0xbf, 0x62, 0xff, 0xd6, 0x80, 0xee, 0x3e, 0xc1, 0x2e, 0xc5, 0xcb,
+	0xab, 0xd6, 0x8f, 0x00, 0x64, 0x72, 0x8a, 0x33, 0xe0, 0xd2, 0xfc, 0x50, 0x44, 0x59, 0x3c, 0x50,
+	0x7f, 0x86, 0xa3, 0x94, 0x72, 0x4f, 0x1b, 0x4e, 0x46, 0x8a, 0x76, 0x43, 0xb4, 0xfd, 0xa2, 0x49,
+	0xcf, 0x48, 0xfb, 0x97, 0xd0, 0xcb, 0xf4, 0x91, 0x11, 0xb7, 0x98, 0xe7, 0xb7, 0x55, 0xc7, 0xfe,
+	0xbd, 0x01, 0x6d, 0x07, 0xbb, 0xe3, 0xeb, 0x17, 0x10, 0x7d, 0xab, 0xea, 0xf5, 0x2d, 0x9f, 0x57,
+	0xd5, 0xda, 0xb5, 0xaa, 0xaa, 0xfd, 0x1b, 0x03, 0x3a, 0x42, 0xb7, 0x0f, 0x6c, 0xb5, 0xa2, 0x4a,
+	0xf5, 0x7a, 0xaa, 0xfc, 0xcb, 0x80, 0xee, 0x69, 0x3c, 0x56, 0x42, 0xe2, 0xff, 0x59, 0x69, 0x95,
+	0x18, 0xaa, 0xeb, 0x31, 0x54, 0xa8, 0xc1, 0x8d, 0x92, 0x1a, 0xac, 0x46, 0xda, 0xaa, 0x1e, 0x69,
+	0x87, 0xd0, 0xcb, 0xcc, 0x94, 0x98, 0xeb, 0x18, 0x1b, 0xd7, 0x8f, 0xac, 0x5f, 0x1b, 0xd0, 0x1d,
+	0xf2, 0x22, 0xf6, 0x3f, 0x88, 0x2d, 0x05, 0x91, 0x9a, 0x86, 0x88, 0xfd, 0x87, 0x55, 0xfe, 0x54,
+	0x21, 0x5e, 0x46, 0x94, 0x67, 0x90, 0xac, 0xb3, 0x37, 0x96, 0x74, 0xf6, 0x15, 0xb5, 0xb3, 0x7f,
+	0x9a, 0x77, 0xf6, 0xa2, 0x2d, 0xfb, 0xbe, 0x7e, 0xf9, 0xd5, 0x16, 0x2f, 0x6d, 0xef, 0xe7, 0x2d,
+	0x7b, 0x6d, 0x69, 0xcb, 0x5e, 0xbf, 0xba, 0x65, 0x6f, 0x94, 0xb6, 0xec, 0xac, 0xd9, 0xa3, 0x17,
+	0x31, 0x96, 0xb7, 0x11, 0xfe, 0x9d, 0xdf, 0xa9, 0x9b, 0xca, 0x9d, 0x7a, 0x1d, 0x1a, 0xb1, 0x4b,
+	0x70, 0x48, 0xcd, 0x16, 0x1f, 0x95, 0x94, 0x92, 0x0e, 0x70, 0xbd, 0x7e, 0xe7, 0x2b, 0x58, 0xe3,
+	0x5f, 0x5a, 0x23, 0xdc, 0xe6, 0xd0, 0xec, 0x5e, 0x06, 0xcd, 0xe1, 0xe2, 0x24, 0x81, 0x52, 0x71,
+	0x31, 0xe9, 0x21, 0x8a, 0x3d, 0x71, 0x4f, 0x69, 0x3a, 0x19, 0xc9, 0x9e, 0x99, 0xb2, 0x9b, 0x5e,
+	0x62, 0x76, 0xcb, 0x9e, 0x99, 0xf4, 0x3d, 0x8f, 0x33, 0x61, 0xf9, 0xcc, 0x94, 0x4f, 0x66, 0x7b,
+	0xb8, 0x81, 0xef, 0x26, 0x38, 0x31, 0x7b, 0xe2, 0x68, 0x96, 0x24, 0xb2, 0xd9, 0x99, 0xa8, 0x98,
+	0x76, 0x87, 0xb3, 0xb5, 0xb1, 0xd2, 0x1b, 0x5b, 0xbf, 0xfc, 0xc6, 0xc6, 0xee, 0x54, 0xf9, 0x59,
+	0x75, 0x55, 0x97, 0x7e, 0xfb, 0x2b, 0x8e, 0x75, 0x0e, 0xeb, 0xe5, 0x08, 0x97, 0xac, 0x72, 0xa0,
+	0x1f, 0xab, 0x3b, 0x57, 0x40, 0x58, 0xd0, 0x5d, 0xdd, 0xf7, 0x09, 0xf4, 0x74, 0x94, 0x6f, 0x74,
+	0x31, 0xfb, 0xa6, 0x02, 0x6b, 0xca, 0x96, 0xb2, 0xee, 0x14, 0x8f, 0xdc, 0xcf, 0x78, 0x6a, 0x52,
+	0x7c, 0x55, 0xa1, 0x17, 0x52, 0xc8, 0x85, 0x35, 0xfe, 0x51, 0xf2, 0xf4, 0xf0, 0xb0, 0xdc, 0x58,
+	0xb1, 0xf3, 0xe0, 0x64, 0x71, 0x96, 0x0c, 0xd2, 0xc2, 0x6a, 0x37, 0x72, 0xeb, 0x7b, 0x58, 0x2f,
+	0x5f, 0xb8, 0x04, 0xab, 0xe7, 0xba, 0x6f, 0x7e, 0x78, 0xa9, 0xba, 0x57, 0x38, 0xc7, 0xfe, 0xb3,
+	0x01, 0x1b, 0xfc, 0x5d, 0x2e, 0x7b, 0x88, 0x3a, 0x0c, 0x7d, 0x7a, 0xc0, 0xdb, 0xae, 0x0f, 0x77,
+	0xa0, 0x9a, 0xb0, 0x2a, 0x6e, 0x24, 0x02, 0xe2, 0x96, 0x93, 0x91, 0x37, 0x3f, 0xf5, 0x77, 0xa0,
+	0x27, 0xd5, 0xa5, 0xe4, 0x82, 0x75, 0xca, 0xe8, 0x3b, 0x00, 0x84, 0x11, 0x7b, 0x13, 0x8a, 0x09,
+	0xd7, 0xd6, 0x70, 0x94, 0x91, 0xdd, 0x3f, 0x35, 0xa1, 0x9f, 0x19, 0x97, 0xc5, 0x21, 0x2b, 0x13,
+	0xf9, 0x7b, 0x36, 0xfa, 0x58, 0x41, 0x70, 0xf1, 0x4d, 0xdc, 0xda, 0x2c, 0x67, 0x0a, 0x78, 0xed,
+	0x15, 0xf4, 0x0c, 0xda, 0xfc, 0x9e, 0x26, 0xb2, 0x12, 0x15, 0x6e, 0x76, 0xd9, 0x3a, 0x66, 0x91,
+	0x91, 0xaf, 0xf1, 0x14, 0x80, 0x77, 0xa4, 0xf2, 0x34, 0x28, 0x34, 0xd7, 0x62, 0x85, 0x8d, 0x25,
+	0x4d, 0xb7, 0xbd, 0xc2, 0xcc, 0xc9, 0x5f, 0x59, 0x35, 0x73, 0x16, 0x9f, 0xd5, 0xad, 0xcd, 0x72,
+	0xa6, 0xa2, 0x4a, 0x43, 0xbc, 0x2b, 0x22, 0x55, 0x61, 0xed, 0x21, 0xd5, 0xba, 0x5b, 0xc2, 0xc9,
+	0x17, 0x78, 0x0e, 0x9d, 0x13, 0x4a, 0xb0, 0x3b, 0xfb, 0xaf, 0x96, 0xd9, 0x31, 0xd0, 0x23, 0xa8,
+	0xb1, 0xa7, 0x04, 0x0d, 0x0e, 0xe5, 0x31, 0xc8, 0xda, 0x28, 0x8c, 0xe7, 0x3a, 0x3c, 0x81, 0x3a,
+	0x87, 0xf8, 0x76, 0xde, 0x78, 0x04, 0x35, 0x7e, 0x57, 0xb9, 0x85, 0x1f, 0x9e, 0x42, 0x43, 0xb4,
+	0xe2, 0x9a, 0xd9, 0xda, 0x6d, 0xc1, 0xba, 0x5b, 0xc2, 0x51, 0xf7, 0x66, 0x3d, 0xad, 0xb6, 0xb7,
+	0xd2, 0x80, 0x5b, 0x1b, 0x85, 0x71, 0x75, 0x6f, 0xd1, 0x9c, 0x69, 0x7b, 0x6b, 0x6d, 0xa9, 0x75,
+	0xb7, 0x84, 0xa3, 0xa0, 0xd6, 0x10, 0x1d, 0x99, 0xb6, 0x80, 0xd6, 0xa4, 0x59, 0xeb, 0x85, 0xfc,
+	0x1c, 0xb1, 0x3f, 0x4e, 0x79, 0x08, 0x8a, 0xea, 0xb3, 0x18, 0x82, 0xda, 0x79, 0x61, 0x6d, 0x96,
+	0x33, 0x73, 0x3d, 0x1e, 0x43, 0x63, 0xdf, 0x0d, 0x3d, 0x1c, 0xa0, 0x25, 0xbb, 0x5d, 0xa2, 0xc5,
+	0x17, 0xd0, 0x7d, 0x8e, 0xe9, 0x31, 0x7f, 0xac, 0x3d, 0x0c, 0x27, 0xd1, 0xd2, 0x25, 0xbe, 0xad,
+	0x5e, 0x14, 0x73, 0x71, 0x7b, 0xe5, 0x6d, 0x83, 0x0b, 0x3e, 0xfc, 0xcf, 0x00, 0x86, 0x2b, 0x6c,
+	0xcf, 0x84, 0x1b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
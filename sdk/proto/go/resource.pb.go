@@ -317,6 +317,7 @@ type RegisterResourceRequest struct {
 	ReplaceOnChanges           []string                                                 `protobuf:"bytes,23,rep,name=replaceOnChanges,proto3" json:"replaceOnChanges,omitempty"`
 	PluginDownloadURL          string                                                   `protobuf:"bytes,24,opt,name=pluginDownloadURL,proto3" json:"pluginDownloadURL,omitempty"`
 	RetainOnDelete             bool                                                     `protobuf:"varint,25,opt,name=retainOnDelete,proto3" json:"retainOnDelete,omitempty"`
+	Retries                    int32                                                    `protobuf:"varint,26,opt,name=retries,proto3" json:"retries,omitempty"`
 	XXX_NoUnkeyedLiteral       struct{}                                                 `json:"-"`
 	XXX_unrecognized           []byte                                                   `json:"-"`
 	XXX_sizecache              int32                                                    `json:"-"`
@@ -522,6 +523,13 @@ func (m *RegisterResourceRequest) GetRetainOnDelete() bool {
 	return false
 }
 
+func (m *RegisterResourceRequest) GetRetries() int32 {
+	if m != nil {
+		return m.Retries
+	}
+	return 0
+}
+
 // PropertyDependencies describes the resources that a particular property depends on.
 type RegisterResourceRequest_PropertyDependencies struct {
 	Urns                 []string `protobuf:"bytes,1,rep,name=urns,proto3" json:"urns,omitempty"`
@@ -816,74 +824,74 @@ func init() {
 func init() { proto.RegisterFile("resource.proto", fileDescriptor_d1b72f771c35e3b8) }
 
 var fileDescriptor_d1b72f771c35e3b8 = []byte{
-	// 1058 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0xff, 0x6f, 0xdb, 0x44,
-	0x14, 0x6f, 0x92, 0x2e, 0x4d, 0x5e, 0xdb, 0xb4, 0xbb, 0x76, 0xc9, 0xd5, 0xa0, 0x52, 0x0c, 0x42,
-	0x61, 0x42, 0xe9, 0x5a, 0x90, 0xd6, 0xa1, 0x01, 0x12, 0xed, 0x40, 0x93, 0x18, 0x1d, 0x2e, 0x20,
-	0x40, 0x02, 0xe9, 0x1a, 0xbf, 0x66, 0xa6, 0x8e, 0xcf, 0xbb, 0x3b, 0x17, 0xe5, 0x37, 0xf8, 0x5b,
-	0xf8, 0x4f, 0x10, 0x3f, 0xf1, 0x57, 0xa1, 0xbb, 0xb3, 0x33, 0x3b, 0x76, 0xda, 0x74, 0xfb, 0xed,
-	0xde, 0xd7, 0xf3, 0x7b, 0xef, 0x73, 0x9f, 0x3b, 0x43, 0x47, 0xa0, 0xe4, 0x89, 0x18, 0xe2, 0x20,
-	0x16, 0x5c, 0x71, 0xd2, 0x8e, 0x93, 0x30, 0x19, 0x07, 0x22, 0x1e, 0x3a, 0x6f, 0x8d, 0x38, 0x1f,
-	0x85, 0xb8, 0x6f, 0x0c, 0xe7, 0xc9, 0xc5, 0x3e, 0x8e, 0x63, 0x35, 0xb1, 0x7e, 0xce, 0xdb, 0xb3,
-	0x46, 0xa9, 0x44, 0x32, 0x54, 0xa9, 0xb5, 0x13, 0x0b, 0x7e, 0x15, 0xf8, 0x28, 0xac, 0xec, 0xf6,
-	0xa1, 0x7b, 0x96, 0xc4, 0x31, 0x17, 0x4a, 0x7e, 0x85, 0x4c, 0x25, 0x02, 0x3d, 0x7c, 0x99, 0xa0,
-	0x54, 0xa4, 0x03, 0xf5, 0xc0, 0xa7, 0xb5, 0xbd, 0x5a, 0xbf, 0xed, 0xd5, 0x03, 0xdf, 0x7d, 0x04,
-	0xbd, 0x92, 0xa7, 0x8c, 0x79, 0x24, 0x91, 0xec, 0x02, 0xbc, 0x60, 0x32, 0xb5, 0x9a, 0x90, 0x96,
-	0x97, 0xd3, 0xb8, 0xff, 0x35, 0x60, 0xcb, 0x43, 0xe6, 0x7b, 0x69, 0x45, 0x73, 0xb6, 0x20, 0x04,
-	0x96, 0xd5, 0x24, 0x46, 0x5a, 0x37, 0x1a, 0xb3, 0xd6, 0xba, 0x88, 0x8d, 0x91, 0x36, 0xac, 0x4e,
-	0xaf, 0x49, 0x17, 0x9a, 0x31, 0x13, 0x18, 0x29, 0xba, 0x6c, 0xb4, 0xa9, 0x44, 0x1e, 0x02, 0xc4,
-	0x82, 0xc7, 0x28, 0x54, 0x80, 0x92, 0xde, 0xd9, 0xab, 0xf5, 0x57, 0x0f, 0x7b, 0x03, 0xdb, 0x8f,
-	0x41, 0xd6, 0x8f, 0xc1, 0x99, 0xe9, 0x87, 0x97, 0x73, 0x25, 0x2e, 0xac, 0xf9, 0x18, 0x63, 0xe4,
-	0x63, 0x34, 0xd4, 0xa1, 0xcd, 0xbd, 0x46, 0xbf, 0xed, 0x15, 0x74, 0xc4, 0x81, 0x56, 0xd6, 0x3b,
-	0xba, 0x62, 0xb6, 0x9d, 0xca, 0x84, 0xc2, 0xca, 0x15, 0x0a, 0x19, 0xf0, 0x88, 0xb6, 0x8c, 0x29,
-	0x13, 0xc9, 0xfb, 0xb0, 0xce, 0x86, 0x43, 0x8c, 0xd5, 0x19, 0x0e, 0x05, 0x2a, 0x49, 0xdb, 0xa6,
-	0x3b, 0x45, 0x25, 0x39, 0x82, 0x1e, 0xf3, 0xfd, 0x40, 0x05, 0x3c, 0x62, 0xa1, 0x55, 0x9e, 0x26,
-	0x2a, 0x4e, 0x94, 0xa4, 0x60, 0x3e, 0x65, 0x9e, 0x59, 0xef, 0xcc, 0xc2, 0x80, 0x49, 0x94, 0x74,
-	0xd5, 0x78, 0x66, 0x22, 0xe9, 0xc3, 0x86, 0xdd, 0x24, 0xeb, 0xba, 0xa4, 0x6b, 0x66, 0xef, 0x59,
-	0x35, 0xf9, 0x08, 0xee, 0xc6, 0x61, 0x32, 0x0a, 0xa2, 0x13, 0xfe, 0x47, 0x14, 0x72, 0xe6, 0xff,
-	0xe0, 0x7d, 0x43, 0xd7, 0x4d, 0x1d, 0x65, 0x83, 0xcb, 0x60, 0xbb, 0x38, 0xcb, 0x14, 0x04, 0x9b,
-	0xd0, 0x48, 0x44, 0x94, 0x4e, 0x53, 0x2f, 0x67, 0xc6, 0x51, 0x5f, 0x78, 0x1c, 0xee, 0xdf, 0xab,
-	0xd0, 0xf3, 0x70, 0x14, 0x48, 0x85, 0x62, 0x16, 0x33, 0x19, 0x46, 0x6a, 0x15, 0x18, 0xa9, 0x57,
-	0x62, 0xa4, 0x51, 0xc0, 0x48, 0x17, 0x9a, 0xc3, 0x44, 0x2a, 0x3e, 0x36, 0xd8, 0x69, 0x79, 0xa9,
-	0x44, 0xf6, 0xa1, 0xc9, 0xcf, 0x7f, 0xc7, 0xa1, 0xba, 0x09, 0x37, 0xa9, 0x9b, 0xee, 0xbc, 0x36,
-	0xe9, 0x88, 0xa6, 0xc9, 0x94, 0x89, 0x25, 0x34, 0xad, 0xdc, 0x80, 0xa6, 0xd6, 0x0c, 0x9a, 0x62,
-	0xd8, 0x4e, 0x9b, 0x31, 0x39, 0xc9, 0xe7, 0x69, 0xef, 0x35, 0xfa, 0xab, 0x87, 0x8f, 0x07, 0x53,
-	0x22, 0x18, 0xcc, 0x69, 0xd2, 0xe0, 0x79, 0x45, 0xf8, 0x93, 0x48, 0x89, 0x89, 0x57, 0x99, 0x99,
-	0x3c, 0x80, 0x2d, 0x1f, 0x43, 0x54, 0xf8, 0x25, 0x5e, 0x70, 0x7d, 0xb0, 0xe3, 0x90, 0x0d, 0x91,
-	0x82, 0xa9, 0xab, 0xca, 0x94, 0x47, 0xfc, 0x6a, 0x09, 0xf1, 0xc1, 0x28, 0xe2, 0x02, 0x8f, 0x5f,
-	0xb0, 0x68, 0x64, 0x50, 0xa7, 0xcb, 0x2f, 0x2a, 0xcb, 0xe7, 0x62, 0xfd, 0x96, 0xe7, 0xa2, 0xb3,
-	0xf0, 0xb9, 0xd8, 0x28, 0x9e, 0x0b, 0x07, 0x5a, 0xc1, 0x58, 0xd3, 0xd2, 0x53, 0x9f, 0x6e, 0xda,
-	0xce, 0x67, 0x32, 0xf9, 0x19, 0x3a, 0x16, 0x0e, 0xdf, 0x07, 0x63, 0xe4, 0x7a, 0x9b, 0xbb, 0x06,
-	0x0c, 0x07, 0x0b, 0xf4, 0xfc, 0xb8, 0x10, 0xe8, 0xcd, 0x24, 0x22, 0x9f, 0x83, 0x53, 0xd1, 0xc7,
-	0x13, 0xbc, 0x08, 0x22, 0xf4, 0x29, 0x31, 0xd5, 0x5f, 0xe3, 0x41, 0x3e, 0x81, 0x7b, 0x32, 0xa5,
-	0xdf, 0xe7, 0x4c, 0xa8, 0x80, 0x85, 0x3f, 0xb2, 0x30, 0x41, 0x49, 0xb7, 0x4c, 0x68, 0xb5, 0x51,
-	0xa3, 0x5d, 0xe0, 0x98, 0x2b, 0xa4, 0xdb, 0x16, 0xed, 0x56, 0xaa, 0x22, 0x87, 0x7b, 0xd5, 0xe4,
-	0x70, 0x0a, 0xed, 0x0c, 0x98, 0x92, 0x76, 0x0d, 0x02, 0x0f, 0x16, 0x43, 0xa0, 0x8d, 0xb1, 0xb0,
-	0x7b, 0x95, 0x83, 0xdc, 0x87, 0x4d, 0x61, 0x4b, 0x3b, 0x8d, 0x32, 0x88, 0xf4, 0xcc, 0x88, 0x4a,
-	0xfa, 0x6a, 0x66, 0xa2, 0x73, 0x98, 0x89, 0x7c, 0xa0, 0xef, 0x4c, 0xc5, 0x82, 0xe8, 0x34, 0x3a,
-	0x31, 0x8d, 0xa4, 0x3b, 0xa6, 0xa6, 0x19, 0xad, 0x73, 0x1f, 0xb6, 0xab, 0x0e, 0x88, 0xa6, 0x91,
-	0x44, 0x44, 0x92, 0xd6, 0xcc, 0xd7, 0x98, 0xb5, 0xf3, 0x13, 0x74, 0x8a, 0x83, 0x35, 0x04, 0x22,
-	0x90, 0xa9, 0x8c, 0x82, 0x52, 0x49, 0xeb, 0x93, 0xd8, 0xd7, 0x7a, 0x4b, 0x43, 0xa9, 0xa4, 0xf5,
-	0x76, 0xac, 0x19, 0x11, 0x59, 0xc9, 0xf9, 0xb3, 0x06, 0x3b, 0x73, 0xcf, 0xa9, 0x66, 0xd3, 0x4b,
-	0x9c, 0x64, 0x6c, 0x7a, 0x89, 0x13, 0xf2, 0x0c, 0xee, 0x5c, 0xe9, 0xa1, 0xa6, 0x44, 0xfa, 0xf0,
-	0x35, 0x69, 0xc0, 0xb3, 0x59, 0x3e, 0xad, 0x1f, 0xd5, 0x9c, 0xc7, 0xd0, 0x29, 0xce, 0xa9, 0x62,
-	0xdb, 0xed, 0xfc, 0xb6, 0xed, 0x5c, 0xb4, 0xfb, 0x4f, 0x03, 0x68, 0x79, 0xe7, 0xb9, 0xb7, 0x81,
-	0xbd, 0xec, 0xeb, 0xd3, 0xcb, 0xfe, 0x15, 0xe1, 0x36, 0x16, 0x23, 0xdc, 0x2e, 0x34, 0xa5, 0x62,
-	0xe7, 0x21, 0x66, 0xcc, 0x6d, 0x25, 0x7d, 0xd4, 0xed, 0x4a, 0x5f, 0xf9, 0xe6, 0xa8, 0xa7, 0x22,
-	0x79, 0x39, 0x87, 0x48, 0x9b, 0x06, 0xc6, 0x9f, 0x5d, 0xdb, 0x41, 0x5b, 0xc7, 0x6d, 0x99, 0xf4,
-	0x56, 0xd8, 0xfa, 0xeb, 0x96, 0x08, 0xf8, 0xb6, 0x88, 0x80, 0xa3, 0xd7, 0xfd, 0xfe, 0xfc, 0x10,
-	0x11, 0x76, 0x67, 0x63, 0x53, 0x0a, 0xcd, 0x2e, 0xdc, 0xf2, 0x24, 0x0f, 0x60, 0x85, 0xa7, 0x2c,
-	0x7c, 0xc3, 0xa5, 0x9e, 0xf9, 0x1d, 0xfe, 0xbb, 0x0c, 0x1b, 0x59, 0xfe, 0x67, 0x3c, 0x0a, 0x14,
-	0x17, 0xe4, 0x17, 0xd8, 0x98, 0x79, 0x50, 0x92, 0x77, 0x73, 0x25, 0x55, 0x3f, 0x4b, 0x1d, 0xf7,
-	0x3a, 0x17, 0x5b, 0xb4, 0xbb, 0x44, 0xbe, 0x80, 0xe6, 0xd3, 0xe8, 0x8a, 0x5f, 0x22, 0xa1, 0x39,
-	0x7f, 0xab, 0xca, 0x32, 0xed, 0x54, 0x58, 0xa6, 0x09, 0xbe, 0x86, 0xb5, 0x33, 0x25, 0x90, 0x8d,
-	0xdf, 0x28, 0xcd, 0x83, 0x1a, 0x79, 0x04, 0xcb, 0xc7, 0x2c, 0x0c, 0x49, 0x37, 0xe7, 0xa6, 0x15,
-	0x59, 0x78, 0xaf, 0xa4, 0x9f, 0x7e, 0xc3, 0x77, 0xb0, 0x96, 0x7f, 0x69, 0x91, 0xdd, 0xc2, 0xc0,
-	0x4b, 0xcf, 0x69, 0xe7, 0x9d, 0xb9, 0xf6, 0x69, 0xca, 0x5f, 0x61, 0x73, 0x76, 0xdc, 0xc4, 0xbd,
-	0x99, 0x49, 0x9c, 0xf7, 0x16, 0xc0, 0x9a, 0xbb, 0x44, 0x7e, 0x2b, 0xbf, 0xdb, 0xb2, 0x0b, 0xf9,
-	0xc3, 0x6b, 0x32, 0x14, 0x11, 0xe7, 0x74, 0x4b, 0x70, 0x7a, 0xa2, 0xff, 0x6f, 0xdc, 0xa5, 0xf3,
-	0xa6, 0xd1, 0x7c, 0xfc, 0x7f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x2c, 0x4f, 0xf5, 0x90, 0x1c, 0x0d,
-	0x00, 0x00,
+	// 1068 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0x5d, 0x6f, 0x1b, 0x45,
+	0x17, 0xae, 0xed, 0xd4, 0xb1, 0x4f, 0x12, 0x27, 0x9d, 0xa4, 0xf6, 0x64, 0xdf, 0x57, 0xc1, 0x2c,
+	0x08, 0x99, 0x0a, 0x39, 0x6d, 0x40, 0x6a, 0x8a, 0x0a, 0x48, 0x24, 0x05, 0x55, 0xa2, 0x24, 0x6c,
+	0x00, 0x01, 0x12, 0x48, 0x13, 0xef, 0x89, 0xbb, 0x64, 0xbd, 0xb3, 0x9d, 0x9d, 0x0d, 0xf2, 0x1d,
+	0xfc, 0x30, 0x6e, 0x10, 0x57, 0xfc, 0x2a, 0x34, 0x33, 0x3b, 0xee, 0xae, 0x77, 0x9d, 0x38, 0xe5,
+	0x6e, 0xce, 0xb7, 0xcf, 0x39, 0xcf, 0x3c, 0x3b, 0x86, 0x8e, 0xc0, 0x84, 0xa7, 0x62, 0x84, 0xc3,
+	0x58, 0x70, 0xc9, 0x49, 0x3b, 0x4e, 0xc3, 0x74, 0x12, 0x88, 0x78, 0xe4, 0xfc, 0x6f, 0xcc, 0xf9,
+	0x38, 0xc4, 0x7d, 0x6d, 0x38, 0x4f, 0x2f, 0xf6, 0x71, 0x12, 0xcb, 0xa9, 0xf1, 0x73, 0xfe, 0x3f,
+	0x6f, 0x4c, 0xa4, 0x48, 0x47, 0x32, 0xb3, 0x76, 0x62, 0xc1, 0xaf, 0x02, 0x1f, 0x85, 0x91, 0xdd,
+	0x01, 0x74, 0xcf, 0xd2, 0x38, 0xe6, 0x42, 0x26, 0x5f, 0x20, 0x93, 0xa9, 0x40, 0x0f, 0x5f, 0xa5,
+	0x98, 0x48, 0xd2, 0x81, 0x7a, 0xe0, 0xd3, 0x5a, 0xbf, 0x36, 0x68, 0x7b, 0xf5, 0xc0, 0x77, 0x9f,
+	0x40, 0xaf, 0xe4, 0x99, 0xc4, 0x3c, 0x4a, 0x90, 0xec, 0x01, 0xbc, 0x64, 0x49, 0x66, 0xd5, 0x21,
+	0x2d, 0x2f, 0xa7, 0x71, 0xff, 0x69, 0xc0, 0xb6, 0x87, 0xcc, 0xf7, 0xb2, 0x8e, 0x16, 0x94, 0x20,
+	0x04, 0x56, 0xe4, 0x34, 0x46, 0x5a, 0xd7, 0x1a, 0x7d, 0x56, 0xba, 0x88, 0x4d, 0x90, 0x36, 0x8c,
+	0x4e, 0x9d, 0x49, 0x17, 0x9a, 0x31, 0x13, 0x18, 0x49, 0xba, 0xa2, 0xb5, 0x99, 0x44, 0x1e, 0x03,
+	0xc4, 0x82, 0xc7, 0x28, 0x64, 0x80, 0x09, 0xbd, 0xdb, 0xaf, 0x0d, 0xd6, 0x0e, 0x7a, 0x43, 0x33,
+	0x8f, 0xa1, 0x9d, 0xc7, 0xf0, 0x4c, 0xcf, 0xc3, 0xcb, 0xb9, 0x12, 0x17, 0xd6, 0x7d, 0x8c, 0x31,
+	0xf2, 0x31, 0x1a, 0xa9, 0xd0, 0x66, 0xbf, 0x31, 0x68, 0x7b, 0x05, 0x1d, 0x71, 0xa0, 0x65, 0x67,
+	0x47, 0x57, 0x75, 0xd9, 0x99, 0x4c, 0x28, 0xac, 0x5e, 0xa1, 0x48, 0x02, 0x1e, 0xd1, 0x96, 0x36,
+	0x59, 0x91, 0xbc, 0x0b, 0x1b, 0x6c, 0x34, 0xc2, 0x58, 0x9e, 0xe1, 0x48, 0xa0, 0x4c, 0x68, 0x5b,
+	0x4f, 0xa7, 0xa8, 0x24, 0x87, 0xd0, 0x63, 0xbe, 0x1f, 0xc8, 0x80, 0x47, 0x2c, 0x34, 0xca, 0x93,
+	0x54, 0xc6, 0xa9, 0x4c, 0x28, 0xe8, 0x9f, 0xb2, 0xc8, 0xac, 0x2a, 0xb3, 0x30, 0x60, 0x09, 0x26,
+	0x74, 0x4d, 0x7b, 0x5a, 0x91, 0x0c, 0x60, 0xd3, 0x14, 0xb1, 0x53, 0x4f, 0xe8, 0xba, 0xae, 0x3d,
+	0xaf, 0x26, 0x1f, 0xc0, 0xbd, 0x38, 0x4c, 0xc7, 0x41, 0x74, 0xcc, 0x7f, 0x8b, 0x42, 0xce, 0xfc,
+	0xef, 0xbc, 0xaf, 0xe8, 0x86, 0xee, 0xa3, 0x6c, 0x70, 0x19, 0xec, 0x14, 0x77, 0x99, 0x81, 0x60,
+	0x0b, 0x1a, 0xa9, 0x88, 0xb2, 0x6d, 0xaa, 0xe3, 0xdc, 0x3a, 0xea, 0x4b, 0xaf, 0xc3, 0xfd, 0x73,
+	0x0d, 0x7a, 0x1e, 0x8e, 0x83, 0x44, 0xa2, 0x98, 0xc7, 0x8c, 0xc5, 0x48, 0xad, 0x02, 0x23, 0xf5,
+	0x4a, 0x8c, 0x34, 0x0a, 0x18, 0xe9, 0x42, 0x73, 0x94, 0x26, 0x92, 0x4f, 0x34, 0x76, 0x5a, 0x5e,
+	0x26, 0x91, 0x7d, 0x68, 0xf2, 0xf3, 0x5f, 0x71, 0x24, 0x6f, 0xc2, 0x4d, 0xe6, 0xa6, 0x26, 0xaf,
+	0x4c, 0x2a, 0xa2, 0xa9, 0x33, 0x59, 0xb1, 0x84, 0xa6, 0xd5, 0x1b, 0xd0, 0xd4, 0x9a, 0x43, 0x53,
+	0x0c, 0x3b, 0xd9, 0x30, 0xa6, 0xc7, 0xf9, 0x3c, 0xed, 0x7e, 0x63, 0xb0, 0x76, 0xf0, 0x74, 0x38,
+	0x23, 0x82, 0xe1, 0x82, 0x21, 0x0d, 0x4f, 0x2b, 0xc2, 0x9f, 0x45, 0x52, 0x4c, 0xbd, 0xca, 0xcc,
+	0xe4, 0x21, 0x6c, 0xfb, 0x18, 0xa2, 0xc4, 0xcf, 0xf1, 0x82, 0x0b, 0xf4, 0x30, 0x0e, 0xd9, 0x08,
+	0x29, 0xe8, 0xbe, 0xaa, 0x4c, 0x79, 0xc4, 0xaf, 0x95, 0x10, 0x1f, 0x8c, 0x23, 0x2e, 0xf0, 0xe8,
+	0x25, 0x8b, 0xc6, 0x1a, 0x75, 0xaa, 0xfd, 0xa2, 0xb2, 0x7c, 0x2f, 0x36, 0x6e, 0x79, 0x2f, 0x3a,
+	0x4b, 0xdf, 0x8b, 0xcd, 0xe2, 0xbd, 0x70, 0xa0, 0x15, 0x4c, 0x62, 0x2e, 0xe4, 0x73, 0x9f, 0x6e,
+	0x99, 0xc9, 0x5b, 0x99, 0xfc, 0x08, 0x1d, 0x03, 0x87, 0x6f, 0x83, 0x09, 0x72, 0x55, 0xe6, 0x9e,
+	0x06, 0xc3, 0xa3, 0x25, 0x66, 0x7e, 0x54, 0x08, 0xf4, 0xe6, 0x12, 0x91, 0x4f, 0xc1, 0xa9, 0x98,
+	0xe3, 0x31, 0x5e, 0x04, 0x11, 0xfa, 0x94, 0xe8, 0xee, 0xaf, 0xf1, 0x20, 0x1f, 0xc1, 0xfd, 0x24,
+	0xa3, 0xdf, 0x53, 0x26, 0x64, 0xc0, 0xc2, 0xef, 0x59, 0x98, 0x62, 0x42, 0xb7, 0x75, 0x68, 0xb5,
+	0x51, 0xa1, 0x5d, 0xe0, 0x84, 0x4b, 0xa4, 0x3b, 0x06, 0xed, 0x46, 0xaa, 0x22, 0x87, 0xfb, 0xd5,
+	0xe4, 0x70, 0x02, 0x6d, 0x0b, 0xcc, 0x84, 0x76, 0xfb, 0x8d, 0x25, 0xa7, 0x71, 0x6a, 0x63, 0x0c,
+	0xec, 0x5e, 0xe7, 0x20, 0x0f, 0x60, 0x4b, 0x98, 0xd6, 0x4e, 0x22, 0x0b, 0x91, 0x9e, 0x5e, 0x51,
+	0x49, 0x5f, 0xcd, 0x4c, 0x74, 0x01, 0x33, 0x91, 0xf7, 0xd4, 0x37, 0x53, 0xb2, 0x20, 0x3a, 0x89,
+	0x8e, 0xf5, 0x20, 0xe9, 0xae, 0xee, 0x69, 0x4e, 0xab, 0xb0, 0x21, 0x50, 0x0a, 0x75, 0xa5, 0x9c,
+	0x7e, 0x6d, 0x70, 0xd7, 0xb3, 0xa2, 0xf3, 0x00, 0x76, 0xaa, 0xae, 0x8e, 0x22, 0x98, 0x54, 0x44,
+	0x09, 0xad, 0xe9, 0xdf, 0xa9, 0xcf, 0xce, 0x0f, 0xd0, 0x29, 0xae, 0x5c, 0x53, 0x8b, 0x40, 0x26,
+	0x2d, 0x39, 0x65, 0x92, 0xd2, 0xa7, 0xb1, 0xcf, 0xa4, 0x25, 0xa8, 0x4c, 0x52, 0x7a, 0xb3, 0x70,
+	0x4b, 0x51, 0x46, 0x72, 0x7e, 0xaf, 0xc1, 0xee, 0xc2, 0x1b, 0xac, 0x78, 0xf6, 0x12, 0xa7, 0x96,
+	0x67, 0x2f, 0x71, 0x4a, 0x5e, 0xc0, 0xdd, 0x2b, 0xb5, 0xee, 0x8c, 0x62, 0x1f, 0xbf, 0x21, 0x41,
+	0x78, 0x26, 0xcb, 0xc7, 0xf5, 0xc3, 0x9a, 0xf3, 0x14, 0x3a, 0xc5, 0x0d, 0x56, 0x94, 0xdd, 0xc9,
+	0x97, 0x6d, 0xe7, 0xa2, 0xdd, 0xbf, 0x1a, 0x40, 0xcb, 0x95, 0x17, 0x7e, 0x27, 0xcc, 0x33, 0xa0,
+	0x3e, 0x7b, 0x06, 0xbc, 0xa6, 0xe2, 0xc6, 0x72, 0x54, 0xdc, 0x85, 0x66, 0x22, 0xd9, 0x79, 0x88,
+	0x96, 0xd3, 0x8d, 0xa4, 0x16, 0x6d, 0x4e, 0xea, 0x31, 0xa0, 0x49, 0x20, 0x13, 0xc9, 0xab, 0x05,
+	0x14, 0xdb, 0xd4, 0x00, 0xff, 0xe4, 0xda, 0x09, 0x9a, 0x3e, 0x6e, 0xcb, 0xb1, 0xb7, 0xc2, 0xd6,
+	0x1f, 0xb7, 0x44, 0xc0, 0xd7, 0x45, 0x04, 0x1c, 0xbe, 0xe9, 0xef, 0xcf, 0x2f, 0x11, 0x61, 0x6f,
+	0x3e, 0x36, 0x23, 0x57, 0xfb, 0x29, 0x2e, 0x6f, 0xf2, 0x11, 0xac, 0xf2, 0x8c, 0x9f, 0x6f, 0xf8,
+	0xdc, 0x5b, 0xbf, 0x83, 0xbf, 0x57, 0x60, 0xd3, 0xe6, 0x7f, 0xc1, 0xa3, 0x40, 0x72, 0x41, 0x7e,
+	0x82, 0xcd, 0xb9, 0xa7, 0x26, 0x79, 0x3b, 0xd7, 0x52, 0xf5, 0x83, 0xd5, 0x71, 0xaf, 0x73, 0x31,
+	0x4d, 0xbb, 0x77, 0xc8, 0x67, 0xd0, 0x7c, 0x1e, 0x5d, 0xf1, 0x4b, 0x24, 0x34, 0xe7, 0x6f, 0x54,
+	0x36, 0xd3, 0x6e, 0x85, 0x65, 0x96, 0xe0, 0x4b, 0x58, 0x3f, 0x93, 0x02, 0xd9, 0xe4, 0x3f, 0xa5,
+	0x79, 0x58, 0x23, 0x4f, 0x60, 0xe5, 0x88, 0x85, 0x21, 0xe9, 0xe6, 0xdc, 0x94, 0xc2, 0x86, 0xf7,
+	0x4a, 0xfa, 0xd9, 0x6f, 0xf8, 0x06, 0xd6, 0xf3, 0x6f, 0x30, 0xb2, 0x57, 0x58, 0x78, 0xe9, 0xa1,
+	0xed, 0xbc, 0xb5, 0xd0, 0x3e, 0x4b, 0xf9, 0x33, 0x6c, 0xcd, 0xaf, 0x9b, 0xb8, 0x37, 0x33, 0x89,
+	0xf3, 0xce, 0x12, 0x58, 0x73, 0xef, 0x90, 0x5f, 0xa0, 0xb7, 0x00, 0x4d, 0xe4, 0xfd, 0x6b, 0x32,
+	0x14, 0x11, 0xe7, 0x74, 0x4b, 0x70, 0x7a, 0xa6, 0xfe, 0xf9, 0xb8, 0x77, 0xce, 0x9b, 0x5a, 0xf3,
+	0xe1, 0xbf, 0x03, 0x00, 0xd5, 0x36, 0x18, 0xfb, 0x36, 0x0d, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -487,6 +487,13 @@ class ResourceOptions:
     If set to True, the providers Delete method will not be called for this resource.
     """
 
+    retries: Optional[int]
+    """
+    The number of times the engine should retry an operation on this resource that fails with an error the provider
+    reports as retryable, such as a throttling error. This overrides the deployment's default. Deleting a resource that
+    has been removed from the program uses the deployment's default, as this option is not recorded in the stack's state.
+    """
+
     # pylint: disable=redefined-builtin
     def __init__(
         self,
@@ -512,6 +519,7 @@ class ResourceOptions:
         replace_on_changes: Optional[List[str]] = None,
         plugin_download_url: Optional[str] = None,
         retain_on_delete: Optional[bool] = None,
+        retries: Optional[int] = None,
     ) -> None:
         """
         :param Optional[Resource] parent: If provided, the currently-constructing resource should be the child of
@@ -552,6 +560,8 @@ class ResourceOptions:
                from the provided url. This url overrides the plugin download url inferred from the current package and should
                rarely be used.
         :param Optional[bool] retain_on_delete: If set to True, the providers Delete method will not be called for this resource.
+        :param Optional[int] retries: The number of times the engine should retry an operation on this resource that fails
+               with an error the provider reports as retryable. This overrides the deployment's default.
         """
 
         # Expose 'merge' again this this object, but this time as an instance method.
@@ -577,6 +587,7 @@ class ResourceOptions:
         self.replace_on_changes = replace_on_changes
         self.depends_on = depends_on
         self.retain_on_delete = retain_on_delete
+        self.retries = retries
 
         # Proactively check that `depends_on` values are of type
         # `Resource`. We cannot complete the check in the general case
@@ -704,6 +715,7 @@ class ResourceOptions:
             if source.retain_on_delete is None
             else source.retain_on_delete
         )
+        dest.retries = dest.retries if source.retries is None else source.retries
 
         # Now, if we are left with a .providers that is just a single key/value pair, then
         # collapse that down into .provider form.
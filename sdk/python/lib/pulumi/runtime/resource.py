@@ -584,6 +584,7 @@ def register_resource(
                 remote=remote,
                 replaceOnChanges=replace_on_changes,
                 retainOnDelete=opts.retain_on_delete or False,
+                retries=opts.retries or 0,
             )
 
             from ..resource import create_urn  # pylint: disable=import-outside-toplevel